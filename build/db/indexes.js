@@ -1,3 +1,35 @@
 db = db.getSiblingDB('default'); 
 db.createCollection("user");
-db.user.createIndex({ "email": 1 }, { unique: true });
\ No newline at end of file
+// deleted users can have their email moved out, see DELETED_EMAIL_POLICY
+// existing databases need build/db/migrations/001_partial_email_index.js
+db.user.createIndex({ "email": 1 }, { unique: true, partialFilterExpression: { "email": { "$exists": true } } });
+db.user.createIndex({ "deleted_at": 1 }, { sparse: true });
+// admin user directory filters
+db.user.createIndex({ "created_at": 1 });
+db.user.createIndex({ "email_verified": 1, "_id": 1 });
+// name prefixes are searched on lowercase copies, see 002_lowercase_names.js
+db.user.createIndex({ "first_name_lower": 1 });
+db.user.createIndex({ "last_name_lower": 1 });
+// users waiting for a screening retry, see PLD_OUTAGE_POLICY, or for a compliance review
+db.user.createIndex({ "screening_status": 1, "_id": 1 });
+db.createCollection("refresh_token");
+db.refresh_token.createIndex({ "family_id": 1 });
+// revoking every session of a user, or all but the current one
+db.refresh_token.createIndex({ "user_id": 1, "family_id": 1 });
+db.refresh_token.createIndex({ "expires_at": 1 }, { expireAfterSeconds: 0 });
+db.createCollection("revoked_token");
+// the per user token generations have no expires_at and are kept
+db.revoked_token.createIndex({ "expires_at": 1 }, { expireAfterSeconds: 0 });
+db.createCollection("session");
+db.session.createIndex({ "user_id": 1, "last_seen_at": -1 });
+db.session.createIndex({ "expires_at": 1 }, { expireAfterSeconds: 0 });
+db.createCollection("one_time_token");
+db.one_time_token.createIndex({ "user_id": 1, "purpose": 1 });
+db.one_time_token.createIndex({ "expires_at": 1 }, { expireAfterSeconds: 0 });
+db.createCollection("login_attempt");
+db.login_attempt.createIndex({ "expires_at": 1 }, { expireAfterSeconds: 0 });
+db.createCollection("audit_log");
+db.audit_log.createIndex({ "user_id": 1, "created_at": -1 });
+db.createCollection("pld_screening");
+db.pld_screening.createIndex({ "user_id": 1, "_id": -1 });
+db.pld_screening.createIndex({ "email_hash": 1, "_id": -1 });
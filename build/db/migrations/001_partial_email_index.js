@@ -0,0 +1,11 @@
+// Databases created before DELETED_EMAIL_POLICY have a unique index on every email,
+// and released emails ($unset) collide on it. Stop the service before running it,
+// emails are not unique between the drop and the new index. Running it again is a no-op.
+db = db.getSiblingDB('default');
+const index = db.user.getIndexes().find((index) => index.name === "email_1");
+if (index && !index.partialFilterExpression) {
+    db.user.dropIndex("email_1");
+    print("dropped the unique email index");
+}
+db.user.createIndex({ "email": 1 }, { unique: true, partialFilterExpression: { "email": { "$exists": true } } });
+print("the unique email index only covers users with an email");
@@ -0,0 +1,31 @@
+// The name search runs on lowercase copies of the names, so the anchored prefix
+// regex can use their indexes. Fills them in for the users created before them and
+// swaps the name indexes. Running it again is a no-op.
+db = db.getSiblingDB('default');
+let updated = 0;
+db.user.find({
+    "$or": [
+        { "first_name": { "$exists": true }, "first_name_lower": { "$exists": false } },
+        { "last_name": { "$exists": true }, "last_name_lower": { "$exists": false } },
+    ],
+}).forEach((user) => {
+    const set = {};
+    if (user.first_name) {
+        set.first_name_lower = user.first_name.toLowerCase();
+    }
+    if (user.last_name) {
+        set.last_name_lower = user.last_name.toLowerCase();
+    }
+    if (Object.keys(set).length > 0) {
+        db.user.updateOne({ "_id": user._id }, { "$set": set });
+        updated++;
+    }
+});
+print(`filled in the lowercase names of ${updated} users`);
+db.user.createIndex({ "first_name_lower": 1 });
+db.user.createIndex({ "last_name_lower": 1 });
+for (const name of ["first_name_1", "last_name_1"]) {
+    if (db.user.getIndexes().some((index) => index.name === name)) {
+        db.user.dropIndex(name);
+    }
+}
@@ -1,18 +1,222 @@
 package main
 
-import "os"
+import (
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/application"
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/christhianjesus/crabi-challenge/internal/infrastructure"
+)
 
 type Context struct {
-	jwtKey   string
-	httpPort string
-	mongoURL string
-	pldURL   string
+	jwtKey          string
+	jwtSigningKey   string
+	jwtVerifyKeys   string
+	jwtIssuer       string
+	jwtAudience     string
+	accessTokenTTL  string
+	refreshTokenTTL string
+	resetTokenTTL   string
+	passwordHistory string
+	passwordMinLen  string
+	passwordScore   string
+	breachedDir     string
+	hashAlgorithm   string
+	bcryptCost      string
+	argon2Memory    string
+	argon2Time      string
+	argon2Threads   string
+	hashConcurrency string
+	hashQueueDepth  string
+	hashRetryAfter  string
+	verifyTokenTTL  string
+	verifyResend    string
+	unverifiedLogin string
+	totpIssuer      string
+	mfaChallengeTTL string
+	lockThreshold   string
+	lockIPThreshold string
+	lockBaseDelay   string
+	lockMaxDelay    string
+	attemptWindow   string
+	adminUserIDs    string
+	deletedEmail    string
+	retention       string
+	purgeInterval   string
+	trustProxy      string
+	notifyFile      string
+	httpPort        string
+	mongoURL        string
+	pldURL          string
+	pldTimeout      string
+	pldRetries      string
+	pldRetryDelay   string
+	pldBreakerLimit string
+	pldBreakerWait  string
+	pldOutage       string
+	pldThreshold    string
+	screeningRetry  string
 }
 
 func (c *Context) GetJwtKey() []byte {
 	return []byte(c.jwtKey)
 }
 
+func (c *Context) GetJwtSigningKeyFile() string {
+	return c.jwtSigningKey
+}
+
+func (c *Context) GetJwtVerificationKeyFiles() []string {
+	return splitList(c.jwtVerifyKeys)
+}
+
+func (c *Context) GetJwtIssuer() string {
+	if c.jwtIssuer == "" {
+		return "crabi-challenge"
+	}
+
+	return c.jwtIssuer
+}
+
+func (c *Context) GetJwtAudience() string {
+	if c.jwtAudience == "" {
+		return "crabi-challenge"
+	}
+
+	return c.jwtAudience
+}
+
+func (c *Context) GetAccessTokenTTL() time.Duration {
+	return parseDuration(c.accessTokenTTL, 15*time.Minute)
+}
+
+func (c *Context) GetRefreshTokenTTL() time.Duration {
+	return parseDuration(c.refreshTokenTTL, 30*24*time.Hour)
+}
+
+func (c *Context) GetResetTokenTTL() time.Duration {
+	return parseDuration(c.resetTokenTTL, time.Hour)
+}
+
+func (c *Context) GetPasswordHistorySize() int {
+	return parseInt(c.passwordHistory, 5)
+}
+
+func (c *Context) GetPasswordPolicy() application.PasswordPolicy {
+	return application.PasswordPolicy{
+		MinLength: parseInt(c.passwordMinLen, 8),
+		MaxLength: 72,
+		MinScore:  min(parseInt(c.passwordScore, 3), 4),
+	}
+}
+
+func (c *Context) GetBreachedPasswordsDir() string {
+	return c.breachedDir
+}
+
+func (c *Context) GetPasswordHashPolicy() domain.PasswordHashPolicy {
+	algorithm := domain.PasswordHashAlgorithm(c.hashAlgorithm)
+	if algorithm != domain.BcryptHash {
+		algorithm = domain.Argon2idHash
+	}
+
+	return domain.PasswordHashPolicy{
+		Algorithm: algorithm,
+		// bcrypt only takes costs from 4 to 31
+		BcryptCost:        min(max(parseInt(c.bcryptCost, 10), 4), 31),
+		Argon2Memory:      uint32(parseInt(c.argon2Memory, 19*1024)),
+		Argon2Iterations:  uint32(parseInt(c.argon2Time, 2)),
+		Argon2Parallelism: uint8(min(parseInt(c.argon2Threads, 1), 255)),
+	}
+}
+
+// One hash per core by default, more would only make each one slower
+func (c *Context) GetHashingConcurrency() int {
+	return parseInt(c.hashConcurrency, runtime.NumCPU())
+}
+
+func (c *Context) GetHashingQueueDepth() int {
+	return parseInt(c.hashQueueDepth, 4*c.GetHashingConcurrency())
+}
+
+func (c *Context) GetHashingRetryAfter() time.Duration {
+	return parseDuration(c.hashRetryAfter, time.Second)
+}
+
+func (c *Context) GetVerificationTokenTTL() time.Duration {
+	return parseDuration(c.verifyTokenTTL, 24*time.Hour)
+}
+
+func (c *Context) GetVerificationResendInterval() time.Duration {
+	return parseDuration(c.verifyResend, time.Minute)
+}
+
+func (c *Context) GetUnverifiedEmailPolicy() domain.UnverifiedEmailPolicy {
+	switch policy := domain.UnverifiedEmailPolicy(c.unverifiedLogin); policy {
+	case domain.AllowUnverified, domain.DenyUnverified:
+		return policy
+	default:
+		return domain.RestrictUnverified
+	}
+}
+
+func (c *Context) GetTOTPIssuer() string {
+	if c.totpIssuer == "" {
+		return "crabi-challenge"
+	}
+
+	return c.totpIssuer
+}
+
+func (c *Context) GetMFAChallengeTTL() time.Duration {
+	return parseDuration(c.mfaChallengeTTL, 5*time.Minute)
+}
+
+func (c *Context) GetLockoutPolicy() application.LockoutPolicy {
+	return application.LockoutPolicy{
+		EmailThreshold: parseInt(c.lockThreshold, 5),
+		IPThreshold:    parseInt(c.lockIPThreshold, 20),
+		BaseDelay:      parseDuration(c.lockBaseDelay, time.Minute),
+		MaxDelay:       parseDuration(c.lockMaxDelay, time.Hour),
+		Window:         parseDuration(c.attemptWindow, 15*time.Minute),
+	}
+}
+
+func (c *Context) GetDeletionPolicy() application.DeletionPolicy {
+	email := domain.DeletedEmailPolicy(c.deletedEmail)
+	if email != domain.RetainDeletedEmail {
+		email = domain.ReleaseDeletedEmail
+	}
+
+	return application.DeletionPolicy{
+		Email:     email,
+		Retention: parseDuration(c.retention, 30*24*time.Hour),
+	}
+}
+
+func (c *Context) GetPurgeInterval() time.Duration {
+	return parseDuration(c.purgeInterval, time.Hour)
+}
+
+func (c *Context) GetAdminUserIDs() []string {
+	return splitList(c.adminUserIDs)
+}
+
+// Only enable behind a proxy that overwrites X-Forwarded-For
+func (c *Context) GetTrustProxyHeaders() bool {
+	trust, _ := strconv.ParseBool(c.trustProxy)
+
+	return trust
+}
+
+func (c *Context) GetNotificationFile() string {
+	return c.notifyFile
+}
+
 func (c *Context) GetHttpPort() string {
 	if c.httpPort == "" {
 		return "8080"
@@ -29,11 +233,138 @@ func (c *Context) GetPLDURL() string {
 	return c.pldURL
 }
 
+// For every attempt, retries get a timeout of their own
+func (c *Context) GetPLDTimeout() time.Duration {
+	return parseDuration(c.pldTimeout, 5*time.Second)
+}
+
+func (c *Context) GetPLDPolicy() infrastructure.PLDPolicy {
+	return infrastructure.PLDPolicy{
+		MaxRetries:       parseCount(c.pldRetries, 2),
+		BaseDelay:        parseDuration(c.pldRetryDelay, 200*time.Millisecond),
+		MaxDelay:         2 * time.Second,
+		BreakerThreshold: parseInt(c.pldBreakerLimit, 5),
+		BreakerCooldown:  parseDuration(c.pldBreakerWait, 30*time.Second),
+	}
+}
+
+func (c *Context) GetScreeningPolicy() application.ScreeningPolicy {
+	outage := domain.PLDOutagePolicy(c.pldOutage)
+	if outage != domain.FailOpenOnOutage && outage != domain.DeferOnOutage {
+		outage = domain.FailClosedOnOutage
+	}
+
+	return application.ScreeningPolicy{
+		Outage:         outage,
+		MatchThreshold: min(parseFloat(c.pldThreshold, 0.9), 1),
+	}
+}
+
+func (c *Context) GetScreeningRetryInterval() time.Duration {
+	return parseDuration(c.screeningRetry, 5*time.Minute)
+}
+
 func GetContext() *Context {
 	return &Context{
-		jwtKey:   os.Getenv("JWT_KEY"),
-		httpPort: os.Getenv("HTTP_PORT"),
-		mongoURL: os.Getenv("MONGODB_URL"),
-		pldURL:   os.Getenv("PLD_URL"),
+		jwtKey:          os.Getenv("JWT_KEY"),
+		jwtSigningKey:   os.Getenv("JWT_SIGNING_KEY_FILE"),
+		jwtVerifyKeys:   os.Getenv("JWT_VERIFICATION_KEY_FILES"),
+		jwtIssuer:       os.Getenv("JWT_ISSUER"),
+		jwtAudience:     os.Getenv("JWT_AUDIENCE"),
+		accessTokenTTL:  os.Getenv("JWT_ACCESS_TTL"),
+		refreshTokenTTL: os.Getenv("JWT_REFRESH_TTL"),
+		resetTokenTTL:   os.Getenv("PASSWORD_RESET_TTL"),
+		passwordHistory: os.Getenv("PASSWORD_HISTORY_SIZE"),
+		passwordMinLen:  os.Getenv("PASSWORD_MIN_LENGTH"),
+		passwordScore:   os.Getenv("PASSWORD_MIN_SCORE"),
+		breachedDir:     os.Getenv("BREACHED_PASSWORDS_DIR"),
+		hashAlgorithm:   os.Getenv("PASSWORD_HASH_ALGORITHM"),
+		bcryptCost:      os.Getenv("BCRYPT_COST"),
+		argon2Memory:    os.Getenv("ARGON2_MEMORY"),
+		argon2Time:      os.Getenv("ARGON2_ITERATIONS"),
+		argon2Threads:   os.Getenv("ARGON2_PARALLELISM"),
+		hashConcurrency: os.Getenv("HASHING_CONCURRENCY"),
+		hashQueueDepth:  os.Getenv("HASHING_QUEUE_DEPTH"),
+		hashRetryAfter:  os.Getenv("HASHING_RETRY_AFTER"),
+		verifyTokenTTL:  os.Getenv("EMAIL_VERIFICATION_TTL"),
+		verifyResend:    os.Getenv("EMAIL_VERIFICATION_RESEND_INTERVAL"),
+		unverifiedLogin: os.Getenv("UNVERIFIED_LOGIN_POLICY"),
+		totpIssuer:      os.Getenv("TOTP_ISSUER"),
+		mfaChallengeTTL: os.Getenv("MFA_CHALLENGE_TTL"),
+		lockThreshold:   os.Getenv("LOGIN_LOCKOUT_THRESHOLD"),
+		lockIPThreshold: os.Getenv("LOGIN_IP_LOCKOUT_THRESHOLD"),
+		lockBaseDelay:   os.Getenv("LOGIN_LOCKOUT_BASE_DELAY"),
+		lockMaxDelay:    os.Getenv("LOGIN_LOCKOUT_MAX_DELAY"),
+		attemptWindow:   os.Getenv("LOGIN_ATTEMPT_WINDOW"),
+		adminUserIDs:    os.Getenv("ADMIN_USER_IDS"),
+		deletedEmail:    os.Getenv("DELETED_EMAIL_POLICY"),
+		retention:       os.Getenv("DELETED_USER_RETENTION"),
+		purgeInterval:   os.Getenv("DELETED_USER_PURGE_INTERVAL"),
+		trustProxy:      os.Getenv("TRUST_PROXY_HEADERS"),
+		notifyFile:      os.Getenv("NOTIFICATION_FILE"),
+		httpPort:        os.Getenv("HTTP_PORT"),
+		mongoURL:        os.Getenv("MONGODB_URL"),
+		pldURL:          os.Getenv("PLD_URL"),
+		pldTimeout:      os.Getenv("PLD_TIMEOUT"),
+		pldRetries:      os.Getenv("PLD_MAX_RETRIES"),
+		pldRetryDelay:   os.Getenv("PLD_RETRY_DELAY"),
+		pldBreakerLimit: os.Getenv("PLD_BREAKER_THRESHOLD"),
+		pldBreakerWait:  os.Getenv("PLD_BREAKER_COOLDOWN"),
+		pldOutage:       os.Getenv("PLD_OUTAGE_POLICY"),
+		pldThreshold:    os.Getenv("PLD_MATCH_THRESHOLD"),
+		screeningRetry:  os.Getenv("PLD_SCREENING_RETRY_INTERVAL"),
+	}
+}
+
+// Helper function to read durations like "15m" falling back to a default
+func parseDuration(value string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return fallback
 	}
+
+	return d
+}
+
+// Helper function to read positive integers falling back to a default
+func parseInt(value string, fallback int) int {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return fallback
+	}
+
+	return n
+}
+
+// Helper function to read integers where 0 is a valid value, falling back to a default
+func parseCount(value string, fallback int) int {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return fallback
+	}
+
+	return n
+}
+
+// Helper function to read positive numbers falling back to a default
+func parseFloat(value string, fallback float64) float64 {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n <= 0 {
+		return fallback
+	}
+
+	return n
+}
+
+// Helper function to read comma separated values
+func splitList(value string) []string {
+	var list []string
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
 }
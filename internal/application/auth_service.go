@@ -2,46 +2,195 @@ package application
 
 import (
 	"context"
+	"errors"
 
 	"github.com/christhianjesus/crabi-challenge/internal/domain"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthService interface {
 	Signin(ctx context.Context, user *domain.User) error
-	Login(ctx context.Context, email, password string) (string, error)
+	Login(ctx context.Context, email, password string) (*domain.LoginResult, error)
+	LoginMFA(ctx context.Context, challengeToken, code string) (*domain.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*domain.TokenPair, error)
+	Logout(ctx context.Context, token *domain.AccessToken, refreshToken string) error
 }
 
 type authService struct {
-	repo    domain.AuthRepository
-	userSrv UserService
+	repo      domain.AuthRepository
+	hasher    domain.PasswordHasher
+	userSrv   UserService
+	tokenSrv  TokenService
+	verifySrv EmailVerificationService
+	mfaSrv    MFAService
+	lockout   LockoutService
+	notifier  domain.Notifier
+	policySrv PasswordPolicyService
+	// compared against when the email is unknown, so both cases cost a hash run
+	dummyHash string
 }
 
-func NewAuthService(repo domain.AuthRepository, userSrv UserService) AuthService {
-	return &authService{repo, userSrv}
+func NewAuthService(repo domain.AuthRepository, hasher domain.PasswordHasher, userSrv UserService, tokenSrv TokenService, verifySrv EmailVerificationService, mfaSrv MFAService, lockout LockoutService, notifier domain.Notifier, policySrv PasswordPolicyService) AuthService {
+	dummyHash, _ := hasher.Hash(context.Background(), "dummy password")
+
+	return &authService{repo, hasher, userSrv, tokenSrv, verifySrv, mfaSrv, lockout, notifier, policySrv, dummyHash}
 }
 
 func (a *authService) Signin(ctx context.Context, user *domain.User) error {
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	if err := a.policySrv.CheckPassword(ctx, user.Password, user); err != nil {
+		return err
+	}
+
+	hash, err := a.hasher.Hash(ctx, user.Password)
 	if err != nil {
 		return err
 	}
 
-	user.Password = string(hashedBytes)
+	user.Password = hash
+
+	if err = a.userSrv.CreateUser(ctx, user); err != nil {
+		// the caller sees a success, only the owner of the email is told
+		if errors.Is(err, domain.ErrConflict) {
+			return a.notifier.Notify(ctx, &domain.Notification{
+				To:      user.Email,
+				Subject: "Sign up attempt",
+				Body:    "Someone tried to create an account with this email, which is already registered.\nIf it was you, log in or reset your password. Otherwise you can ignore this message.",
+			})
+		}
+
+		return err
+	}
 
-	return a.userSrv.CreateUser(ctx, user)
+	return a.verifySrv.SendVerification(ctx, user)
 }
 
-func (a *authService) Login(ctx context.Context, email string, password string) (string, error) {
+func (a *authService) Login(ctx context.Context, email string, password string) (*domain.LoginResult, error) {
+	ip := domain.ClientIPFromContext(ctx)
+
+	if err := a.lockout.CheckLogin(ctx, email, ip); err != nil {
+		return nil, err
+	}
+
 	userID, hash, err := a.repo.GetIdAndHash(ctx, email)
 	if err != nil {
-		return "", err
+		if !errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+
+		// a busy hasher fails both cases alike
+		if _, _, err = a.hasher.Verify(ctx, a.dummyHash, password); err != nil {
+			return nil, err
+		}
+
+		return nil, a.loginFailed(ctx, email, ip)
+	}
+
+	match, rehash, err := a.hasher.Verify(ctx, hash, password)
+	if err != nil {
+		return nil, err
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if !match {
+		return nil, a.loginFailed(ctx, email, ip)
+	}
+
+	if rehash {
+		a.rehashPassword(ctx, userID, hash, password)
+	}
+
+	challenge, err := a.mfaSrv.StartChallenge(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the counter is kept until the second factor passes as well
+	if challenge != nil {
+		return &domain.LoginResult{Challenge: challenge}, nil
+	}
+
+	if err = a.lockout.LoginSucceeded(ctx, email); err != nil {
+		return nil, err
+	}
+
+	pair, err := a.issueTokens(ctx, userID, domain.PasswordAuth)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	return &domain.LoginResult{Tokens: pair}, nil
+}
+
+// Wrong codes count as failed logins, and challenges started before the
+// lockout cannot be used to keep guessing
+func (a *authService) LoginMFA(ctx context.Context, challengeToken, code string) (*domain.TokenPair, error) {
+	ip := domain.ClientIPFromContext(ctx)
+
+	userID, codeErr := a.mfaSrv.CompleteChallenge(ctx, challengeToken, code)
+	if userID == "" {
+		return nil, codeErr
+	}
+
+	user, err := a.userSrv.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if errors.Is(codeErr, ErrInvalidMFACode) {
+		if err = a.lockout.LoginFailed(ctx, user.Email, ip); err != nil {
+			return nil, err
+		}
+
+		return nil, codeErr
+	}
+
+	if codeErr != nil {
+		return nil, codeErr
+	}
+
+	if err = a.lockout.CheckLogin(ctx, user.Email, ip); err != nil {
+		return nil, err
+	}
+
+	if err = a.lockout.LoginSucceeded(ctx, user.Email); err != nil {
+		return nil, err
+	}
+
+	return a.tokenSrv.IssueTokens(ctx, user, domain.TOTPAuth)
+}
+
+func (a *authService) Refresh(ctx context.Context, refreshToken string) (*domain.TokenPair, error) {
+	return a.tokenSrv.RefreshTokens(ctx, refreshToken)
+}
+
+func (a *authService) Logout(ctx context.Context, token *domain.AccessToken, refreshToken string) error {
+	return a.tokenSrv.RevokeTokens(ctx, token, refreshToken)
+}
+
+func (a *authService) issueTokens(ctx context.Context, userID string, method domain.AuthMethod) (*domain.TokenPair, error) {
+	user, err := a.userSrv.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.tokenSrv.IssueTokens(ctx, user, method)
+}
+
+// The login does not depend on it, a hash that could not be upgraded
+// still works and the upgrade is tried again on the next login
+func (a *authService) rehashPassword(ctx context.Context, userID, hash, password string) {
+	newHash, err := a.hasher.Hash(ctx, password)
+	if err != nil {
+		return
+	}
+
+	_ = a.repo.RehashPassword(ctx, userID, hash, newHash)
+}
+
+// Unknown emails and wrong passwords look the same to the caller,
+// and both count as failures so locking does not reveal them either
+func (a *authService) loginFailed(ctx context.Context, email, ip string) error {
+	if err := a.lockout.LoginFailed(ctx, email, ip); err != nil {
+		return err
 	}
 
-	return userID, nil
+	return domain.ErrInvalidCredentials
 }
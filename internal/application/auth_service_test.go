@@ -3,57 +3,98 @@ package application
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/christhianjesus/crabi-challenge/internal/domain"
 	"github.com/christhianjesus/crabi-challenge/internal/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type authServiceMock struct {
-	repoMock *mocks.AuthRepository
-	srvMock  *mocks.UserService
-	service  AuthService
+	repoMock    *mocks.AuthRepository
+	hasherMock  *mocks.PasswordHasher
+	srvMock     *mocks.UserService
+	tokenMock   *mocks.TokenService
+	verifyMock  *mocks.EmailVerificationService
+	mfaMock     *mocks.MFAService
+	lockoutMock *mocks.LockoutService
+	notifyMock  *mocks.Notifier
+	policyMock  *mocks.PasswordPolicyService
+	service     AuthService
 }
 
 func setupAuthService(t *testing.T) *authServiceMock {
 	mockAuthRepository := mocks.NewAuthRepository(t)
+	mockPasswordHasher := mocks.NewPasswordHasher(t)
 	mockUserService := mocks.NewUserService(t)
+	mockTokenService := mocks.NewTokenService(t)
+	mockEmailVerificationService := mocks.NewEmailVerificationService(t)
+	mockMFAService := mocks.NewMFAService(t)
+	mockLockoutService := mocks.NewLockoutService(t)
+	mockNotifier := mocks.NewNotifier(t)
+	mockPasswordPolicyService := mocks.NewPasswordPolicyService(t)
+	mockPasswordHasher.On("Hash", mock.Anything, "dummy password").Return("dummy", nil)
 
 	return &authServiceMock{
-		repoMock: mockAuthRepository,
-		srvMock:  mockUserService,
-		service:  NewAuthService(mockAuthRepository, mockUserService),
+		repoMock:    mockAuthRepository,
+		hasherMock:  mockPasswordHasher,
+		srvMock:     mockUserService,
+		tokenMock:   mockTokenService,
+		verifyMock:  mockEmailVerificationService,
+		mfaMock:     mockMFAService,
+		lockoutMock: mockLockoutService,
+		notifyMock:  mockNotifier,
+		policyMock:  mockPasswordPolicyService,
+		service:     NewAuthService(mockAuthRepository, mockPasswordHasher, mockUserService, mockTokenService, mockEmailVerificationService, mockMFAService, mockLockoutService, mockNotifier, mockPasswordPolicyService),
 	}
 }
 
 func TestSignin_OK(t *testing.T) {
-	password := "123"
 	checkPassword := func(user *domain.User) bool {
-		return bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) == nil
+		return user.Password == "hash"
 	}
 
 	asm := setupAuthService(t)
+	asm.policyMock.On("CheckPassword", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("*domain.User")).Return(nil)
+	asm.hasherMock.On("Hash", mock.Anything, "123").Return("hash", nil)
 	asm.srvMock.On("CreateUser", mock.IsType(nil), mock.MatchedBy(checkPassword)).Return(nil)
+	asm.verifyMock.On("SendVerification", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil)
 
-	err := asm.service.Signin(context.Context(nil), &domain.User{Password: password})
+	err := asm.service.Signin(context.Context(nil), &domain.User{Password: "123"})
 
 	assert.NoError(t, err)
 }
 
-func TestSignin_GenerateFromPasswordError(t *testing.T) {
-	password := make([]byte, 100)
+func TestSignin_WeakPassword(t *testing.T) {
+	rejected := &PasswordRejectedError{[]PasswordRejection{{PasswordTooShort, "password must be at least 8 characters long"}}}
+	user := &domain.User{Email: "an@email.com", Password: "123"}
+
+	asm := setupAuthService(t)
+	asm.policyMock.On("CheckPassword", mock.IsType(nil), "123", user).Return(rejected)
+
+	err := asm.service.Signin(context.Context(nil), user)
+
+	assert.ErrorIs(t, err, ErrWeakPassword)
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	asm.srvMock.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+}
+
+func TestSignin_HashError(t *testing.T) {
 	asm := setupAuthService(t)
+	asm.policyMock.On("CheckPassword", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("*domain.User")).Return(nil)
+	asm.hasherMock.On("Hash", mock.Anything, "123").Return("", assert.AnError)
 
-	err := asm.service.Signin(context.Context(nil), &domain.User{Password: string(password)})
+	err := asm.service.Signin(context.Context(nil), &domain.User{Password: "123"})
 
 	assert.Error(t, err)
-	assert.EqualError(t, err, bcrypt.ErrPasswordTooLong.Error())
+	assert.EqualError(t, err, assert.AnError.Error())
 }
 
 func TestSignin_CreateUserError(t *testing.T) {
 	asm := setupAuthService(t)
+	asm.policyMock.On("CheckPassword", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("*domain.User")).Return(nil)
+	asm.hasherMock.On("Hash", mock.Anything, mock.AnythingOfType("string")).Return("hash", nil)
 	asm.srvMock.On("CreateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(assert.AnError)
 
 	err := asm.service.Signin(context.Context(nil), &domain.User{})
@@ -62,41 +103,449 @@ func TestSignin_CreateUserError(t *testing.T) {
 	assert.EqualError(t, err, assert.AnError.Error())
 }
 
+func TestSignin_AlreadyExists(t *testing.T) {
+	isNotice := func(n *domain.Notification) bool {
+		return n.To == "an@email.com" && n.Subject == "Sign up attempt"
+	}
+
+	asm := setupAuthService(t)
+	asm.policyMock.On("CheckPassword", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("*domain.User")).Return(nil)
+	asm.hasherMock.On("Hash", mock.Anything, mock.AnythingOfType("string")).Return("hash", nil)
+	asm.srvMock.On("CreateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(domain.ErrConflict)
+	asm.notifyMock.On("Notify", mock.IsType(nil), mock.MatchedBy(isNotice)).Return(nil)
+
+	err := asm.service.Signin(context.Context(nil), &domain.User{Email: "an@email.com"})
+
+	assert.NoError(t, err)
+	asm.verifyMock.AssertNotCalled(t, "SendVerification", mock.Anything, mock.Anything)
+}
+
+func TestSignin_AlreadyExistsNotifyError(t *testing.T) {
+	asm := setupAuthService(t)
+	asm.policyMock.On("CheckPassword", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("*domain.User")).Return(nil)
+	asm.hasherMock.On("Hash", mock.Anything, mock.AnythingOfType("string")).Return("hash", nil)
+	asm.srvMock.On("CreateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(domain.ErrConflict)
+	asm.notifyMock.On("Notify", mock.IsType(nil), mock.AnythingOfType("*domain.Notification")).Return(assert.AnError)
+
+	err := asm.service.Signin(context.Context(nil), &domain.User{Email: "an@email.com"})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestSignin_SendVerificationError(t *testing.T) {
+	asm := setupAuthService(t)
+	asm.policyMock.On("CheckPassword", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("*domain.User")).Return(nil)
+	asm.hasherMock.On("Hash", mock.Anything, mock.AnythingOfType("string")).Return("hash", nil)
+	asm.srvMock.On("CreateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil)
+	asm.verifyMock.On("SendVerification", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(assert.AnError)
+
+	err := asm.service.Signin(context.Context(nil), &domain.User{})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
 func TestLogin_OK(t *testing.T) {
-	res := "1"
+	res := &domain.TokenPair{AccessToken: "access", RefreshToken: "refresh"}
 	password := "123"
-	hash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	user := &domain.User{ID: "1", EmailVerified: true}
 
 	asm := setupAuthService(t)
-	asm.repoMock.On("GetIdAndHash", mock.IsType(nil), mock.AnythingOfType("string")).Return(res, string(hash), nil)
+	asm.lockoutMock.On("CheckLogin", mock.IsType(nil), "an@email.com", "").Return(nil)
+	asm.repoMock.On("GetIdAndHash", mock.IsType(nil), mock.AnythingOfType("string")).Return("1", "hash", nil)
+	asm.hasherMock.On("Verify", mock.IsType(nil), "hash", mock.AnythingOfType("string")).Return(true, false, nil)
+	asm.lockoutMock.On("LoginSucceeded", mock.IsType(nil), "an@email.com").Return(nil)
+	asm.mfaMock.On("StartChallenge", mock.IsType(nil), "1").Return(nil, nil)
+	asm.srvMock.On("GetUser", mock.IsType(nil), "1").Return(user, nil)
+	asm.tokenMock.On("IssueTokens", mock.IsType(nil), user, domain.PasswordAuth).Return(res, nil)
 
-	userID, err := asm.service.Login(context.Context(nil), "an@email.com", password)
+	result, err := asm.service.Login(context.Context(nil), "an@email.com", password)
 
 	assert.NoError(t, err)
-	assert.NotNil(t, userID)
-	assert.Equal(t, res, userID)
+	assert.Equal(t, res, result.Tokens)
+	assert.Nil(t, result.Challenge)
+}
+
+func TestLogin_MFAChallenge(t *testing.T) {
+	challenge := &domain.MFAChallenge{Token: "challenge", ExpiresIn: time.Minute}
+	password := "123"
+
+	asm := setupAuthService(t)
+	asm.lockoutMock.On("CheckLogin", mock.IsType(nil), "an@email.com", "").Return(nil)
+	asm.repoMock.On("GetIdAndHash", mock.IsType(nil), mock.AnythingOfType("string")).Return("1", "hash", nil)
+	asm.hasherMock.On("Verify", mock.IsType(nil), "hash", mock.AnythingOfType("string")).Return(true, false, nil)
+	asm.mfaMock.On("StartChallenge", mock.IsType(nil), "1").Return(challenge, nil)
+
+	result, err := asm.service.Login(context.Context(nil), "an@email.com", password)
+
+	assert.NoError(t, err)
+	assert.Equal(t, challenge, result.Challenge)
+	assert.Nil(t, result.Tokens)
+}
+
+func TestLogin_StartChallengeError(t *testing.T) {
+	password := "123"
+
+	asm := setupAuthService(t)
+	asm.lockoutMock.On("CheckLogin", mock.IsType(nil), "an@email.com", "").Return(nil)
+	asm.repoMock.On("GetIdAndHash", mock.IsType(nil), mock.AnythingOfType("string")).Return("1", "hash", nil)
+	asm.hasherMock.On("Verify", mock.IsType(nil), "hash", mock.AnythingOfType("string")).Return(true, false, nil)
+	asm.mfaMock.On("StartChallenge", mock.IsType(nil), "1").Return(nil, assert.AnError)
+
+	result, err := asm.service.Login(context.Context(nil), "an@email.com", password)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+	assert.Nil(t, result)
+}
+
+func TestLogin_IssueTokensError(t *testing.T) {
+	password := "123"
+	user := &domain.User{ID: "1", EmailVerified: true}
+
+	asm := setupAuthService(t)
+	asm.lockoutMock.On("CheckLogin", mock.IsType(nil), "an@email.com", "").Return(nil)
+	asm.repoMock.On("GetIdAndHash", mock.IsType(nil), mock.AnythingOfType("string")).Return("1", "hash", nil)
+	asm.hasherMock.On("Verify", mock.IsType(nil), "hash", mock.AnythingOfType("string")).Return(true, false, nil)
+	asm.lockoutMock.On("LoginSucceeded", mock.IsType(nil), "an@email.com").Return(nil)
+	asm.mfaMock.On("StartChallenge", mock.IsType(nil), "1").Return(nil, nil)
+	asm.srvMock.On("GetUser", mock.IsType(nil), "1").Return(user, nil)
+	asm.tokenMock.On("IssueTokens", mock.IsType(nil), user, domain.PasswordAuth).Return(nil, assert.AnError)
+
+	result, err := asm.service.Login(context.Context(nil), "an@email.com", password)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+	assert.Nil(t, result)
+}
+
+func TestLogin_GetUserError(t *testing.T) {
+	password := "123"
+
+	asm := setupAuthService(t)
+	asm.lockoutMock.On("CheckLogin", mock.IsType(nil), "an@email.com", "").Return(nil)
+	asm.repoMock.On("GetIdAndHash", mock.IsType(nil), mock.AnythingOfType("string")).Return("1", "hash", nil)
+	asm.hasherMock.On("Verify", mock.IsType(nil), "hash", mock.AnythingOfType("string")).Return(true, false, nil)
+	asm.lockoutMock.On("LoginSucceeded", mock.IsType(nil), "an@email.com").Return(nil)
+	asm.mfaMock.On("StartChallenge", mock.IsType(nil), "1").Return(nil, nil)
+	asm.srvMock.On("GetUser", mock.IsType(nil), "1").Return(nil, assert.AnError)
+
+	result, err := asm.service.Login(context.Context(nil), "an@email.com", password)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+	assert.Nil(t, result)
 }
 
 func TestLogin_GetIdAndHashError(t *testing.T) {
 	asm := setupAuthService(t)
+	asm.lockoutMock.On("CheckLogin", mock.IsType(nil), "an@email.com", "").Return(nil)
 	asm.repoMock.On("GetIdAndHash", mock.IsType(nil), mock.AnythingOfType("string")).Return("", "", assert.AnError)
 
-	userID, err := asm.service.Login(context.Context(nil), "an@email.com", "")
+	result, err := asm.service.Login(context.Context(nil), "an@email.com", "")
 
 	assert.Error(t, err)
 	assert.EqualError(t, err, assert.AnError.Error())
-	assert.Empty(t, userID)
+	assert.Nil(t, result)
 }
 
-func TestLogin_CompareHashAndPasswordError(t *testing.T) {
-	emptyHash := ""
+func TestLogin_VerifyError(t *testing.T) {
+	asm := setupAuthService(t)
+	asm.lockoutMock.On("CheckLogin", mock.IsType(nil), "an@email.com", "").Return(nil)
+	asm.repoMock.On("GetIdAndHash", mock.IsType(nil), mock.AnythingOfType("string")).Return("1", "", nil)
+	asm.hasherMock.On("Verify", mock.IsType(nil), "", "123").Return(false, false, assert.AnError)
+
+	result, err := asm.service.Login(context.Context(nil), "an@email.com", "123")
 
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+	assert.Nil(t, result)
+}
+
+func TestLogin_Locked(t *testing.T) {
+	asm := setupAuthService(t)
+	asm.lockoutMock.On("CheckLogin", mock.Anything, "an@email.com", "10.0.0.1").Return(&LoginLockedError{RetryAfter: time.Minute})
+
+	result, err := asm.service.Login(domain.WithClientIP(context.Background(), "10.0.0.1"), "an@email.com", "123")
+
+	assert.ErrorIs(t, err, ErrLoginLocked)
+	assert.Nil(t, result)
+}
+
+func TestLogin_WrongPasswordCountsFailure(t *testing.T) {
+
+	asm := setupAuthService(t)
+	asm.lockoutMock.On("CheckLogin", mock.Anything, "an@email.com", "10.0.0.1").Return(nil)
+	asm.repoMock.On("GetIdAndHash", mock.Anything, "an@email.com").Return("1", "hash", nil)
+	asm.hasherMock.On("Verify", mock.Anything, "hash", "wrong").Return(false, false, nil)
+	asm.lockoutMock.On("LoginFailed", mock.Anything, "an@email.com", "10.0.0.1").Return(nil)
+
+	result, err := asm.service.Login(domain.WithClientIP(context.Background(), "10.0.0.1"), "an@email.com", "wrong")
+
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+	assert.Nil(t, result)
+}
+
+func TestLogin_UnknownEmailCountsFailure(t *testing.T) {
 	asm := setupAuthService(t)
-	asm.repoMock.On("GetIdAndHash", mock.IsType(nil), mock.AnythingOfType("string")).Return("1", emptyHash, nil)
+	asm.lockoutMock.On("CheckLogin", mock.IsType(nil), "an@email.com", "").Return(nil)
+	asm.repoMock.On("GetIdAndHash", mock.IsType(nil), "an@email.com").Return("", "", domain.ErrNotFound)
+	asm.hasherMock.On("Verify", mock.IsType(nil), "dummy", "password").Return(false, false, nil)
+	asm.lockoutMock.On("LoginFailed", mock.IsType(nil), "an@email.com", "").Return(nil)
 
-	userID, err := asm.service.Login(context.Context(nil), "an@email.com", "123")
+	result, err := asm.service.Login(context.Context(nil), "an@email.com", "password")
+
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+	assert.Nil(t, result)
+}
+
+func TestLogin_UnknownEmailVerifiesDummyHash(t *testing.T) {
+	asm := setupAuthService(t)
+	asm.lockoutMock.On("CheckLogin", mock.IsType(nil), "an@email.com", "").Return(nil)
+	asm.repoMock.On("GetIdAndHash", mock.IsType(nil), "an@email.com").Return("", "", domain.ErrNotFound)
+	asm.hasherMock.On("Verify", mock.IsType(nil), "dummy", "password").Return(false, false, nil).Twice()
+	asm.lockoutMock.On("LoginFailed", mock.IsType(nil), "an@email.com", "").Return(nil)
+
+	// a fast path would tell unknown emails apart, the dummy hash is made only once
+	_, err := asm.service.Login(context.Context(nil), "an@email.com", "password")
+	_, _ = asm.service.Login(context.Context(nil), "an@email.com", "password")
+
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+	asm.hasherMock.AssertNumberOfCalls(t, "Hash", 1)
+}
+
+func TestLogin_UnknownEmailVerifyError(t *testing.T) {
+	asm := setupAuthService(t)
+	asm.lockoutMock.On("CheckLogin", mock.IsType(nil), "an@email.com", "").Return(nil)
+	asm.repoMock.On("GetIdAndHash", mock.IsType(nil), "an@email.com").Return("", "", domain.ErrNotFound)
+	asm.hasherMock.On("Verify", mock.IsType(nil), "dummy", "password").Return(false, false, assert.AnError)
+
+	result, err := asm.service.Login(context.Context(nil), "an@email.com", "password")
 
 	assert.Error(t, err)
-	assert.EqualError(t, err, bcrypt.ErrHashTooShort.Error())
-	assert.Empty(t, userID)
+	assert.EqualError(t, err, assert.AnError.Error())
+	assert.Nil(t, result)
+}
+
+func TestLogin_LoginFailedError(t *testing.T) {
+	asm := setupAuthService(t)
+	asm.lockoutMock.On("CheckLogin", mock.IsType(nil), "an@email.com", "").Return(nil)
+	asm.repoMock.On("GetIdAndHash", mock.IsType(nil), "an@email.com").Return("", "", domain.ErrNotFound)
+	asm.hasherMock.On("Verify", mock.IsType(nil), "dummy", "password").Return(false, false, nil)
+	asm.lockoutMock.On("LoginFailed", mock.IsType(nil), "an@email.com", "").Return(assert.AnError)
+
+	result, err := asm.service.Login(context.Context(nil), "an@email.com", "password")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+	assert.Nil(t, result)
+}
+
+func TestLogin_LoginSucceededError(t *testing.T) {
+
+	asm := setupAuthService(t)
+	asm.lockoutMock.On("CheckLogin", mock.IsType(nil), "an@email.com", "").Return(nil)
+	asm.repoMock.On("GetIdAndHash", mock.IsType(nil), "an@email.com").Return("1", "hash", nil)
+	asm.hasherMock.On("Verify", mock.IsType(nil), "hash", mock.AnythingOfType("string")).Return(true, false, nil)
+	asm.mfaMock.On("StartChallenge", mock.IsType(nil), "1").Return(nil, nil)
+	asm.lockoutMock.On("LoginSucceeded", mock.IsType(nil), "an@email.com").Return(assert.AnError)
+
+	result, err := asm.service.Login(context.Context(nil), "an@email.com", "password")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+	assert.Nil(t, result)
+}
+
+func TestLogin_RehashesOutdatedHash(t *testing.T) {
+	challenge := &domain.MFAChallenge{Token: "challenge", ExpiresIn: time.Minute}
+
+	asm := setupAuthService(t)
+	asm.lockoutMock.On("CheckLogin", mock.IsType(nil), "an@email.com", "").Return(nil)
+	asm.repoMock.On("GetIdAndHash", mock.IsType(nil), "an@email.com").Return("1", "old hash", nil)
+	asm.hasherMock.On("Verify", mock.IsType(nil), "old hash", "password").Return(true, true, nil)
+	asm.hasherMock.On("Hash", mock.Anything, "password").Return("new hash", nil)
+	asm.repoMock.On("RehashPassword", mock.IsType(nil), "1", "old hash", "new hash").Return(nil)
+	asm.mfaMock.On("StartChallenge", mock.IsType(nil), "1").Return(challenge, nil)
+
+	result, err := asm.service.Login(context.Context(nil), "an@email.com", "password")
+
+	assert.NoError(t, err)
+	assert.Equal(t, challenge, result.Challenge)
+}
+
+func TestLogin_RehashPasswordError(t *testing.T) {
+	challenge := &domain.MFAChallenge{Token: "challenge", ExpiresIn: time.Minute}
+
+	asm := setupAuthService(t)
+	asm.lockoutMock.On("CheckLogin", mock.IsType(nil), "an@email.com", "").Return(nil)
+	asm.repoMock.On("GetIdAndHash", mock.IsType(nil), "an@email.com").Return("1", "old hash", nil)
+	asm.hasherMock.On("Verify", mock.IsType(nil), "old hash", "password").Return(true, true, nil)
+	asm.hasherMock.On("Hash", mock.Anything, "password").Return("new hash", nil)
+	asm.repoMock.On("RehashPassword", mock.IsType(nil), "1", "old hash", "new hash").Return(assert.AnError)
+	asm.mfaMock.On("StartChallenge", mock.IsType(nil), "1").Return(challenge, nil)
+
+	// the old hash still works, the upgrade is tried again on the next login
+	result, err := asm.service.Login(context.Context(nil), "an@email.com", "password")
+
+	assert.NoError(t, err)
+	assert.Equal(t, challenge, result.Challenge)
+}
+
+func TestLoginMFA_OK(t *testing.T) {
+	res := &domain.TokenPair{AccessToken: "access", RefreshToken: "refresh"}
+	user := &domain.User{ID: "1", Email: "an@email.com", EmailVerified: true}
+
+	asm := setupAuthService(t)
+	asm.mfaMock.On("CompleteChallenge", mock.IsType(nil), "challenge", "123456").Return("1", nil)
+	asm.srvMock.On("GetUser", mock.IsType(nil), "1").Return(user, nil)
+	asm.lockoutMock.On("CheckLogin", mock.IsType(nil), "an@email.com", "").Return(nil)
+	asm.lockoutMock.On("LoginSucceeded", mock.IsType(nil), "an@email.com").Return(nil)
+	asm.tokenMock.On("IssueTokens", mock.IsType(nil), user, domain.TOTPAuth).Return(res, nil)
+
+	pair, err := asm.service.LoginMFA(context.Context(nil), "challenge", "123456")
+
+	assert.NoError(t, err)
+	assert.Equal(t, res, pair)
+}
+
+func TestLoginMFA_InvalidChallenge(t *testing.T) {
+	asm := setupAuthService(t)
+	asm.mfaMock.On("CompleteChallenge", mock.IsType(nil), "challenge", "123456").Return("", ErrInvalidMFAChallenge)
+
+	pair, err := asm.service.LoginMFA(context.Context(nil), "challenge", "123456")
+
+	assert.ErrorIs(t, err, ErrInvalidMFAChallenge)
+	assert.Nil(t, pair)
+}
+
+func TestLoginMFA_WrongCodeCountsFailure(t *testing.T) {
+	asm := setupAuthService(t)
+	asm.mfaMock.On("CompleteChallenge", mock.IsType(nil), "challenge", "123456").Return("1", ErrInvalidMFACode)
+	asm.srvMock.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1", Email: "an@email.com"}, nil)
+	asm.lockoutMock.On("LoginFailed", mock.IsType(nil), "an@email.com", "").Return(nil)
+
+	pair, err := asm.service.LoginMFA(context.Context(nil), "challenge", "123456")
+
+	assert.ErrorIs(t, err, ErrInvalidMFACode)
+	assert.Nil(t, pair)
+}
+
+func TestLoginMFA_LoginFailedError(t *testing.T) {
+	asm := setupAuthService(t)
+	asm.mfaMock.On("CompleteChallenge", mock.IsType(nil), "challenge", "123456").Return("1", ErrInvalidMFACode)
+	asm.srvMock.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1", Email: "an@email.com"}, nil)
+	asm.lockoutMock.On("LoginFailed", mock.IsType(nil), "an@email.com", "").Return(assert.AnError)
+
+	pair, err := asm.service.LoginMFA(context.Context(nil), "challenge", "123456")
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, pair)
+}
+
+func TestLoginMFA_CompleteChallengeError(t *testing.T) {
+	asm := setupAuthService(t)
+	asm.mfaMock.On("CompleteChallenge", mock.IsType(nil), "challenge", "123456").Return("1", assert.AnError)
+	asm.srvMock.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1", Email: "an@email.com"}, nil)
+
+	pair, err := asm.service.LoginMFA(context.Context(nil), "challenge", "123456")
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, pair)
+}
+
+func TestLoginMFA_GetUserError(t *testing.T) {
+	asm := setupAuthService(t)
+	asm.mfaMock.On("CompleteChallenge", mock.IsType(nil), "challenge", "123456").Return("1", nil)
+	asm.srvMock.On("GetUser", mock.IsType(nil), "1").Return(nil, assert.AnError)
+
+	pair, err := asm.service.LoginMFA(context.Context(nil), "challenge", "123456")
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, pair)
+}
+
+func TestLoginMFA_Locked(t *testing.T) {
+	asm := setupAuthService(t)
+	asm.mfaMock.On("CompleteChallenge", mock.IsType(nil), "challenge", "123456").Return("1", nil)
+	asm.srvMock.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1", Email: "an@email.com"}, nil)
+	asm.lockoutMock.On("CheckLogin", mock.IsType(nil), "an@email.com", "").Return(&LoginLockedError{RetryAfter: time.Minute})
+
+	// a challenge started before the lockout is not a way around it
+	pair, err := asm.service.LoginMFA(context.Context(nil), "challenge", "123456")
+
+	assert.ErrorIs(t, err, ErrLoginLocked)
+	assert.Nil(t, pair)
+}
+
+// Runs the password and code steps against the real lockout, until the email is locked
+func TestLoginMFA_RepeatedWrongCodesLock(t *testing.T) {
+	attempts := map[string]*domain.LoginAttempts{}
+	attemptRepo := mocks.NewLoginAttemptRepository(t)
+	attemptRepo.On("GetLoginAttempts", mock.Anything, mock.AnythingOfType("string")).Return(func(_ context.Context, key string) (*domain.LoginAttempts, error) {
+		if attempts[key] == nil {
+			return nil, domain.ErrNotFound
+		}
+
+		return attempts[key], nil
+	})
+	attemptRepo.On("AddLoginFailure", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(func(_ context.Context, key string, _ time.Time) (int, error) {
+		if attempts[key] == nil {
+			attempts[key] = &domain.LoginAttempts{Key: key}
+		}
+
+		attempts[key].Failures++
+
+		return attempts[key].Failures, nil
+	})
+	attemptRepo.On("LockLogin", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(nil).Run(func(args mock.Arguments) {
+		attempts[args.String(1)].LockedUntil = args.Get(2).(time.Time)
+	})
+
+	asm := setupAuthService(t)
+	lockout := NewLockoutService(attemptRepo, LockoutPolicy{EmailThreshold: 3, IPThreshold: 20, BaseDelay: time.Minute, MaxDelay: time.Hour, Window: time.Hour})
+	service := NewAuthService(asm.repoMock, asm.hasherMock, asm.srvMock, asm.tokenMock, asm.verifyMock, asm.mfaMock, lockout, asm.notifyMock, asm.policyMock)
+
+	asm.repoMock.On("GetIdAndHash", mock.IsType(nil), "an@email.com").Return("1", "hash", nil)
+	asm.hasherMock.On("Verify", mock.IsType(nil), "hash", "password").Return(true, false, nil)
+	asm.mfaMock.On("StartChallenge", mock.IsType(nil), "1").Return(&domain.MFAChallenge{Token: "challenge"}, nil)
+	asm.mfaMock.On("CompleteChallenge", mock.IsType(nil), "challenge", "000000").Return("1", ErrInvalidMFACode)
+	asm.srvMock.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1", Email: "an@email.com"}, nil)
+
+	for i := 0; i < 3; i++ {
+		_, err := service.Login(context.Context(nil), "an@email.com", "password")
+		assert.NoError(t, err)
+
+		_, err = service.LoginMFA(context.Context(nil), "challenge", "000000")
+		assert.ErrorIs(t, err, ErrInvalidMFACode)
+	}
+
+	_, err := service.Login(context.Context(nil), "an@email.com", "password")
+
+	var locked *LoginLockedError
+	assert.ErrorAs(t, err, &locked)
+}
+
+func TestRefresh_OK(t *testing.T) {
+	res := &domain.TokenPair{AccessToken: "access", RefreshToken: "refresh"}
+
+	asm := setupAuthService(t)
+	asm.tokenMock.On("RefreshTokens", mock.IsType(nil), "token").Return(res, nil)
+
+	pair, err := asm.service.Refresh(context.Context(nil), "token")
+
+	assert.NoError(t, err)
+	assert.Equal(t, res, pair)
+}
+
+func TestLogout_OK(t *testing.T) {
+	token := &domain.AccessToken{ID: "jti"}
+
+	asm := setupAuthService(t)
+	asm.tokenMock.On("RevokeTokens", mock.IsType(nil), token, "refresh").Return(nil)
+
+	err := asm.service.Logout(context.Context(nil), token, "refresh")
+
+	assert.NoError(t, err)
 }
@@ -0,0 +1,37 @@
+package application
+
+import (
+	"context"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+)
+
+var (
+	ErrUnauthenticated  = domain.NewError(domain.ErrInvalidCredentials, "Authentication required")
+	ErrPermissionDenied = domain.NewError(domain.ErrForbidden, "Permission denied")
+)
+
+// Helper function to check the caller of a request, the route guards are not
+// the only line of defense
+func authorize(ctx context.Context, permission domain.Permission) (*domain.Principal, error) {
+	principal, err := authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !principal.HasPermission(permission) {
+		return nil, ErrPermissionDenied
+	}
+
+	return principal, nil
+}
+
+// Helper function for the operations any authenticated caller can do on its own account
+func authenticate(ctx context.Context) (*domain.Principal, error) {
+	principal, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	return principal, nil
+}
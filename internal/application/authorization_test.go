@@ -0,0 +1,52 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorize_OK(t *testing.T) {
+	ctx := domain.WithPrincipal(context.Background(), &domain.Principal{UserID: "1", Roles: []domain.Role{domain.RoleAdmin}})
+
+	principal, err := authorize(ctx, domain.PermissionManageRoles)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1", principal.UserID)
+}
+
+func TestAuthorize_Unauthenticated(t *testing.T) {
+	principal, err := authorize(context.Context(nil), domain.PermissionManageRoles)
+
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+	assert.Nil(t, principal)
+}
+
+func TestAuthorize_PermissionDenied(t *testing.T) {
+	ctx := domain.WithPrincipal(context.Background(), &domain.Principal{UserID: "1", Roles: []domain.Role{domain.RoleUser, domain.RoleSupport}})
+
+	principal, err := authorize(ctx, domain.PermissionManageRoles)
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	assert.Nil(t, principal)
+}
+
+func TestAuthenticate_OK(t *testing.T) {
+	ctx := domain.WithPrincipal(context.Background(), &domain.Principal{UserID: "1", Roles: []domain.Role{domain.RoleUser}})
+
+	principal, err := authenticate(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1", principal.UserID)
+}
+
+func TestAuthenticate_Unauthenticated(t *testing.T) {
+	principal, err := authenticate(context.Background())
+
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+	assert.Nil(t, principal)
+}
@@ -0,0 +1,161 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+)
+
+// Hard matches cannot log in until decided, fuzzy ones can
+var reviewStatuses = []domain.ScreeningStatus{domain.ScreeningPendingReview, domain.ScreeningPossibleMatch}
+
+var (
+	ErrNotUnderReview  = domain.NewError(domain.ErrConflict, "User is not waiting for a review")
+	ErrCommentRequired = domain.NewError(domain.ErrInvalidInput, "A comment is required")
+	// the same decision was already taken, only its side effects are run again
+	errAlreadyDecided = domain.NewError(domain.ErrConflict, "User is not waiting for a review")
+)
+
+type ComplianceService interface {
+	ListScreenings(ctx context.Context, query *domain.ScreeningQuery) (*domain.ScreeningPage, error)
+	ListReviews(ctx context.Context, cursor string, limit int) (*domain.UserPage, error)
+	GetReview(ctx context.Context, userID string) (*domain.ReviewCase, error)
+	ApproveReview(ctx context.Context, userID, comment string) error
+	RejectReview(ctx context.Context, userID, comment string) error
+}
+
+type complianceService struct {
+	screeningRepo domain.ScreeningRepository
+	userRepo      domain.UserRepository
+	auditRepo     domain.AuditRepository
+	tokenSrv      TokenService
+}
+
+func NewComplianceService(screeningRepo domain.ScreeningRepository, userRepo domain.UserRepository, auditRepo domain.AuditRepository, tokenSrv TokenService) ComplianceService {
+	return &complianceService{screeningRepo, userRepo, auditRepo, tokenSrv}
+}
+
+func (s *complianceService) ListScreenings(ctx context.Context, query *domain.ScreeningQuery) (*domain.ScreeningPage, error) {
+	if _, err := authorize(ctx, domain.PermissionCompliance); err != nil {
+		return nil, err
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = defaultPageSize
+	}
+
+	query.Limit = min(query.Limit, maxPageSize)
+
+	return s.screeningRepo.ListScreenings(ctx, query)
+}
+
+// Oldest first, so cases are decided in the order they came in
+func (s *complianceService) ListReviews(ctx context.Context, cursor string, limit int) (*domain.UserPage, error) {
+	if _, err := authorize(ctx, domain.PermissionCompliance); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+
+	return s.userRepo.ListUsers(ctx, &domain.UserQuery{
+		Filter: domain.UserFilter{ScreeningStatuses: reviewStatuses},
+		Cursor: cursor,
+		Limit:  min(limit, maxPageSize),
+	})
+}
+
+// Decided cases can be looked at as well
+func (s *complianceService) GetReview(ctx context.Context, userID string) (*domain.ReviewCase, error) {
+	if _, err := authorize(ctx, domain.PermissionCompliance); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := s.screeningRepo.ListScreenings(ctx, &domain.ScreeningQuery{
+		Filter: domain.ScreeningFilter{UserID: user.ID},
+		Limit:  maxPageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ReviewCase{User: user, Screenings: page.Screenings}, nil
+}
+
+func (s *complianceService) ApproveReview(ctx context.Context, userID, comment string) error {
+	err := s.decide(ctx, userID, comment, domain.ScreeningPassed, domain.AuditReviewApproved)
+	if errors.Is(err, errAlreadyDecided) {
+		return ErrNotUnderReview
+	}
+
+	return err
+}
+
+// Possible matches may be logged in, their sessions are ended. Rejecting a blacklisted
+// user again only ends them, so a failed revocation can be retried
+func (s *complianceService) RejectReview(ctx context.Context, userID, comment string) error {
+	err := s.decide(ctx, userID, comment, domain.ScreeningBlacklisted, domain.AuditReviewRejected)
+	if err != nil && !errors.Is(err, errAlreadyDecided) {
+		return err
+	}
+
+	return s.tokenSrv.RevokeUserTokens(ctx, userID)
+}
+
+// Only cases still waiting are decided, officers cannot overrule each other
+func (s *complianceService) decide(ctx context.Context, userID, comment string, status domain.ScreeningStatus, action domain.AuditAction) error {
+	principal, err := authorize(ctx, domain.PermissionCompliance)
+	if err != nil {
+		return err
+	}
+
+	comment = strings.TrimSpace(comment)
+	if comment == "" {
+		return ErrCommentRequired
+	}
+
+	user, err := s.userRepo.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	previous := user.ScreeningStatus
+	if previous == status {
+		return errAlreadyDecided
+	}
+
+	if !slices.Contains(reviewStatuses, previous) {
+		return ErrNotUnderReview
+	}
+
+	// recorded first, a decision is never applied without its audit event
+	err = s.auditRepo.RecordEvent(ctx, &domain.AuditEvent{
+		Action:  action,
+		UserID:  user.ID,
+		ActorID: principal.UserID,
+		Details: map[string]string{"comment": comment, "previous_status": string(previous)},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = s.userRepo.SetScreeningStatus(ctx, user, status); err != nil {
+		// decided or deleted in the meantime
+		if errors.Is(err, domain.ErrNotFound) {
+			return ErrNotUnderReview
+		}
+
+		return err
+	}
+
+	return nil
+}
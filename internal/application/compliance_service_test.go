@@ -0,0 +1,316 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type complianceServiceMock struct {
+	screeningRepo *mocks.ScreeningRepository
+	userRepo      *mocks.UserRepository
+	auditRepo     *mocks.AuditRepository
+	tokenSrv      *mocks.TokenService
+	service       ComplianceService
+}
+
+func setupComplianceService(t *testing.T) *complianceServiceMock {
+	mockScreeningRepository := mocks.NewScreeningRepository(t)
+	mockUserRepository := mocks.NewUserRepository(t)
+	mockAuditRepository := mocks.NewAuditRepository(t)
+	mockTokenService := mocks.NewTokenService(t)
+
+	return &complianceServiceMock{
+		screeningRepo: mockScreeningRepository,
+		userRepo:      mockUserRepository,
+		auditRepo:     mockAuditRepository,
+		tokenSrv:      mockTokenService,
+		service:       NewComplianceService(mockScreeningRepository, mockUserRepository, mockAuditRepository, mockTokenService),
+	}
+}
+
+func complianceContext() context.Context {
+	return domain.WithPrincipal(context.Background(), &domain.Principal{UserID: "1", Roles: []domain.Role{domain.RoleCompliance}})
+}
+
+func TestListScreenings_OK(t *testing.T) {
+	page := &domain.ScreeningPage{Screenings: []*domain.ScreeningRecord{{ID: "1"}}}
+
+	csm := setupComplianceService(t)
+	csm.screeningRepo.On("ListScreenings", mock.Anything, &domain.ScreeningQuery{Limit: defaultPageSize}).Return(page, nil)
+
+	result, err := csm.service.ListScreenings(complianceContext(), &domain.ScreeningQuery{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, page, result)
+}
+
+func TestListScreenings_MaxPageSize(t *testing.T) {
+	csm := setupComplianceService(t)
+	csm.screeningRepo.On("ListScreenings", mock.Anything, &domain.ScreeningQuery{Limit: maxPageSize}).Return(&domain.ScreeningPage{}, nil)
+
+	_, err := csm.service.ListScreenings(complianceContext(), &domain.ScreeningQuery{Limit: 1000})
+
+	assert.NoError(t, err)
+}
+
+func TestListScreenings_PermissionDenied(t *testing.T) {
+	ctx := domain.WithPrincipal(context.Background(), &domain.Principal{UserID: "1", Roles: []domain.Role{domain.RoleSupport}})
+
+	csm := setupComplianceService(t)
+
+	page, err := csm.service.ListScreenings(ctx, &domain.ScreeningQuery{})
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	assert.Nil(t, page)
+}
+
+func TestListScreenings_Error(t *testing.T) {
+	csm := setupComplianceService(t)
+	csm.screeningRepo.On("ListScreenings", mock.Anything, mock.AnythingOfType("*domain.ScreeningQuery")).Return(nil, assert.AnError)
+
+	page, err := csm.service.ListScreenings(complianceContext(), &domain.ScreeningQuery{})
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, page)
+}
+
+func TestListReviews_OK(t *testing.T) {
+	query := &domain.UserQuery{Filter: domain.UserFilter{ScreeningStatuses: []domain.ScreeningStatus{domain.ScreeningPendingReview, domain.ScreeningPossibleMatch}}, Cursor: "abc", Limit: defaultPageSize}
+	page := &domain.UserPage{Users: []*domain.User{{ID: "2"}}}
+
+	csm := setupComplianceService(t)
+	csm.userRepo.On("ListUsers", mock.Anything, query).Return(page, nil)
+
+	result, err := csm.service.ListReviews(complianceContext(), "abc", 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, page, result)
+}
+
+func TestListReviews_MaxPageSize(t *testing.T) {
+	query := &domain.UserQuery{Filter: domain.UserFilter{ScreeningStatuses: []domain.ScreeningStatus{domain.ScreeningPendingReview, domain.ScreeningPossibleMatch}}, Limit: maxPageSize}
+
+	csm := setupComplianceService(t)
+	csm.userRepo.On("ListUsers", mock.Anything, query).Return(&domain.UserPage{}, nil)
+
+	_, err := csm.service.ListReviews(complianceContext(), "", 1000)
+
+	assert.NoError(t, err)
+}
+
+func TestListReviews_Unauthenticated(t *testing.T) {
+	csm := setupComplianceService(t)
+
+	page, err := csm.service.ListReviews(context.Background(), "", 0)
+
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+	assert.Nil(t, page)
+}
+
+func TestGetReview_OK(t *testing.T) {
+	user := &domain.User{ID: "2", ScreeningStatus: domain.ScreeningPendingReview}
+	screenings := []*domain.ScreeningRecord{{ID: "3", UserID: "2"}}
+
+	csm := setupComplianceService(t)
+	csm.userRepo.On("GetUser", mock.Anything, "2").Return(user, nil)
+	csm.screeningRepo.On("ListScreenings", mock.Anything, &domain.ScreeningQuery{Filter: domain.ScreeningFilter{UserID: "2"}, Limit: maxPageSize}).Return(&domain.ScreeningPage{Screenings: screenings}, nil)
+
+	review, err := csm.service.GetReview(complianceContext(), "2")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.ReviewCase{User: user, Screenings: screenings}, review)
+}
+
+func TestGetReview_GetUserError(t *testing.T) {
+	csm := setupComplianceService(t)
+	csm.userRepo.On("GetUser", mock.Anything, "2").Return(nil, domain.ErrNotFound)
+
+	review, err := csm.service.GetReview(complianceContext(), "2")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Nil(t, review)
+}
+
+func TestGetReview_ListScreeningsError(t *testing.T) {
+	csm := setupComplianceService(t)
+	csm.userRepo.On("GetUser", mock.Anything, "2").Return(&domain.User{ID: "2"}, nil)
+	csm.screeningRepo.On("ListScreenings", mock.Anything, mock.AnythingOfType("*domain.ScreeningQuery")).Return(nil, assert.AnError)
+
+	review, err := csm.service.GetReview(complianceContext(), "2")
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, review)
+}
+
+func TestApproveReview_OK(t *testing.T) {
+	user := &domain.User{ID: "2", ScreeningStatus: domain.ScreeningPendingReview}
+	event := &domain.AuditEvent{
+		Action:  domain.AuditReviewApproved,
+		UserID:  "2",
+		ActorID: "1",
+		Details: map[string]string{"comment": "Different birth date", "previous_status": "pending_review"},
+	}
+
+	csm := setupComplianceService(t)
+	csm.userRepo.On("GetUser", mock.Anything, "2").Return(user, nil)
+	csm.userRepo.On("SetScreeningStatus", mock.Anything, user, domain.ScreeningPassed).Return(nil)
+	csm.auditRepo.On("RecordEvent", mock.Anything, event).Return(nil)
+
+	err := csm.service.ApproveReview(complianceContext(), "2", "  Different birth date ")
+
+	assert.NoError(t, err)
+}
+
+func TestRejectReview_OK(t *testing.T) {
+	user := &domain.User{ID: "2", ScreeningStatus: domain.ScreeningPendingReview}
+	rejected := func(event *domain.AuditEvent) bool {
+		return event.Action == domain.AuditReviewRejected && event.ActorID == "1" && event.Details["comment"] == "Same person as listed"
+	}
+
+	csm := setupComplianceService(t)
+	csm.userRepo.On("GetUser", mock.Anything, "2").Return(user, nil)
+	csm.userRepo.On("SetScreeningStatus", mock.Anything, user, domain.ScreeningBlacklisted).Return(nil)
+	csm.auditRepo.On("RecordEvent", mock.Anything, mock.MatchedBy(rejected)).Return(nil)
+	csm.tokenSrv.On("RevokeUserTokens", mock.Anything, "2").Return(nil)
+
+	err := csm.service.RejectReview(complianceContext(), "2", "Same person as listed")
+
+	assert.NoError(t, err)
+}
+
+func TestApproveReview_PossibleMatch(t *testing.T) {
+	user := &domain.User{ID: "2", ScreeningStatus: domain.ScreeningPossibleMatch}
+	event := &domain.AuditEvent{
+		Action:  domain.AuditReviewApproved,
+		UserID:  "2",
+		ActorID: "1",
+		Details: map[string]string{"comment": "Common name", "previous_status": "possible_match"},
+	}
+
+	csm := setupComplianceService(t)
+	csm.userRepo.On("GetUser", mock.Anything, "2").Return(user, nil)
+	csm.userRepo.On("SetScreeningStatus", mock.Anything, user, domain.ScreeningPassed).Return(nil)
+	csm.auditRepo.On("RecordEvent", mock.Anything, event).Return(nil)
+
+	err := csm.service.ApproveReview(complianceContext(), "2", "Common name")
+
+	assert.NoError(t, err)
+}
+
+func TestRejectReview_RevokeUserTokensError(t *testing.T) {
+	csm := setupComplianceService(t)
+	csm.userRepo.On("GetUser", mock.Anything, "2").Return(&domain.User{ID: "2", ScreeningStatus: domain.ScreeningPossibleMatch}, nil)
+	csm.userRepo.On("SetScreeningStatus", mock.Anything, mock.AnythingOfType("*domain.User"), domain.ScreeningBlacklisted).Return(nil)
+	csm.auditRepo.On("RecordEvent", mock.Anything, mock.AnythingOfType("*domain.AuditEvent")).Return(nil)
+	csm.tokenSrv.On("RevokeUserTokens", mock.Anything, "2").Return(assert.AnError)
+
+	err := csm.service.RejectReview(complianceContext(), "2", "Same person as listed")
+
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestRejectReview_RetriesRevocation(t *testing.T) {
+	csm := setupComplianceService(t)
+	csm.userRepo.On("GetUser", mock.Anything, "2").Return(&domain.User{ID: "2", ScreeningStatus: domain.ScreeningBlacklisted}, nil)
+	csm.tokenSrv.On("RevokeUserTokens", mock.Anything, "2").Return(nil)
+
+	// already rejected, the sessions are ended again without a second decision
+	err := csm.service.RejectReview(complianceContext(), "2", "Same person as listed")
+
+	assert.NoError(t, err)
+}
+
+func TestApproveReview_AlreadyApproved(t *testing.T) {
+	csm := setupComplianceService(t)
+	csm.userRepo.On("GetUser", mock.Anything, "2").Return(&domain.User{ID: "2", ScreeningStatus: domain.ScreeningPassed}, nil)
+
+	err := csm.service.ApproveReview(complianceContext(), "2", "ok")
+
+	assert.Equal(t, ErrNotUnderReview, err)
+}
+
+func TestRejectReview_NotUnderReview(t *testing.T) {
+	csm := setupComplianceService(t)
+	csm.userRepo.On("GetUser", mock.Anything, "2").Return(&domain.User{ID: "2", ScreeningStatus: domain.ScreeningPassed}, nil)
+
+	err := csm.service.RejectReview(complianceContext(), "2", "Same person as listed")
+
+	assert.ErrorIs(t, err, ErrNotUnderReview)
+}
+
+func TestApproveReview_PermissionDenied(t *testing.T) {
+	ctx := domain.WithPrincipal(context.Background(), &domain.Principal{UserID: "1", Roles: []domain.Role{domain.RoleSupport}})
+
+	csm := setupComplianceService(t)
+
+	err := csm.service.ApproveReview(ctx, "2", "ok")
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+}
+
+func TestApproveReview_CommentRequired(t *testing.T) {
+	csm := setupComplianceService(t)
+
+	err := csm.service.ApproveReview(complianceContext(), "2", "   ")
+
+	assert.ErrorIs(t, err, ErrCommentRequired)
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+}
+
+func TestApproveReview_GetUserError(t *testing.T) {
+	csm := setupComplianceService(t)
+	csm.userRepo.On("GetUser", mock.Anything, "2").Return(nil, domain.ErrNotFound)
+
+	err := csm.service.ApproveReview(complianceContext(), "2", "ok")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestApproveReview_NotUnderReview(t *testing.T) {
+	csm := setupComplianceService(t)
+	csm.userRepo.On("GetUser", mock.Anything, "2").Return(&domain.User{ID: "2", ScreeningStatus: domain.ScreeningBlacklisted}, nil)
+
+	err := csm.service.ApproveReview(complianceContext(), "2", "ok")
+
+	assert.ErrorIs(t, err, ErrNotUnderReview)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+}
+
+func TestApproveReview_DecidedMeanwhile(t *testing.T) {
+	csm := setupComplianceService(t)
+	csm.userRepo.On("GetUser", mock.Anything, "2").Return(&domain.User{ID: "2", ScreeningStatus: domain.ScreeningPendingReview}, nil)
+	csm.auditRepo.On("RecordEvent", mock.Anything, mock.AnythingOfType("*domain.AuditEvent")).Return(nil)
+	csm.userRepo.On("SetScreeningStatus", mock.Anything, mock.AnythingOfType("*domain.User"), domain.ScreeningPassed).Return(domain.ErrNotFound)
+
+	err := csm.service.ApproveReview(complianceContext(), "2", "ok")
+
+	assert.ErrorIs(t, err, ErrNotUnderReview)
+}
+
+func TestApproveReview_SetScreeningStatusError(t *testing.T) {
+	csm := setupComplianceService(t)
+	csm.userRepo.On("GetUser", mock.Anything, "2").Return(&domain.User{ID: "2", ScreeningStatus: domain.ScreeningPendingReview}, nil)
+	csm.auditRepo.On("RecordEvent", mock.Anything, mock.AnythingOfType("*domain.AuditEvent")).Return(nil)
+	csm.userRepo.On("SetScreeningStatus", mock.Anything, mock.AnythingOfType("*domain.User"), domain.ScreeningPassed).Return(assert.AnError)
+
+	err := csm.service.ApproveReview(complianceContext(), "2", "ok")
+
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestApproveReview_RecordEventError(t *testing.T) {
+	csm := setupComplianceService(t)
+	csm.userRepo.On("GetUser", mock.Anything, "2").Return(&domain.User{ID: "2", ScreeningStatus: domain.ScreeningPendingReview}, nil)
+	csm.auditRepo.On("RecordEvent", mock.Anything, mock.AnythingOfType("*domain.AuditEvent")).Return(assert.AnError)
+
+	err := csm.service.ApproveReview(complianceContext(), "2", "ok")
+
+	// the decision is not applied without its audit event
+	assert.ErrorIs(t, err, assert.AnError)
+	csm.userRepo.AssertNotCalled(t, "SetScreeningStatus", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,111 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+)
+
+var ErrInvalidVerificationToken = domain.NewError(domain.ErrInvalidInput, "Invalid or expired verification token")
+
+type EmailVerificationService interface {
+	SendVerification(ctx context.Context, user *domain.User) error
+	VerifyEmail(ctx context.Context, verificationToken string) error
+	ResendVerification(ctx context.Context, email string) error
+}
+
+type emailVerificationService struct {
+	repo           domain.AuthRepository
+	userRepo       domain.UserRepository
+	otRepo         domain.OneTimeTokenRepository
+	notifier       domain.Notifier
+	verifyTTL      time.Duration
+	resendInterval time.Duration
+}
+
+func NewEmailVerificationService(repo domain.AuthRepository, userRepo domain.UserRepository, otRepo domain.OneTimeTokenRepository, notifier domain.Notifier, verifyTTL, resendInterval time.Duration) EmailVerificationService {
+	return &emailVerificationService{repo, userRepo, otRepo, notifier, verifyTTL, resendInterval}
+}
+
+func (e *emailVerificationService) SendVerification(ctx context.Context, user *domain.User) error {
+	// only the latest sent token is valid
+	if err := e.otRepo.DeleteOneTimeTokens(ctx, user.ID, domain.EmailVerificationPurpose); err != nil {
+		return err
+	}
+
+	verificationToken, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = e.otRepo.CreateOneTimeToken(ctx, &domain.OneTimeToken{
+		Hash:      hashToken(verificationToken),
+		UserID:    user.ID,
+		Purpose:   domain.EmailVerificationPurpose,
+		CreatedAt: now,
+		ExpiresAt: now.Add(e.verifyTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	return e.notifier.Notify(ctx, &domain.Notification{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body:    fmt.Sprintf("Use this code to verify your email: %s\nIt expires in %s.", verificationToken, e.verifyTTL),
+	})
+}
+
+func (e *emailVerificationService) VerifyEmail(ctx context.Context, verificationToken string) error {
+	stored, err := e.otRepo.ConsumeOneTimeToken(ctx, hashToken(verificationToken), domain.EmailVerificationPurpose)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return ErrInvalidVerificationToken
+		}
+
+		return err
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return ErrInvalidVerificationToken
+	}
+
+	return e.userRepo.MarkEmailVerified(ctx, stored.UserID)
+}
+
+// Unknown, verified and throttled emails are skipped silently,
+// so the caller cannot learn which accounts exist
+func (e *emailVerificationService) ResendVerification(ctx context.Context, email string) error {
+	userID, _, err := e.repo.GetIdAndHash(ctx, email)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	user, err := e.userRepo.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.EmailVerified {
+		return nil
+	}
+
+	latest, err := e.otRepo.GetLatestOneTimeToken(ctx, userID, domain.EmailVerificationPurpose)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+
+	if latest != nil && time.Since(latest.CreatedAt) < e.resendInterval {
+		return nil
+	}
+
+	return e.SendVerification(ctx, user)
+}
@@ -0,0 +1,229 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type emailVerificationServiceMock struct {
+	repo     *mocks.AuthRepository
+	userRepo *mocks.UserRepository
+	otRepo   *mocks.OneTimeTokenRepository
+	notifier *mocks.Notifier
+	service  EmailVerificationService
+}
+
+func setupEmailVerificationService(t *testing.T) *emailVerificationServiceMock {
+	mockAuthRepository := mocks.NewAuthRepository(t)
+	mockUserRepository := mocks.NewUserRepository(t)
+	mockOneTimeTokenRepository := mocks.NewOneTimeTokenRepository(t)
+	mockNotifier := mocks.NewNotifier(t)
+
+	return &emailVerificationServiceMock{
+		repo:     mockAuthRepository,
+		userRepo: mockUserRepository,
+		otRepo:   mockOneTimeTokenRepository,
+		notifier: mockNotifier,
+		service:  NewEmailVerificationService(mockAuthRepository, mockUserRepository, mockOneTimeTokenRepository, mockNotifier, time.Hour, time.Minute),
+	}
+}
+
+func TestSendVerification_OK(t *testing.T) {
+	var verificationToken *domain.OneTimeToken
+	checkToken := func(token *domain.OneTimeToken) bool {
+		verificationToken = token
+		return token.UserID == "1" && token.Purpose == domain.EmailVerificationPurpose && token.ExpiresAt.Sub(token.CreatedAt) == time.Hour
+	}
+	checkNotification := func(notification *domain.Notification) bool {
+		code := strings.Fields(notification.Body)[7]
+		return notification.To == "an@email.com" && hashToken(code) == verificationToken.Hash
+	}
+
+	evm := setupEmailVerificationService(t)
+	evm.otRepo.On("DeleteOneTimeTokens", mock.IsType(nil), "1", domain.EmailVerificationPurpose).Return(nil)
+	evm.otRepo.On("CreateOneTimeToken", mock.IsType(nil), mock.MatchedBy(checkToken)).Return(nil)
+	evm.notifier.On("Notify", mock.IsType(nil), mock.MatchedBy(checkNotification)).Return(nil)
+
+	err := evm.service.SendVerification(context.Context(nil), &domain.User{ID: "1", Email: "an@email.com"})
+
+	assert.NoError(t, err)
+}
+
+func TestSendVerification_DeleteOneTimeTokensError(t *testing.T) {
+	evm := setupEmailVerificationService(t)
+	evm.otRepo.On("DeleteOneTimeTokens", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(assert.AnError)
+
+	err := evm.service.SendVerification(context.Context(nil), &domain.User{ID: "1"})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestSendVerification_CreateOneTimeTokenError(t *testing.T) {
+	evm := setupEmailVerificationService(t)
+	evm.otRepo.On("DeleteOneTimeTokens", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+	evm.otRepo.On("CreateOneTimeToken", mock.IsType(nil), mock.AnythingOfType("*domain.OneTimeToken")).Return(assert.AnError)
+
+	err := evm.service.SendVerification(context.Context(nil), &domain.User{ID: "1"})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestVerifyEmail_OK(t *testing.T) {
+	stored := &domain.OneTimeToken{UserID: "1", ExpiresAt: time.Now().Add(time.Hour)}
+
+	evm := setupEmailVerificationService(t)
+	evm.otRepo.On("ConsumeOneTimeToken", mock.IsType(nil), hashToken("token"), domain.EmailVerificationPurpose).Return(stored, nil)
+	evm.userRepo.On("MarkEmailVerified", mock.IsType(nil), "1").Return(nil)
+
+	err := evm.service.VerifyEmail(context.Context(nil), "token")
+
+	assert.NoError(t, err)
+}
+
+func TestVerifyEmail_NotFound(t *testing.T) {
+	evm := setupEmailVerificationService(t)
+	evm.otRepo.On("ConsumeOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil, domain.ErrNotFound)
+
+	err := evm.service.VerifyEmail(context.Context(nil), "token")
+
+	assert.ErrorIs(t, err, ErrInvalidVerificationToken)
+}
+
+func TestVerifyEmail_ConsumeOneTimeTokenError(t *testing.T) {
+	evm := setupEmailVerificationService(t)
+	evm.otRepo.On("ConsumeOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil, assert.AnError)
+
+	err := evm.service.VerifyEmail(context.Context(nil), "token")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestVerifyEmail_Expired(t *testing.T) {
+	stored := &domain.OneTimeToken{UserID: "1", ExpiresAt: time.Now().Add(-time.Minute)}
+
+	evm := setupEmailVerificationService(t)
+	evm.otRepo.On("ConsumeOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(stored, nil)
+
+	err := evm.service.VerifyEmail(context.Context(nil), "token")
+
+	assert.ErrorIs(t, err, ErrInvalidVerificationToken)
+}
+
+func TestVerifyEmail_MarkEmailVerifiedError(t *testing.T) {
+	stored := &domain.OneTimeToken{UserID: "1", ExpiresAt: time.Now().Add(time.Hour)}
+
+	evm := setupEmailVerificationService(t)
+	evm.otRepo.On("ConsumeOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(stored, nil)
+	evm.userRepo.On("MarkEmailVerified", mock.IsType(nil), "1").Return(assert.AnError)
+
+	err := evm.service.VerifyEmail(context.Context(nil), "token")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestResendVerification_OK(t *testing.T) {
+	latest := &domain.OneTimeToken{CreatedAt: time.Now().Add(-time.Hour)}
+
+	evm := setupEmailVerificationService(t)
+	evm.repo.On("GetIdAndHash", mock.IsType(nil), "an@email.com").Return("1", "hash", nil)
+	evm.userRepo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1", Email: "an@email.com"}, nil)
+	evm.otRepo.On("GetLatestOneTimeToken", mock.IsType(nil), "1", domain.EmailVerificationPurpose).Return(latest, nil)
+	evm.otRepo.On("DeleteOneTimeTokens", mock.IsType(nil), "1", domain.EmailVerificationPurpose).Return(nil)
+	evm.otRepo.On("CreateOneTimeToken", mock.IsType(nil), mock.AnythingOfType("*domain.OneTimeToken")).Return(nil)
+	evm.notifier.On("Notify", mock.IsType(nil), mock.AnythingOfType("*domain.Notification")).Return(nil)
+
+	err := evm.service.ResendVerification(context.Context(nil), "an@email.com")
+
+	assert.NoError(t, err)
+}
+
+func TestResendVerification_NoPreviousToken(t *testing.T) {
+	evm := setupEmailVerificationService(t)
+	evm.repo.On("GetIdAndHash", mock.IsType(nil), "an@email.com").Return("1", "hash", nil)
+	evm.userRepo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1", Email: "an@email.com"}, nil)
+	evm.otRepo.On("GetLatestOneTimeToken", mock.IsType(nil), "1", domain.EmailVerificationPurpose).Return(nil, domain.ErrNotFound)
+	evm.otRepo.On("DeleteOneTimeTokens", mock.IsType(nil), "1", domain.EmailVerificationPurpose).Return(nil)
+	evm.otRepo.On("CreateOneTimeToken", mock.IsType(nil), mock.AnythingOfType("*domain.OneTimeToken")).Return(nil)
+	evm.notifier.On("Notify", mock.IsType(nil), mock.AnythingOfType("*domain.Notification")).Return(nil)
+
+	err := evm.service.ResendVerification(context.Context(nil), "an@email.com")
+
+	assert.NoError(t, err)
+}
+
+func TestResendVerification_Throttled(t *testing.T) {
+	latest := &domain.OneTimeToken{CreatedAt: time.Now().Add(-time.Second)}
+
+	evm := setupEmailVerificationService(t)
+	evm.repo.On("GetIdAndHash", mock.IsType(nil), "an@email.com").Return("1", "hash", nil)
+	evm.userRepo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1"}, nil)
+	evm.otRepo.On("GetLatestOneTimeToken", mock.IsType(nil), "1", domain.EmailVerificationPurpose).Return(latest, nil)
+
+	err := evm.service.ResendVerification(context.Context(nil), "an@email.com")
+
+	assert.NoError(t, err)
+	evm.notifier.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+}
+
+func TestResendVerification_AlreadyVerified(t *testing.T) {
+	evm := setupEmailVerificationService(t)
+	evm.repo.On("GetIdAndHash", mock.IsType(nil), "an@email.com").Return("1", "hash", nil)
+	evm.userRepo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1", EmailVerified: true}, nil)
+
+	err := evm.service.ResendVerification(context.Context(nil), "an@email.com")
+
+	assert.NoError(t, err)
+}
+
+func TestResendVerification_UnknownEmail(t *testing.T) {
+	evm := setupEmailVerificationService(t)
+	evm.repo.On("GetIdAndHash", mock.IsType(nil), mock.AnythingOfType("string")).Return("", "", domain.ErrNotFound)
+
+	err := evm.service.ResendVerification(context.Context(nil), "an@email.com")
+
+	assert.NoError(t, err)
+}
+
+func TestResendVerification_GetIdAndHashError(t *testing.T) {
+	evm := setupEmailVerificationService(t)
+	evm.repo.On("GetIdAndHash", mock.IsType(nil), mock.AnythingOfType("string")).Return("", "", assert.AnError)
+
+	err := evm.service.ResendVerification(context.Context(nil), "an@email.com")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestResendVerification_GetUserError(t *testing.T) {
+	evm := setupEmailVerificationService(t)
+	evm.repo.On("GetIdAndHash", mock.IsType(nil), mock.AnythingOfType("string")).Return("1", "hash", nil)
+	evm.userRepo.On("GetUser", mock.IsType(nil), "1").Return(nil, assert.AnError)
+
+	err := evm.service.ResendVerification(context.Context(nil), "an@email.com")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestResendVerification_GetLatestOneTimeTokenError(t *testing.T) {
+	evm := setupEmailVerificationService(t)
+	evm.repo.On("GetIdAndHash", mock.IsType(nil), mock.AnythingOfType("string")).Return("1", "hash", nil)
+	evm.userRepo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1"}, nil)
+	evm.otRepo.On("GetLatestOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil, assert.AnError)
+
+	err := evm.service.ResendVerification(context.Context(nil), "an@email.com")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
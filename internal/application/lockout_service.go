@@ -0,0 +1,163 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+)
+
+var ErrLoginLocked = domain.NewError(domain.ErrRateLimited, "Too many failed login attempts")
+
+// Tells the caller when to try again, matches ErrLoginLocked
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return ErrLoginLocked.Error()
+}
+
+func (e *LoginLockedError) Unwrap() error {
+	return ErrLoginLocked
+}
+
+type LockoutPolicy struct {
+	// failures before the first lockout
+	EmailThreshold int
+	IPThreshold    int
+	// first lockout, doubled on every further failure up to MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// failures older than this are forgotten
+	Window time.Duration
+}
+
+type LockoutService interface {
+	CheckLogin(ctx context.Context, email, ip string) error
+	LoginFailed(ctx context.Context, email, ip string) error
+	LoginSucceeded(ctx context.Context, email string) error
+	Unlock(ctx context.Context, email, ip string) error
+}
+
+type lockoutService struct {
+	repo   domain.LoginAttemptRepository
+	policy LockoutPolicy
+}
+
+func NewLockoutService(repo domain.LoginAttemptRepository, policy LockoutPolicy) LockoutService {
+	return &lockoutService{repo, policy}
+}
+
+func (l *lockoutService) CheckLogin(ctx context.Context, email, ip string) error {
+	var retryAfter time.Duration
+
+	for _, key := range loginKeys(email, ip) {
+		attempts, err := l.repo.GetLoginAttempts(ctx, key)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				continue
+			}
+
+			return err
+		}
+
+		if wait := time.Until(attempts.LockedUntil); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	if retryAfter > 0 {
+		return &LoginLockedError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+func (l *lockoutService) LoginFailed(ctx context.Context, email, ip string) error {
+	if err := l.fail(ctx, emailKey(email), l.policy.EmailThreshold); err != nil {
+		return err
+	}
+
+	if ip == "" {
+		return nil
+	}
+
+	return l.fail(ctx, ipKey(ip), l.policy.IPThreshold)
+}
+
+// The IP counter is kept, otherwise one valid account would let
+// an attacker reset it between guesses
+func (l *lockoutService) LoginSucceeded(ctx context.Context, email string) error {
+	return l.repo.ResetLoginAttempts(ctx, emailKey(email))
+}
+
+func (l *lockoutService) Unlock(ctx context.Context, email, ip string) error {
+	if _, err := authorize(ctx, domain.PermissionUnlockUsers); err != nil {
+		return err
+	}
+
+	for _, key := range loginKeys(email, ip) {
+		if err := l.repo.ResetLoginAttempts(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *lockoutService) fail(ctx context.Context, key string, threshold int) error {
+	now := time.Now()
+	expiresAt := now.Add(l.policy.Window)
+
+	failures, err := l.repo.AddLoginFailure(ctx, key, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	if failures < threshold {
+		return nil
+	}
+
+	lockedUntil := now.Add(l.lockDelay(failures - threshold))
+	if lockedUntil.After(expiresAt) {
+		expiresAt = lockedUntil
+	}
+
+	return l.repo.LockLogin(ctx, key, lockedUntil, expiresAt)
+}
+
+func (l *lockoutService) lockDelay(extraFailures int) time.Duration {
+	delay := l.policy.BaseDelay
+
+	for i := 0; i < extraFailures && delay < l.policy.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, l.policy.MaxDelay)
+}
+
+// Helper function to list the counters involved in a login
+func loginKeys(email, ip string) []string {
+	var keys []string
+
+	if email != "" {
+		keys = append(keys, emailKey(email))
+	}
+
+	if ip != "" {
+		keys = append(keys, ipKey(ip))
+	}
+
+	return keys
+}
+
+func emailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
@@ -0,0 +1,175 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type lockoutServiceMock struct {
+	repo    *mocks.LoginAttemptRepository
+	service LockoutService
+}
+
+func setupLockoutService(t *testing.T) *lockoutServiceMock {
+	mockLoginAttemptRepository := mocks.NewLoginAttemptRepository(t)
+
+	return &lockoutServiceMock{
+		repo: mockLoginAttemptRepository,
+		service: NewLockoutService(mockLoginAttemptRepository, LockoutPolicy{
+			EmailThreshold: 5,
+			IPThreshold:    20,
+			BaseDelay:      time.Minute,
+			MaxDelay:       time.Hour,
+			Window:         15 * time.Minute,
+		}),
+	}
+}
+
+func TestCheckLogin_OK(t *testing.T) {
+	lsm := setupLockoutService(t)
+	lsm.repo.On("GetLoginAttempts", mock.IsType(nil), "email:an@email.com").Return(&domain.LoginAttempts{Failures: 2}, nil)
+	lsm.repo.On("GetLoginAttempts", mock.IsType(nil), "ip:10.0.0.1").Return(nil, domain.ErrNotFound)
+
+	err := lsm.service.CheckLogin(context.Context(nil), " An@Email.com", "10.0.0.1")
+
+	assert.NoError(t, err)
+}
+
+func TestCheckLogin_Locked(t *testing.T) {
+	lsm := setupLockoutService(t)
+	lsm.repo.On("GetLoginAttempts", mock.IsType(nil), "email:an@email.com").Return(&domain.LoginAttempts{LockedUntil: time.Now().Add(time.Minute)}, nil)
+	lsm.repo.On("GetLoginAttempts", mock.IsType(nil), "ip:10.0.0.1").Return(&domain.LoginAttempts{LockedUntil: time.Now().Add(time.Hour)}, nil)
+
+	err := lsm.service.CheckLogin(context.Context(nil), "an@email.com", "10.0.0.1")
+
+	var locked *LoginLockedError
+	assert.ErrorIs(t, err, ErrLoginLocked)
+	assert.True(t, errors.As(err, &locked))
+	assert.InDelta(t, time.Hour.Seconds(), locked.RetryAfter.Seconds(), 1)
+}
+
+func TestCheckLogin_ExpiredLock(t *testing.T) {
+	lsm := setupLockoutService(t)
+	lsm.repo.On("GetLoginAttempts", mock.IsType(nil), "email:an@email.com").Return(&domain.LoginAttempts{LockedUntil: time.Now().Add(-time.Minute)}, nil)
+
+	err := lsm.service.CheckLogin(context.Context(nil), "an@email.com", "")
+
+	assert.NoError(t, err)
+}
+
+func TestCheckLogin_GetLoginAttemptsError(t *testing.T) {
+	lsm := setupLockoutService(t)
+	lsm.repo.On("GetLoginAttempts", mock.IsType(nil), mock.AnythingOfType("string")).Return(nil, assert.AnError)
+
+	err := lsm.service.CheckLogin(context.Context(nil), "an@email.com", "")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestLoginFailed_BelowThreshold(t *testing.T) {
+	lsm := setupLockoutService(t)
+	lsm.repo.On("AddLoginFailure", mock.IsType(nil), "email:an@email.com", mock.AnythingOfType("time.Time")).Return(4, nil)
+	lsm.repo.On("AddLoginFailure", mock.IsType(nil), "ip:10.0.0.1", mock.AnythingOfType("time.Time")).Return(4, nil)
+
+	err := lsm.service.LoginFailed(context.Context(nil), "an@email.com", "10.0.0.1")
+
+	assert.NoError(t, err)
+}
+
+func TestLoginFailed_LocksWithBackoff(t *testing.T) {
+	lockedFor := func(delay time.Duration) interface{} {
+		return mock.MatchedBy(func(until time.Time) bool {
+			return time.Until(until).Round(time.Second) == delay
+		})
+	}
+
+	lsm := setupLockoutService(t)
+	lsm.repo.On("AddLoginFailure", mock.IsType(nil), "email:an@email.com", mock.AnythingOfType("time.Time")).Return(7, nil)
+	lsm.repo.On("LockLogin", mock.IsType(nil), "email:an@email.com", lockedFor(4*time.Minute), lockedFor(15*time.Minute)).Return(nil)
+
+	err := lsm.service.LoginFailed(context.Context(nil), "an@email.com", "")
+
+	assert.NoError(t, err)
+}
+
+func TestLoginFailed_MaxDelay(t *testing.T) {
+	lockedFor := func(delay time.Duration) interface{} {
+		return mock.MatchedBy(func(until time.Time) bool {
+			return time.Until(until).Round(time.Second) == delay
+		})
+	}
+
+	lsm := setupLockoutService(t)
+	lsm.repo.On("AddLoginFailure", mock.IsType(nil), "email:an@email.com", mock.AnythingOfType("time.Time")).Return(50, nil)
+	lsm.repo.On("LockLogin", mock.IsType(nil), "email:an@email.com", lockedFor(time.Hour), lockedFor(time.Hour)).Return(nil)
+
+	err := lsm.service.LoginFailed(context.Context(nil), "an@email.com", "")
+
+	assert.NoError(t, err)
+}
+
+func TestLoginFailed_AddLoginFailureError(t *testing.T) {
+	lsm := setupLockoutService(t)
+	lsm.repo.On("AddLoginFailure", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(0, assert.AnError)
+
+	err := lsm.service.LoginFailed(context.Context(nil), "an@email.com", "10.0.0.1")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestLoginFailed_LockLoginError(t *testing.T) {
+	lsm := setupLockoutService(t)
+	lsm.repo.On("AddLoginFailure", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(5, nil)
+	lsm.repo.On("LockLogin", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(assert.AnError)
+
+	err := lsm.service.LoginFailed(context.Context(nil), "an@email.com", "")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestLoginSucceeded_OK(t *testing.T) {
+	lsm := setupLockoutService(t)
+	lsm.repo.On("ResetLoginAttempts", mock.IsType(nil), "email:an@email.com").Return(nil)
+
+	err := lsm.service.LoginSucceeded(context.Context(nil), "an@email.com")
+
+	assert.NoError(t, err)
+}
+
+func TestUnlock_OK(t *testing.T) {
+	lsm := setupLockoutService(t)
+	lsm.repo.On("ResetLoginAttempts", mock.Anything, "email:an@email.com").Return(nil)
+	lsm.repo.On("ResetLoginAttempts", mock.Anything, "ip:10.0.0.1").Return(nil)
+
+	err := lsm.service.Unlock(supportContext(), "an@email.com", "10.0.0.1")
+
+	assert.NoError(t, err)
+}
+
+func TestUnlock_ResetLoginAttemptsError(t *testing.T) {
+	lsm := setupLockoutService(t)
+	lsm.repo.On("ResetLoginAttempts", mock.Anything, "ip:10.0.0.1").Return(assert.AnError)
+
+	err := lsm.service.Unlock(supportContext(), "", "10.0.0.1")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestUnlock_Unauthenticated(t *testing.T) {
+	lsm := setupLockoutService(t)
+
+	err := lsm.service.Unlock(context.Background(), "an@email.com", "10.0.0.1")
+
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+}
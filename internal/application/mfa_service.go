@@ -0,0 +1,243 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+)
+
+const recoveryCodeCount = 10
+
+var (
+	ErrMFAAlreadyEnabled   = domain.NewError(domain.ErrConflict, "MFA is already enabled")
+	ErrMFANotEnrolled      = domain.NewError(domain.ErrConflict, "MFA is not enrolled")
+	ErrInvalidMFACode      = domain.NewError(domain.ErrInvalidInput, "Invalid MFA code")
+	ErrInvalidMFAChallenge = domain.NewError(domain.ErrInvalidCredentials, "Invalid or expired MFA challenge")
+)
+
+type MFAService interface {
+	EnrollTOTP(ctx context.Context, userID string) (*domain.TOTPEnrollment, error)
+	ConfirmTOTP(ctx context.Context, userID, code string) ([]string, error)
+	DisableTOTP(ctx context.Context, userID, code string) error
+	StartChallenge(ctx context.Context, userID string) (*domain.MFAChallenge, error)
+	CompleteChallenge(ctx context.Context, challengeToken, code string) (string, error)
+}
+
+type mfaService struct {
+	repo         domain.MFARepository
+	userRepo     domain.UserRepository
+	otRepo       domain.OneTimeTokenRepository
+	issuer       string
+	challengeTTL time.Duration
+}
+
+func NewMFAService(repo domain.MFARepository, userRepo domain.UserRepository, otRepo domain.OneTimeTokenRepository, issuer string, challengeTTL time.Duration) MFAService {
+	return &mfaService{repo, userRepo, otRepo, issuer, challengeTTL}
+}
+
+// Starting again before confirming replaces the pending secret
+func (m *mfaService) EnrollTOTP(ctx context.Context, userID string) (*domain.TOTPEnrollment, error) {
+	mfa, err := m.repo.GetMFA(ctx, userID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	if mfa != nil && mfa.Enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	user, err := m.userRepo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := newTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	err = m.repo.SaveMFA(ctx, &domain.MFA{
+		UserID:    userID,
+		Secret:    secret,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TOTPEnrollment{
+		Secret: secret,
+		URI:    totpURI(m.issuer, user.Email, secret),
+	}, nil
+}
+
+// Returns the recovery codes in plain text, they cannot be read again
+func (m *mfaService) ConfirmTOTP(ctx context.Context, userID, code string) ([]string, error) {
+	mfa, err := m.getMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if mfa.Enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	if err = m.verifyTOTP(ctx, mfa, code); err != nil {
+		return nil, err
+	}
+
+	codes, err := newRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = hashToken(normalizeRecoveryCode(code))
+	}
+
+	if err = m.repo.EnableMFA(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (m *mfaService) DisableTOTP(ctx context.Context, userID, code string) error {
+	mfa, err := m.getMFA(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !mfa.Enabled {
+		return ErrMFANotEnrolled
+	}
+
+	if err = m.verify(ctx, mfa, code); err != nil {
+		return err
+	}
+
+	return m.repo.DeleteMFA(ctx, userID)
+}
+
+// Returns nil when the user has no MFA enabled
+func (m *mfaService) StartChallenge(ctx context.Context, userID string) (*domain.MFAChallenge, error) {
+	mfa, err := m.repo.GetMFA(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if !mfa.Enabled {
+		return nil, nil
+	}
+
+	challengeToken, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	err = m.otRepo.CreateOneTimeToken(ctx, &domain.OneTimeToken{
+		Hash:      hashToken(challengeToken),
+		UserID:    userID,
+		Purpose:   domain.MFAChallengePurpose,
+		CreatedAt: now,
+		ExpiresAt: now.Add(m.challengeTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.MFAChallenge{Token: challengeToken, ExpiresIn: m.challengeTTL}, nil
+}
+
+// The challenge is consumed by the first attempt, so a wrong code
+// means going through the password step again. The user is returned
+// with a wrong code as well, so the failure can be counted
+func (m *mfaService) CompleteChallenge(ctx context.Context, challengeToken, code string) (string, error) {
+	stored, err := m.otRepo.ConsumeOneTimeToken(ctx, hashToken(challengeToken), domain.MFAChallengePurpose)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return "", ErrInvalidMFAChallenge
+		}
+
+		return "", err
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return "", ErrInvalidMFAChallenge
+	}
+
+	mfa, err := m.getMFA(ctx, stored.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	if !mfa.Enabled {
+		return "", ErrInvalidMFAChallenge
+	}
+
+	if err = m.verify(ctx, mfa, code); err != nil {
+		return stored.UserID, err
+	}
+
+	return stored.UserID, nil
+}
+
+func (m *mfaService) getMFA(ctx context.Context, userID string) (*domain.MFA, error) {
+	mfa, err := m.repo.GetMFA(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, ErrMFANotEnrolled
+		}
+
+		return nil, err
+	}
+
+	return mfa, nil
+}
+
+// Accepts either a TOTP code or an unused recovery code
+func (m *mfaService) verify(ctx context.Context, mfa *domain.MFA, code string) error {
+	if len(code) == totpDigits {
+		return m.verifyTOTP(ctx, mfa, code)
+	}
+
+	used, err := m.repo.UseRecoveryCode(ctx, mfa.UserID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+
+	if !used {
+		return ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+func (m *mfaService) verifyTOTP(ctx context.Context, mfa *domain.MFA, code string) error {
+	step, ok := validateTOTP(mfa.Secret, code, time.Now())
+	if !ok {
+		return ErrInvalidMFACode
+	}
+
+	// a code can only be used once, even inside its time window
+	used, err := m.repo.UseTOTPStep(ctx, mfa.UserID, step)
+	if err != nil {
+		return err
+	}
+
+	if !used {
+		return ErrInvalidMFACode
+	}
+
+	return nil
+}
@@ -0,0 +1,364 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mfaServiceMock struct {
+	repo     *mocks.MFARepository
+	userRepo *mocks.UserRepository
+	otRepo   *mocks.OneTimeTokenRepository
+	service  MFAService
+}
+
+func setupMFAService(t *testing.T) *mfaServiceMock {
+	mockMFARepository := mocks.NewMFARepository(t)
+	mockUserRepository := mocks.NewUserRepository(t)
+	mockOneTimeTokenRepository := mocks.NewOneTimeTokenRepository(t)
+
+	return &mfaServiceMock{
+		repo:     mockMFARepository,
+		userRepo: mockUserRepository,
+		otRepo:   mockOneTimeTokenRepository,
+		service:  NewMFAService(mockMFARepository, mockUserRepository, mockOneTimeTokenRepository, "crabi", 5*time.Minute),
+	}
+}
+
+// Helper function to get the code an authenticator app would show now
+func currentTOTPCode() (string, int64) {
+	key, _ := totpEncoding.DecodeString(rfcSecret)
+	step := time.Now().Unix() / totpPeriod
+
+	return totpCode(key, step), step
+}
+
+func TestEnrollTOTP_OK(t *testing.T) {
+	msm := setupMFAService(t)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(nil, domain.ErrNotFound)
+	msm.userRepo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1", Email: "an@email.com"}, nil)
+	msm.repo.On("SaveMFA", mock.IsType(nil), mock.MatchedBy(func(mfa *domain.MFA) bool {
+		return mfa.UserID == "1" && mfa.Secret != "" && !mfa.Enabled
+	})).Return(nil)
+
+	enrollment, err := msm.service.EnrollTOTP(context.Context(nil), "1")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, enrollment.Secret)
+	assert.Equal(t, totpURI("crabi", "an@email.com", enrollment.Secret), enrollment.URI)
+}
+
+func TestEnrollTOTP_AlreadyEnabled(t *testing.T) {
+	msm := setupMFAService(t)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(&domain.MFA{UserID: "1", Enabled: true}, nil)
+
+	enrollment, err := msm.service.EnrollTOTP(context.Context(nil), "1")
+
+	assert.ErrorIs(t, err, ErrMFAAlreadyEnabled)
+	assert.Nil(t, enrollment)
+}
+
+func TestEnrollTOTP_GetMFAError(t *testing.T) {
+	msm := setupMFAService(t)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(nil, assert.AnError)
+
+	enrollment, err := msm.service.EnrollTOTP(context.Context(nil), "1")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+	assert.Nil(t, enrollment)
+}
+
+func TestEnrollTOTP_GetUserError(t *testing.T) {
+	msm := setupMFAService(t)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(&domain.MFA{UserID: "1"}, nil)
+	msm.userRepo.On("GetUser", mock.IsType(nil), "1").Return(nil, assert.AnError)
+
+	enrollment, err := msm.service.EnrollTOTP(context.Context(nil), "1")
+
+	assert.Error(t, err)
+	assert.Nil(t, enrollment)
+}
+
+func TestEnrollTOTP_SaveMFAError(t *testing.T) {
+	msm := setupMFAService(t)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(nil, domain.ErrNotFound)
+	msm.userRepo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1"}, nil)
+	msm.repo.On("SaveMFA", mock.IsType(nil), mock.AnythingOfType("*domain.MFA")).Return(assert.AnError)
+
+	enrollment, err := msm.service.EnrollTOTP(context.Context(nil), "1")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+	assert.Nil(t, enrollment)
+}
+
+func TestConfirmTOTP_OK(t *testing.T) {
+	code, step := currentTOTPCode()
+	var hashes []string
+
+	msm := setupMFAService(t)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(&domain.MFA{UserID: "1", Secret: rfcSecret}, nil)
+	msm.repo.On("UseTOTPStep", mock.IsType(nil), "1", step).Return(true, nil)
+	msm.repo.On("EnableMFA", mock.IsType(nil), "1", mock.MatchedBy(func(codes []string) bool {
+		hashes = codes
+		return len(codes) == recoveryCodeCount
+	})).Return(nil)
+
+	codes, err := msm.service.ConfirmTOTP(context.Context(nil), "1", code)
+
+	assert.NoError(t, err)
+	assert.Len(t, codes, recoveryCodeCount)
+	assert.Equal(t, hashToken(normalizeRecoveryCode(codes[0])), hashes[0])
+}
+
+func TestConfirmTOTP_NotEnrolled(t *testing.T) {
+	msm := setupMFAService(t)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(nil, domain.ErrNotFound)
+
+	codes, err := msm.service.ConfirmTOTP(context.Context(nil), "1", "123456")
+
+	assert.ErrorIs(t, err, ErrMFANotEnrolled)
+	assert.Nil(t, codes)
+}
+
+func TestConfirmTOTP_AlreadyEnabled(t *testing.T) {
+	msm := setupMFAService(t)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(&domain.MFA{UserID: "1", Secret: rfcSecret, Enabled: true}, nil)
+
+	codes, err := msm.service.ConfirmTOTP(context.Context(nil), "1", "123456")
+
+	assert.ErrorIs(t, err, ErrMFAAlreadyEnabled)
+	assert.Nil(t, codes)
+}
+
+func TestConfirmTOTP_InvalidCode(t *testing.T) {
+	msm := setupMFAService(t)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(&domain.MFA{UserID: "1", Secret: rfcSecret}, nil)
+
+	codes, err := msm.service.ConfirmTOTP(context.Context(nil), "1", "abcdef")
+
+	assert.ErrorIs(t, err, ErrInvalidMFACode)
+	assert.Nil(t, codes)
+}
+
+func TestConfirmTOTP_ReplayedCode(t *testing.T) {
+	code, step := currentTOTPCode()
+
+	msm := setupMFAService(t)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(&domain.MFA{UserID: "1", Secret: rfcSecret}, nil)
+	msm.repo.On("UseTOTPStep", mock.IsType(nil), "1", step).Return(false, nil)
+
+	codes, err := msm.service.ConfirmTOTP(context.Context(nil), "1", code)
+
+	assert.ErrorIs(t, err, ErrInvalidMFACode)
+	assert.Nil(t, codes)
+}
+
+func TestConfirmTOTP_EnableMFAError(t *testing.T) {
+	code, step := currentTOTPCode()
+
+	msm := setupMFAService(t)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(&domain.MFA{UserID: "1", Secret: rfcSecret}, nil)
+	msm.repo.On("UseTOTPStep", mock.IsType(nil), "1", step).Return(true, nil)
+	msm.repo.On("EnableMFA", mock.IsType(nil), "1", mock.Anything).Return(assert.AnError)
+
+	codes, err := msm.service.ConfirmTOTP(context.Context(nil), "1", code)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+	assert.Nil(t, codes)
+}
+
+func TestDisableTOTP_OK(t *testing.T) {
+	code, step := currentTOTPCode()
+
+	msm := setupMFAService(t)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(&domain.MFA{UserID: "1", Secret: rfcSecret, Enabled: true}, nil)
+	msm.repo.On("UseTOTPStep", mock.IsType(nil), "1", step).Return(true, nil)
+	msm.repo.On("DeleteMFA", mock.IsType(nil), "1").Return(nil)
+
+	err := msm.service.DisableTOTP(context.Context(nil), "1", code)
+
+	assert.NoError(t, err)
+}
+
+func TestDisableTOTP_RecoveryCode(t *testing.T) {
+	msm := setupMFAService(t)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(&domain.MFA{UserID: "1", Secret: rfcSecret, Enabled: true}, nil)
+	msm.repo.On("UseRecoveryCode", mock.IsType(nil), "1", hashToken("ABCDEFGH")).Return(true, nil)
+	msm.repo.On("DeleteMFA", mock.IsType(nil), "1").Return(nil)
+
+	err := msm.service.DisableTOTP(context.Context(nil), "1", "abcd-efgh")
+
+	assert.NoError(t, err)
+}
+
+func TestDisableTOTP_NotEnabled(t *testing.T) {
+	msm := setupMFAService(t)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(&domain.MFA{UserID: "1", Secret: rfcSecret}, nil)
+
+	err := msm.service.DisableTOTP(context.Context(nil), "1", "123456")
+
+	assert.ErrorIs(t, err, ErrMFANotEnrolled)
+}
+
+func TestDisableTOTP_UsedRecoveryCode(t *testing.T) {
+	msm := setupMFAService(t)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(&domain.MFA{UserID: "1", Secret: rfcSecret, Enabled: true}, nil)
+	msm.repo.On("UseRecoveryCode", mock.IsType(nil), "1", mock.AnythingOfType("string")).Return(false, nil)
+
+	err := msm.service.DisableTOTP(context.Context(nil), "1", "abcd-efgh")
+
+	assert.ErrorIs(t, err, ErrInvalidMFACode)
+}
+
+func TestDisableTOTP_UseRecoveryCodeError(t *testing.T) {
+	msm := setupMFAService(t)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(&domain.MFA{UserID: "1", Secret: rfcSecret, Enabled: true}, nil)
+	msm.repo.On("UseRecoveryCode", mock.IsType(nil), "1", mock.AnythingOfType("string")).Return(false, assert.AnError)
+
+	err := msm.service.DisableTOTP(context.Context(nil), "1", "abcd-efgh")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestStartChallenge_OK(t *testing.T) {
+	var challengeHash string
+
+	msm := setupMFAService(t)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(&domain.MFA{UserID: "1", Enabled: true}, nil)
+	msm.otRepo.On("CreateOneTimeToken", mock.IsType(nil), mock.MatchedBy(func(token *domain.OneTimeToken) bool {
+		challengeHash = token.Hash
+		return token.UserID == "1" && token.Purpose == domain.MFAChallengePurpose && token.ExpiresAt.Sub(token.CreatedAt) == 5*time.Minute
+	})).Return(nil)
+
+	challenge, err := msm.service.StartChallenge(context.Context(nil), "1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, hashToken(challenge.Token), challengeHash)
+	assert.Equal(t, 5*time.Minute, challenge.ExpiresIn)
+}
+
+func TestStartChallenge_NotEnrolled(t *testing.T) {
+	msm := setupMFAService(t)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(nil, domain.ErrNotFound)
+
+	challenge, err := msm.service.StartChallenge(context.Context(nil), "1")
+
+	assert.NoError(t, err)
+	assert.Nil(t, challenge)
+}
+
+func TestStartChallenge_PendingEnrollment(t *testing.T) {
+	msm := setupMFAService(t)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(&domain.MFA{UserID: "1"}, nil)
+
+	challenge, err := msm.service.StartChallenge(context.Context(nil), "1")
+
+	assert.NoError(t, err)
+	assert.Nil(t, challenge)
+}
+
+func TestStartChallenge_GetMFAError(t *testing.T) {
+	msm := setupMFAService(t)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(nil, assert.AnError)
+
+	challenge, err := msm.service.StartChallenge(context.Context(nil), "1")
+
+	assert.Error(t, err)
+	assert.Nil(t, challenge)
+}
+
+func TestStartChallenge_CreateOneTimeTokenError(t *testing.T) {
+	msm := setupMFAService(t)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(&domain.MFA{UserID: "1", Enabled: true}, nil)
+	msm.otRepo.On("CreateOneTimeToken", mock.IsType(nil), mock.AnythingOfType("*domain.OneTimeToken")).Return(assert.AnError)
+
+	challenge, err := msm.service.StartChallenge(context.Context(nil), "1")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+	assert.Nil(t, challenge)
+}
+
+func TestCompleteChallenge_OK(t *testing.T) {
+	code, step := currentTOTPCode()
+	stored := &domain.OneTimeToken{UserID: "1", ExpiresAt: time.Now().Add(time.Minute)}
+
+	msm := setupMFAService(t)
+	msm.otRepo.On("ConsumeOneTimeToken", mock.IsType(nil), hashToken("challenge"), domain.MFAChallengePurpose).Return(stored, nil)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(&domain.MFA{UserID: "1", Secret: rfcSecret, Enabled: true}, nil)
+	msm.repo.On("UseTOTPStep", mock.IsType(nil), "1", step).Return(true, nil)
+
+	userID, err := msm.service.CompleteChallenge(context.Context(nil), "challenge", code)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1", userID)
+}
+
+func TestCompleteChallenge_NotFound(t *testing.T) {
+	msm := setupMFAService(t)
+	msm.otRepo.On("ConsumeOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil, domain.ErrNotFound)
+
+	userID, err := msm.service.CompleteChallenge(context.Context(nil), "challenge", "123456")
+
+	assert.ErrorIs(t, err, ErrInvalidMFAChallenge)
+	assert.Empty(t, userID)
+}
+
+func TestCompleteChallenge_ConsumeOneTimeTokenError(t *testing.T) {
+	msm := setupMFAService(t)
+	msm.otRepo.On("ConsumeOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil, assert.AnError)
+
+	userID, err := msm.service.CompleteChallenge(context.Context(nil), "challenge", "123456")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+	assert.Empty(t, userID)
+}
+
+func TestCompleteChallenge_Expired(t *testing.T) {
+	stored := &domain.OneTimeToken{UserID: "1", ExpiresAt: time.Now().Add(-time.Minute)}
+
+	msm := setupMFAService(t)
+	msm.otRepo.On("ConsumeOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(stored, nil)
+
+	userID, err := msm.service.CompleteChallenge(context.Context(nil), "challenge", "123456")
+
+	assert.ErrorIs(t, err, ErrInvalidMFAChallenge)
+	assert.Empty(t, userID)
+}
+
+func TestCompleteChallenge_MFADisabled(t *testing.T) {
+	stored := &domain.OneTimeToken{UserID: "1", ExpiresAt: time.Now().Add(time.Minute)}
+
+	msm := setupMFAService(t)
+	msm.otRepo.On("ConsumeOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(stored, nil)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(nil, domain.ErrNotFound)
+
+	userID, err := msm.service.CompleteChallenge(context.Context(nil), "challenge", "123456")
+
+	assert.ErrorIs(t, err, ErrMFANotEnrolled)
+	assert.Empty(t, userID)
+}
+
+func TestCompleteChallenge_InvalidCode(t *testing.T) {
+	stored := &domain.OneTimeToken{UserID: "1", ExpiresAt: time.Now().Add(time.Minute)}
+
+	msm := setupMFAService(t)
+	msm.otRepo.On("ConsumeOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(stored, nil)
+	msm.repo.On("GetMFA", mock.IsType(nil), "1").Return(&domain.MFA{UserID: "1", Secret: rfcSecret, Enabled: true}, nil)
+
+	userID, err := msm.service.CompleteChallenge(context.Context(nil), "challenge", "abcdef")
+
+	assert.ErrorIs(t, err, ErrInvalidMFACode)
+	assert.Equal(t, "1", userID)
+}
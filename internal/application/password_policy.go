@@ -0,0 +1,145 @@
+package application
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+)
+
+var ErrWeakPassword = domain.NewError(domain.ErrInvalidInput, "Password does not meet the policy")
+
+type PasswordReason string
+
+const (
+	PasswordTooShort     PasswordReason = "too_short"
+	PasswordTooLong      PasswordReason = "too_long"
+	PasswordTooGuessable PasswordReason = "too_guessable"
+	PasswordPersonalInfo PasswordReason = "personal_info"
+	PasswordBreached     PasswordReason = "breached"
+)
+
+type PasswordRejection struct {
+	Reason  PasswordReason
+	Message string
+}
+
+// Lists every rule the password broke, matches ErrWeakPassword
+type PasswordRejectedError struct {
+	Rejections []PasswordRejection
+}
+
+func (e *PasswordRejectedError) Error() string {
+	return ErrWeakPassword.Error()
+}
+
+func (e *PasswordRejectedError) Unwrap() error {
+	return ErrWeakPassword
+}
+
+type PasswordPolicy struct {
+	MinLength int
+	// in bytes, bcrypt ignores anything past 72
+	MaxLength int
+	// from 0 to 4, see passwordScore
+	MinScore int
+}
+
+type PasswordPolicyService interface {
+	// The user holds the email and names the password cannot contain
+	CheckPassword(ctx context.Context, password string, user *domain.User) error
+}
+
+type passwordPolicyService struct {
+	breached domain.BreachedPasswordRepository
+	policy   PasswordPolicy
+}
+
+func NewPasswordPolicyService(breached domain.BreachedPasswordRepository, policy PasswordPolicy) PasswordPolicyService {
+	return &passwordPolicyService{breached, policy}
+}
+
+func (p *passwordPolicyService) CheckPassword(ctx context.Context, password string, user *domain.User) error {
+	var rejections []PasswordRejection
+
+	if utf8.RuneCountInString(password) < p.policy.MinLength {
+		rejections = append(rejections, PasswordRejection{PasswordTooShort, fmt.Sprintf("password must be at least %d characters long", p.policy.MinLength)})
+	}
+
+	if len(password) > p.policy.MaxLength {
+		rejections = append(rejections, PasswordRejection{PasswordTooLong, fmt.Sprintf("password must be at most %d bytes long", p.policy.MaxLength)})
+	}
+
+	inputs := personalInputs(user)
+	if containsPersonalInput(password, inputs) {
+		rejections = append(rejections, PasswordRejection{PasswordPersonalInfo, "password must not contain the email or the name"})
+	}
+
+	if passwordScore(password, inputs) < p.policy.MinScore {
+		rejections = append(rejections, PasswordRejection{PasswordTooGuessable, "password is too easy to guess, use a longer mix of unrelated words"})
+	}
+
+	breached, err := p.isBreached(ctx, password)
+	if err != nil {
+		return err
+	}
+
+	if breached {
+		rejections = append(rejections, PasswordRejection{PasswordBreached, "password appeared in a data breach"})
+	}
+
+	if len(rejections) > 0 {
+		return &PasswordRejectedError{rejections}
+	}
+
+	return nil
+}
+
+func (p *passwordPolicyService) isBreached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	suffixes, err := p.breached.GetBreachedSuffixes(ctx, hash[:5])
+	if err != nil {
+		return false, err
+	}
+
+	return slices.Contains(suffixes, hash[5:]), nil
+}
+
+// Helper function to collect the lowercase email, its local part and the names
+func personalInputs(user *domain.User) []string {
+	if user == nil {
+		return nil
+	}
+
+	local, _, _ := strings.Cut(user.Email, "@")
+	inputs := make([]string, 0, 4)
+
+	for _, input := range []string{user.Email, local, user.FirstName, user.LastName} {
+		// short names would ban too many passwords
+		if utf8.RuneCountInString(input) >= 3 {
+			inputs = append(inputs, strings.ToLower(input))
+		}
+	}
+
+	return inputs
+}
+
+func containsPersonalInput(password string, inputs []string) bool {
+	lower := strings.ToLower(password)
+	unleeted := unleet(lower)
+
+	for _, input := range inputs {
+		if strings.Contains(lower, input) || strings.Contains(unleeted, input) {
+			return true
+		}
+	}
+
+	return false
+}
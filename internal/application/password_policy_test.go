@@ -0,0 +1,116 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type passwordPolicyServiceMock struct {
+	repo    *mocks.BreachedPasswordRepository
+	service PasswordPolicyService
+}
+
+func setupPasswordPolicyService(t *testing.T) *passwordPolicyServiceMock {
+	mockBreachedPasswordRepository := mocks.NewBreachedPasswordRepository(t)
+
+	return &passwordPolicyServiceMock{
+		repo:    mockBreachedPasswordRepository,
+		service: NewPasswordPolicyService(mockBreachedPasswordRepository, PasswordPolicy{MinLength: 8, MaxLength: 72, MinScore: 3}),
+	}
+}
+
+// Helper function to get the reasons of a rejected password
+func rejectionReasons(err error) []PasswordReason {
+	var rejected *PasswordRejectedError
+	if !errors.As(err, &rejected) {
+		return nil
+	}
+
+	reasons := make([]PasswordReason, 0, len(rejected.Rejections))
+	for _, rejection := range rejected.Rejections {
+		reasons = append(reasons, rejection.Reason)
+	}
+
+	return reasons
+}
+
+func TestCheckPassword_OK(t *testing.T) {
+	ppsm := setupPasswordPolicyService(t)
+	ppsm.repo.On("GetBreachedSuffixes", mock.IsType(nil), "ABF7A").Return([]string{"0000000000000000000000000000000000A"}, nil)
+
+	err := ppsm.service.CheckPassword(context.Context(nil), "correct horse battery staple", &domain.User{Email: "an@email.com", FirstName: "first", LastName: "last"})
+
+	assert.NoError(t, err)
+}
+
+func TestCheckPassword_TooShort(t *testing.T) {
+	ppsm := setupPasswordPolicyService(t)
+	ppsm.repo.On("GetBreachedSuffixes", mock.IsType(nil), mock.AnythingOfType("string")).Return(nil, nil)
+
+	err := ppsm.service.CheckPassword(context.Context(nil), "x#9Lq", nil)
+
+	assert.ErrorIs(t, err, ErrWeakPassword)
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	assert.Contains(t, rejectionReasons(err), PasswordTooShort)
+}
+
+func TestCheckPassword_TooLong(t *testing.T) {
+	ppsm := setupPasswordPolicyService(t)
+	ppsm.repo.On("GetBreachedSuffixes", mock.IsType(nil), mock.AnythingOfType("string")).Return(nil, nil)
+
+	err := ppsm.service.CheckPassword(context.Context(nil), string(make([]byte, 73)), nil)
+
+	assert.Contains(t, rejectionReasons(err), PasswordTooLong)
+}
+
+func TestCheckPassword_TooGuessable(t *testing.T) {
+	ppsm := setupPasswordPolicyService(t)
+	ppsm.repo.On("GetBreachedSuffixes", mock.IsType(nil), mock.AnythingOfType("string")).Return(nil, nil)
+
+	err := ppsm.service.CheckPassword(context.Context(nil), "P@ssw0rd!", nil)
+
+	assert.Equal(t, []PasswordReason{PasswordTooGuessable}, rejectionReasons(err))
+}
+
+func TestCheckPassword_PersonalInfo(t *testing.T) {
+	ppsm := setupPasswordPolicyService(t)
+	ppsm.repo.On("GetBreachedSuffixes", mock.IsType(nil), mock.AnythingOfType("string")).Return(nil, nil)
+
+	err := ppsm.service.CheckPassword(context.Context(nil), "Christh1an#Vault-42", &domain.User{Email: "cj@email.com", FirstName: "Christhian", LastName: "Jesus"})
+
+	assert.Contains(t, rejectionReasons(err), PasswordPersonalInfo)
+}
+
+func TestCheckPassword_Breached(t *testing.T) {
+	ppsm := setupPasswordPolicyService(t)
+	// SHA-1 of password1 is E38AD214943DAAD1D64C102FAEC29DE4AFE9DA3D
+	ppsm.repo.On("GetBreachedSuffixes", mock.IsType(nil), "E38AD").Return([]string{"214943DAAD1D64C102FAEC29DE4AFE9DA3D"}, nil)
+
+	err := ppsm.service.CheckPassword(context.Context(nil), "password1", nil)
+
+	// every broken rule is reported at once
+	assert.Equal(t, []PasswordReason{PasswordTooGuessable, PasswordBreached}, rejectionReasons(err))
+}
+
+func TestCheckPassword_GetBreachedSuffixesError(t *testing.T) {
+	ppsm := setupPasswordPolicyService(t)
+	ppsm.repo.On("GetBreachedSuffixes", mock.IsType(nil), mock.AnythingOfType("string")).Return(nil, assert.AnError)
+
+	err := ppsm.service.CheckPassword(context.Context(nil), "correct horse battery staple", nil)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestPersonalInputs(t *testing.T) {
+	inputs := personalInputs(&domain.User{Email: "An@Email.com", FirstName: "Jo", LastName: "Smith"})
+
+	// the local part and names shorter than 3 characters are left out
+	assert.Equal(t, []string{"an@email.com", "smith"}, inputs)
+}
@@ -0,0 +1,181 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+)
+
+var (
+	ErrInvalidResetToken = domain.NewError(domain.ErrInvalidInput, "Invalid or expired reset token")
+	ErrWrongPassword     = domain.NewError(domain.ErrInvalidCredentials, "Current password is incorrect")
+	ErrPasswordReused    = domain.NewError(domain.ErrInvalidInput, "Password was used recently")
+)
+
+type PasswordService interface {
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, resetToken, password string) error
+	ChangePassword(ctx context.Context, currentPassword, newPassword string) error
+}
+
+type passwordService struct {
+	repo      domain.AuthRepository
+	hasher    domain.PasswordHasher
+	userRepo  domain.UserRepository
+	otRepo    domain.OneTimeTokenRepository
+	notifier  domain.Notifier
+	tokenSrv  TokenService
+	policySrv PasswordPolicyService
+	resetTTL  time.Duration
+	// number of recent passwords that cannot be reused, the current one included
+	historySize int
+}
+
+func NewPasswordService(repo domain.AuthRepository, hasher domain.PasswordHasher, userRepo domain.UserRepository, otRepo domain.OneTimeTokenRepository, notifier domain.Notifier, tokenSrv TokenService, policySrv PasswordPolicyService, resetTTL time.Duration, historySize int) PasswordService {
+	return &passwordService{repo, hasher, userRepo, otRepo, notifier, tokenSrv, policySrv, resetTTL, historySize}
+}
+
+func (p *passwordService) ForgotPassword(ctx context.Context, email string) error {
+	userID, _, err := p.repo.GetIdAndHash(ctx, email)
+	if err != nil {
+		// the caller must not learn whether the email is registered
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	// only the latest requested token is valid
+	if err = p.otRepo.DeleteOneTimeTokens(ctx, userID, domain.PasswordResetPurpose); err != nil {
+		return err
+	}
+
+	resetToken, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = p.otRepo.CreateOneTimeToken(ctx, &domain.OneTimeToken{
+		Hash:      hashToken(resetToken),
+		UserID:    userID,
+		Purpose:   domain.PasswordResetPurpose,
+		CreatedAt: now,
+		ExpiresAt: now.Add(p.resetTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.notifier.Notify(ctx, &domain.Notification{
+		To:      email,
+		Subject: "Reset your password",
+		Body:    fmt.Sprintf("Use this code to reset your password: %s\nIt expires in %s.", resetToken, p.resetTTL),
+	})
+}
+
+func (p *passwordService) ResetPassword(ctx context.Context, resetToken, password string) error {
+	// the token is only consumed once the password is accepted, so a weak one can be retried
+	stored, err := p.otRepo.GetOneTimeToken(ctx, hashToken(resetToken), domain.PasswordResetPurpose)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return ErrInvalidResetToken
+		}
+
+		return err
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	if err = p.checkPassword(ctx, stored.UserID, password); err != nil {
+		return err
+	}
+
+	if _, err = p.otRepo.ConsumeOneTimeToken(ctx, stored.Hash, domain.PasswordResetPurpose); err != nil {
+		// a concurrent reset used it first
+		if errors.Is(err, domain.ErrNotFound) {
+			return ErrInvalidResetToken
+		}
+
+		return err
+	}
+
+	hash, err := p.hasher.Hash(ctx, password)
+	if err != nil {
+		return err
+	}
+
+	if err = p.repo.UpdatePassword(ctx, stored.UserID, hash, p.historySize-1); err != nil {
+		return err
+	}
+
+	return p.tokenSrv.RevokeUserTokens(ctx, stored.UserID)
+}
+
+// The session making the change stays logged in, every other one is ended
+func (p *passwordService) ChangePassword(ctx context.Context, currentPassword, newPassword string) error {
+	principal, err := authenticate(ctx)
+	if err != nil {
+		return err
+	}
+
+	hashes, err := p.repo.GetPasswordHashes(ctx, principal.UserID)
+	if err != nil {
+		return err
+	}
+
+	match, _, err := p.hasher.Verify(ctx, hashes[0], currentPassword)
+	if err != nil {
+		return err
+	}
+
+	if !match {
+		return ErrWrongPassword
+	}
+
+	if err = p.checkPassword(ctx, principal.UserID, newPassword); err != nil {
+		return err
+	}
+
+	for i, hash := range hashes {
+		if i >= p.historySize {
+			break
+		}
+
+		// the history can hold hashes of any algorithm
+		reused, _, err := p.hasher.Verify(ctx, hash, newPassword)
+		if err != nil {
+			return err
+		}
+
+		if reused {
+			return ErrPasswordReused
+		}
+	}
+
+	hash, err := p.hasher.Hash(ctx, newPassword)
+	if err != nil {
+		return err
+	}
+
+	if err = p.repo.UpdatePassword(ctx, principal.UserID, hash, p.historySize-1); err != nil {
+		return err
+	}
+
+	return p.tokenSrv.RevokeOtherSessions(ctx, principal.UserID, principal.SessionID)
+}
+
+func (p *passwordService) checkPassword(ctx context.Context, userID, password string) error {
+	user, err := p.userRepo.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return p.policySrv.CheckPassword(ctx, password, user)
+}
@@ -0,0 +1,458 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type passwordServiceMock struct {
+	repo       *mocks.AuthRepository
+	hasher     *mocks.PasswordHasher
+	userRepo   *mocks.UserRepository
+	otRepo     *mocks.OneTimeTokenRepository
+	notifier   *mocks.Notifier
+	tokenMock  *mocks.TokenService
+	policyMock *mocks.PasswordPolicyService
+	service    PasswordService
+}
+
+func setupPasswordService(t *testing.T) *passwordServiceMock {
+	mockAuthRepository := mocks.NewAuthRepository(t)
+	mockPasswordHasher := mocks.NewPasswordHasher(t)
+	mockOneTimeTokenRepository := mocks.NewOneTimeTokenRepository(t)
+	mockNotifier := mocks.NewNotifier(t)
+	mockTokenService := mocks.NewTokenService(t)
+	mockUserRepository := mocks.NewUserRepository(t)
+	mockPasswordPolicyService := mocks.NewPasswordPolicyService(t)
+
+	return &passwordServiceMock{
+		repo:       mockAuthRepository,
+		hasher:     mockPasswordHasher,
+		userRepo:   mockUserRepository,
+		otRepo:     mockOneTimeTokenRepository,
+		notifier:   mockNotifier,
+		tokenMock:  mockTokenService,
+		policyMock: mockPasswordPolicyService,
+		service:    NewPasswordService(mockAuthRepository, mockPasswordHasher, mockUserRepository, mockOneTimeTokenRepository, mockNotifier, mockTokenService, mockPasswordPolicyService, time.Hour, 3),
+	}
+}
+
+func TestForgotPassword_OK(t *testing.T) {
+	var resetToken *domain.OneTimeToken
+	checkToken := func(token *domain.OneTimeToken) bool {
+		resetToken = token
+		return token.UserID == "1" && token.Purpose == domain.PasswordResetPurpose && token.ExpiresAt.Sub(token.CreatedAt) == time.Hour
+	}
+	checkNotification := func(notification *domain.Notification) bool {
+		code := strings.Fields(notification.Body)[7]
+		return notification.To == "an@email.com" && hashToken(code) == resetToken.Hash
+	}
+
+	psm := setupPasswordService(t)
+	psm.repo.On("GetIdAndHash", mock.IsType(nil), "an@email.com").Return("1", "hash", nil)
+	psm.otRepo.On("DeleteOneTimeTokens", mock.IsType(nil), "1", domain.PasswordResetPurpose).Return(nil)
+	psm.otRepo.On("CreateOneTimeToken", mock.IsType(nil), mock.MatchedBy(checkToken)).Return(nil)
+	psm.notifier.On("Notify", mock.IsType(nil), mock.MatchedBy(checkNotification)).Return(nil)
+
+	err := psm.service.ForgotPassword(context.Context(nil), "an@email.com")
+
+	assert.NoError(t, err)
+}
+
+func TestForgotPassword_UnknownEmail(t *testing.T) {
+	psm := setupPasswordService(t)
+	psm.repo.On("GetIdAndHash", mock.IsType(nil), "an@email.com").Return("", "", domain.ErrNotFound)
+
+	err := psm.service.ForgotPassword(context.Context(nil), "an@email.com")
+
+	assert.NoError(t, err)
+}
+
+func TestForgotPassword_GetIdAndHashError(t *testing.T) {
+	psm := setupPasswordService(t)
+	psm.repo.On("GetIdAndHash", mock.IsType(nil), "an@email.com").Return("", "", assert.AnError)
+
+	err := psm.service.ForgotPassword(context.Context(nil), "an@email.com")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestForgotPassword_DeleteOneTimeTokensError(t *testing.T) {
+	psm := setupPasswordService(t)
+	psm.repo.On("GetIdAndHash", mock.IsType(nil), "an@email.com").Return("1", "hash", nil)
+	psm.otRepo.On("DeleteOneTimeTokens", mock.IsType(nil), "1", domain.PasswordResetPurpose).Return(assert.AnError)
+
+	err := psm.service.ForgotPassword(context.Context(nil), "an@email.com")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestForgotPassword_CreateOneTimeTokenError(t *testing.T) {
+	psm := setupPasswordService(t)
+	psm.repo.On("GetIdAndHash", mock.IsType(nil), "an@email.com").Return("1", "hash", nil)
+	psm.otRepo.On("DeleteOneTimeTokens", mock.IsType(nil), "1", domain.PasswordResetPurpose).Return(nil)
+	psm.otRepo.On("CreateOneTimeToken", mock.IsType(nil), mock.AnythingOfType("*domain.OneTimeToken")).Return(assert.AnError)
+
+	err := psm.service.ForgotPassword(context.Context(nil), "an@email.com")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestForgotPassword_NotifyError(t *testing.T) {
+	psm := setupPasswordService(t)
+	psm.repo.On("GetIdAndHash", mock.IsType(nil), "an@email.com").Return("1", "hash", nil)
+	psm.otRepo.On("DeleteOneTimeTokens", mock.IsType(nil), "1", domain.PasswordResetPurpose).Return(nil)
+	psm.otRepo.On("CreateOneTimeToken", mock.IsType(nil), mock.AnythingOfType("*domain.OneTimeToken")).Return(nil)
+	psm.notifier.On("Notify", mock.IsType(nil), mock.AnythingOfType("*domain.Notification")).Return(assert.AnError)
+
+	err := psm.service.ForgotPassword(context.Context(nil), "an@email.com")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestResetPassword_OK(t *testing.T) {
+	password := "password"
+	stored := &domain.OneTimeToken{Hash: hashToken("token"), UserID: "1", ExpiresAt: time.Now().Add(time.Hour)}
+	user := &domain.User{ID: "1"}
+
+	psm := setupPasswordService(t)
+	psm.otRepo.On("GetOneTimeToken", mock.IsType(nil), hashToken("token"), domain.PasswordResetPurpose).Return(stored, nil)
+	psm.userRepo.On("GetUser", mock.IsType(nil), "1").Return(user, nil)
+	psm.policyMock.On("CheckPassword", mock.IsType(nil), password, user).Return(nil)
+	psm.otRepo.On("ConsumeOneTimeToken", mock.IsType(nil), hashToken("token"), domain.PasswordResetPurpose).Return(stored, nil)
+	psm.hasher.On("Hash", mock.IsType(nil), password).Return("hash", nil)
+	psm.repo.On("UpdatePassword", mock.IsType(nil), "1", "hash", 2).Return(nil)
+	psm.tokenMock.On("RevokeUserTokens", mock.IsType(nil), "1").Return(nil)
+
+	err := psm.service.ResetPassword(context.Context(nil), "token", password)
+
+	assert.NoError(t, err)
+}
+
+func TestResetPassword_UnknownToken(t *testing.T) {
+	psm := setupPasswordService(t)
+	psm.otRepo.On("GetOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), domain.PasswordResetPurpose).Return(nil, domain.ErrNotFound)
+
+	err := psm.service.ResetPassword(context.Context(nil), "token", "password")
+
+	assert.ErrorIs(t, err, ErrInvalidResetToken)
+}
+
+func TestResetPassword_GetOneTimeTokenError(t *testing.T) {
+	psm := setupPasswordService(t)
+	psm.otRepo.On("GetOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), domain.PasswordResetPurpose).Return(nil, assert.AnError)
+
+	err := psm.service.ResetPassword(context.Context(nil), "token", "password")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestResetPassword_ExpiredToken(t *testing.T) {
+	stored := &domain.OneTimeToken{UserID: "1", ExpiresAt: time.Now().Add(-time.Minute)}
+
+	psm := setupPasswordService(t)
+	psm.otRepo.On("GetOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), domain.PasswordResetPurpose).Return(stored, nil)
+
+	err := psm.service.ResetPassword(context.Context(nil), "token", "password")
+
+	assert.ErrorIs(t, err, ErrInvalidResetToken)
+}
+
+func TestResetPassword_GetUserError(t *testing.T) {
+	stored := &domain.OneTimeToken{UserID: "1", ExpiresAt: time.Now().Add(time.Hour)}
+
+	psm := setupPasswordService(t)
+	psm.otRepo.On("GetOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), domain.PasswordResetPurpose).Return(stored, nil)
+	psm.userRepo.On("GetUser", mock.IsType(nil), "1").Return(nil, assert.AnError)
+
+	err := psm.service.ResetPassword(context.Context(nil), "token", "password")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestResetPassword_WeakPassword(t *testing.T) {
+	stored := &domain.OneTimeToken{UserID: "1", ExpiresAt: time.Now().Add(time.Hour)}
+	rejected := &PasswordRejectedError{Rejections: []PasswordRejection{{Reason: PasswordTooShort, Message: "password must be at least 8 characters long"}}}
+
+	psm := setupPasswordService(t)
+	psm.otRepo.On("GetOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), domain.PasswordResetPurpose).Return(stored, nil)
+	psm.userRepo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1"}, nil)
+	psm.policyMock.On("CheckPassword", mock.IsType(nil), "short", mock.AnythingOfType("*domain.User")).Return(rejected)
+
+	err := psm.service.ResetPassword(context.Context(nil), "token", "short")
+
+	// the token is kept so a stronger password can be sent
+	assert.ErrorIs(t, err, ErrWeakPassword)
+	psm.otRepo.AssertNotCalled(t, "ConsumeOneTimeToken", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResetPassword_AlreadyConsumed(t *testing.T) {
+	stored := &domain.OneTimeToken{UserID: "1", ExpiresAt: time.Now().Add(time.Hour)}
+
+	psm := setupPasswordService(t)
+	psm.otRepo.On("GetOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), domain.PasswordResetPurpose).Return(stored, nil)
+	psm.userRepo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1"}, nil)
+	psm.policyMock.On("CheckPassword", mock.IsType(nil), "password", mock.AnythingOfType("*domain.User")).Return(nil)
+	psm.otRepo.On("ConsumeOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), domain.PasswordResetPurpose).Return(nil, domain.ErrNotFound)
+
+	err := psm.service.ResetPassword(context.Context(nil), "token", "password")
+
+	assert.ErrorIs(t, err, ErrInvalidResetToken)
+}
+
+func TestResetPassword_ConsumeOneTimeTokenError(t *testing.T) {
+	stored := &domain.OneTimeToken{UserID: "1", ExpiresAt: time.Now().Add(time.Hour)}
+
+	psm := setupPasswordService(t)
+	psm.otRepo.On("GetOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), domain.PasswordResetPurpose).Return(stored, nil)
+	psm.userRepo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1"}, nil)
+	psm.policyMock.On("CheckPassword", mock.IsType(nil), "password", mock.AnythingOfType("*domain.User")).Return(nil)
+	psm.otRepo.On("ConsumeOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), domain.PasswordResetPurpose).Return(nil, assert.AnError)
+
+	err := psm.service.ResetPassword(context.Context(nil), "token", "password")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestResetPassword_HashError(t *testing.T) {
+	stored := &domain.OneTimeToken{UserID: "1", ExpiresAt: time.Now().Add(time.Hour)}
+
+	psm := setupPasswordService(t)
+	psm.otRepo.On("GetOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), domain.PasswordResetPurpose).Return(stored, nil)
+	psm.userRepo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1"}, nil)
+	psm.policyMock.On("CheckPassword", mock.IsType(nil), "password", mock.AnythingOfType("*domain.User")).Return(nil)
+	psm.otRepo.On("ConsumeOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), domain.PasswordResetPurpose).Return(stored, nil)
+	psm.hasher.On("Hash", mock.IsType(nil), "password").Return("", assert.AnError)
+
+	err := psm.service.ResetPassword(context.Context(nil), "token", "password")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestResetPassword_UpdatePasswordError(t *testing.T) {
+	stored := &domain.OneTimeToken{UserID: "1", ExpiresAt: time.Now().Add(time.Hour)}
+
+	psm := setupPasswordService(t)
+	psm.otRepo.On("GetOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), domain.PasswordResetPurpose).Return(stored, nil)
+	psm.userRepo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1"}, nil)
+	psm.policyMock.On("CheckPassword", mock.IsType(nil), "password", mock.AnythingOfType("*domain.User")).Return(nil)
+	psm.otRepo.On("ConsumeOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), domain.PasswordResetPurpose).Return(stored, nil)
+	psm.hasher.On("Hash", mock.IsType(nil), "password").Return("hash", nil)
+	psm.repo.On("UpdatePassword", mock.IsType(nil), "1", "hash", 2).Return(assert.AnError)
+
+	err := psm.service.ResetPassword(context.Context(nil), "token", "password")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestResetPassword_RevokeUserTokensError(t *testing.T) {
+	stored := &domain.OneTimeToken{UserID: "1", ExpiresAt: time.Now().Add(time.Hour)}
+
+	psm := setupPasswordService(t)
+	psm.otRepo.On("GetOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), domain.PasswordResetPurpose).Return(stored, nil)
+	psm.userRepo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1"}, nil)
+	psm.policyMock.On("CheckPassword", mock.IsType(nil), "password", mock.AnythingOfType("*domain.User")).Return(nil)
+	psm.otRepo.On("ConsumeOneTimeToken", mock.IsType(nil), mock.AnythingOfType("string"), domain.PasswordResetPurpose).Return(stored, nil)
+	psm.hasher.On("Hash", mock.IsType(nil), "password").Return("hash", nil)
+	psm.repo.On("UpdatePassword", mock.IsType(nil), "1", "hash", 2).Return(nil)
+	psm.tokenMock.On("RevokeUserTokens", mock.IsType(nil), "1").Return(assert.AnError)
+
+	err := psm.service.ResetPassword(context.Context(nil), "token", "password")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestChangePassword_OK(t *testing.T) {
+	psm := setupPasswordService(t)
+	psm.repo.On("GetPasswordHashes", mock.Anything, "1").Return([]string{"current hash", "old hash"}, nil)
+	psm.hasher.On("Verify", mock.Anything, "current hash", "current password").Return(true, false, nil)
+	psm.userRepo.On("GetUser", mock.Anything, "1").Return(&domain.User{ID: "1"}, nil)
+	psm.policyMock.On("CheckPassword", mock.Anything, "new password", mock.AnythingOfType("*domain.User")).Return(nil)
+	psm.hasher.On("Verify", mock.Anything, mock.AnythingOfType("string"), "new password").Return(false, false, nil)
+	psm.hasher.On("Hash", mock.Anything, "new password").Return("new hash", nil)
+	psm.repo.On("UpdatePassword", mock.Anything, "1", "new hash", 2).Return(nil)
+	psm.tokenMock.On("RevokeOtherSessions", mock.Anything, "1", "current").Return(nil)
+
+	err := psm.service.ChangePassword(sessionContext(), "current password", "new password")
+
+	assert.NoError(t, err)
+}
+
+func TestChangePassword_Unauthenticated(t *testing.T) {
+	psm := setupPasswordService(t)
+
+	err := psm.service.ChangePassword(context.Background(), "current password", "new password")
+
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+}
+
+func TestChangePassword_GetPasswordHashesError(t *testing.T) {
+	psm := setupPasswordService(t)
+	psm.repo.On("GetPasswordHashes", mock.Anything, "1").Return(nil, assert.AnError)
+
+	err := psm.service.ChangePassword(sessionContext(), "current password", "new password")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestChangePassword_VerifyError(t *testing.T) {
+	psm := setupPasswordService(t)
+	psm.repo.On("GetPasswordHashes", mock.Anything, "1").Return([]string{"current hash"}, nil)
+	psm.hasher.On("Verify", mock.Anything, "current hash", "current password").Return(false, false, assert.AnError)
+
+	err := psm.service.ChangePassword(sessionContext(), "current password", "new password")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestChangePassword_WrongPassword(t *testing.T) {
+	psm := setupPasswordService(t)
+	psm.repo.On("GetPasswordHashes", mock.Anything, "1").Return([]string{"current hash"}, nil)
+	psm.hasher.On("Verify", mock.Anything, "current hash", "wrong password").Return(false, false, nil)
+
+	err := psm.service.ChangePassword(sessionContext(), "wrong password", "new password")
+
+	assert.ErrorIs(t, err, ErrWrongPassword)
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+}
+
+func TestChangePassword_WeakPassword(t *testing.T) {
+	rejected := &PasswordRejectedError{Rejections: []PasswordRejection{{Reason: PasswordBreached, Message: "password appeared in a data breach"}}}
+
+	psm := setupPasswordService(t)
+	psm.repo.On("GetPasswordHashes", mock.Anything, "1").Return([]string{"current hash"}, nil)
+	psm.hasher.On("Verify", mock.Anything, "current hash", "current password").Return(true, false, nil)
+	psm.userRepo.On("GetUser", mock.Anything, "1").Return(&domain.User{ID: "1"}, nil)
+	psm.policyMock.On("CheckPassword", mock.Anything, "password1", mock.AnythingOfType("*domain.User")).Return(rejected)
+
+	err := psm.service.ChangePassword(sessionContext(), "current password", "password1")
+
+	assert.ErrorIs(t, err, ErrWeakPassword)
+}
+
+func TestChangePassword_SameAsCurrent(t *testing.T) {
+	psm := setupPasswordService(t)
+	psm.repo.On("GetPasswordHashes", mock.Anything, "1").Return([]string{"current hash"}, nil)
+	psm.hasher.On("Verify", mock.Anything, "current hash", "current password").Return(true, false, nil)
+	psm.userRepo.On("GetUser", mock.Anything, "1").Return(&domain.User{ID: "1"}, nil)
+	psm.policyMock.On("CheckPassword", mock.Anything, "current password", mock.AnythingOfType("*domain.User")).Return(nil)
+
+	err := psm.service.ChangePassword(sessionContext(), "current password", "current password")
+
+	assert.ErrorIs(t, err, ErrPasswordReused)
+}
+
+func TestChangePassword_RecentlyUsed(t *testing.T) {
+	psm := setupPasswordService(t)
+	psm.repo.On("GetPasswordHashes", mock.Anything, "1").Return([]string{"current hash", "old hash"}, nil)
+	psm.hasher.On("Verify", mock.Anything, "current hash", "current password").Return(true, false, nil)
+	psm.userRepo.On("GetUser", mock.Anything, "1").Return(&domain.User{ID: "1"}, nil)
+	psm.policyMock.On("CheckPassword", mock.Anything, "old password", mock.AnythingOfType("*domain.User")).Return(nil)
+	psm.hasher.On("Verify", mock.Anything, "current hash", "old password").Return(false, false, nil)
+	psm.hasher.On("Verify", mock.Anything, "old hash", "old password").Return(true, false, nil)
+
+	err := psm.service.ChangePassword(sessionContext(), "current password", "old password")
+
+	assert.ErrorIs(t, err, ErrPasswordReused)
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+}
+
+func TestChangePassword_OutsideHistory(t *testing.T) {
+	psm := setupPasswordService(t)
+	psm.repo.On("GetPasswordHashes", mock.Anything, "1").Return([]string{"current hash", "old hash", "older hash", "oldest hash"}, nil)
+	psm.hasher.On("Verify", mock.Anything, "current hash", "current password").Return(true, false, nil)
+	psm.userRepo.On("GetUser", mock.Anything, "1").Return(&domain.User{ID: "1"}, nil)
+	psm.policyMock.On("CheckPassword", mock.Anything, "oldest password", mock.AnythingOfType("*domain.User")).Return(nil)
+	psm.hasher.On("Verify", mock.Anything, mock.AnythingOfType("string"), "oldest password").Return(false, false, nil)
+	psm.hasher.On("Hash", mock.Anything, "oldest password").Return("new hash", nil)
+	psm.repo.On("UpdatePassword", mock.Anything, "1", "new hash", 2).Return(nil)
+	psm.tokenMock.On("RevokeOtherSessions", mock.Anything, "1", "current").Return(nil)
+
+	err := psm.service.ChangePassword(sessionContext(), "current password", "oldest password")
+
+	// only the history size of 3 hashes is checked
+	assert.NoError(t, err)
+	psm.hasher.AssertNotCalled(t, "Verify", mock.Anything, "oldest hash", "oldest password")
+}
+
+func TestChangePassword_HistoryVerifyError(t *testing.T) {
+	psm := setupPasswordService(t)
+	psm.repo.On("GetPasswordHashes", mock.Anything, "1").Return([]string{"current hash", "old hash"}, nil)
+	psm.hasher.On("Verify", mock.Anything, "current hash", "current password").Return(true, false, nil)
+	psm.userRepo.On("GetUser", mock.Anything, "1").Return(&domain.User{ID: "1"}, nil)
+	psm.policyMock.On("CheckPassword", mock.Anything, "new password", mock.AnythingOfType("*domain.User")).Return(nil)
+	psm.hasher.On("Verify", mock.Anything, "current hash", "new password").Return(false, false, nil)
+	psm.hasher.On("Verify", mock.Anything, "old hash", "new password").Return(false, false, assert.AnError)
+
+	err := psm.service.ChangePassword(sessionContext(), "current password", "new password")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestChangePassword_HashError(t *testing.T) {
+	psm := setupPasswordService(t)
+	psm.repo.On("GetPasswordHashes", mock.Anything, "1").Return([]string{"current hash"}, nil)
+	psm.hasher.On("Verify", mock.Anything, "current hash", "current password").Return(true, false, nil)
+	psm.userRepo.On("GetUser", mock.Anything, "1").Return(&domain.User{ID: "1"}, nil)
+	psm.policyMock.On("CheckPassword", mock.Anything, "new password", mock.AnythingOfType("*domain.User")).Return(nil)
+	psm.hasher.On("Verify", mock.Anything, "current hash", "new password").Return(false, false, nil)
+	psm.hasher.On("Hash", mock.Anything, "new password").Return("", assert.AnError)
+
+	err := psm.service.ChangePassword(sessionContext(), "current password", "new password")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestChangePassword_UpdatePasswordError(t *testing.T) {
+	psm := setupPasswordService(t)
+	psm.repo.On("GetPasswordHashes", mock.Anything, "1").Return([]string{"current hash"}, nil)
+	psm.hasher.On("Verify", mock.Anything, "current hash", "current password").Return(true, false, nil)
+	psm.userRepo.On("GetUser", mock.Anything, "1").Return(&domain.User{ID: "1"}, nil)
+	psm.policyMock.On("CheckPassword", mock.Anything, "new password", mock.AnythingOfType("*domain.User")).Return(nil)
+	psm.hasher.On("Verify", mock.Anything, "current hash", "new password").Return(false, false, nil)
+	psm.hasher.On("Hash", mock.Anything, "new password").Return("new hash", nil)
+	psm.repo.On("UpdatePassword", mock.Anything, "1", "new hash", 2).Return(assert.AnError)
+
+	err := psm.service.ChangePassword(sessionContext(), "current password", "new password")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestChangePassword_RevokeOtherSessionsError(t *testing.T) {
+	psm := setupPasswordService(t)
+	psm.repo.On("GetPasswordHashes", mock.Anything, "1").Return([]string{"current hash"}, nil)
+	psm.hasher.On("Verify", mock.Anything, "current hash", "current password").Return(true, false, nil)
+	psm.userRepo.On("GetUser", mock.Anything, "1").Return(&domain.User{ID: "1"}, nil)
+	psm.policyMock.On("CheckPassword", mock.Anything, "new password", mock.AnythingOfType("*domain.User")).Return(nil)
+	psm.hasher.On("Verify", mock.Anything, "current hash", "new password").Return(false, false, nil)
+	psm.hasher.On("Hash", mock.Anything, "new password").Return("new hash", nil)
+	psm.repo.On("UpdatePassword", mock.Anything, "1", "new hash", 2).Return(nil)
+	psm.tokenMock.On("RevokeOtherSessions", mock.Anything, "1", "current").Return(assert.AnError)
+
+	err := psm.service.ChangePassword(sessionContext(), "current password", "new password")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
@@ -0,0 +1,166 @@
+package application
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Most used base words of leaked password lists, matched after undoing leet speak
+var commonPasswordWords = []string{
+	"password", "qwerty", "letmein", "welcome", "admin", "login", "iloveyou", "monkey",
+	"dragon", "master", "sunshine", "princess", "football", "baseball", "soccer", "shadow",
+	"superman", "batman", "trustno", "hello", "freedom", "whatever", "secret", "starwars",
+	"computer", "charlie", "michael", "jordan", "summer", "winter", "spring", "autumn",
+	"love", "money", "pass", "test", "user", "default", "changeme", "access",
+	"mustang", "killer", "hunter", "ranger", "pokemon", "cheese", "flower", "guest",
+}
+
+var keyboardRows = []string{"qwertyuiop", "asdfghjkl", "zxcvbnm", "1234567890"}
+
+var leetReplacer = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s", "!", "i")
+
+// Helper function to undo leet speak, one character for one character
+func unleet(password string) string {
+	return leetReplacer.Replace(password)
+}
+
+// Rough offline take on zxcvbn: the password is split greedily into dictionary words,
+// repeats, sequences and single characters, and the guesses of each piece are multiplied.
+// Scores go from 0 (under 10^3 guesses) to 4 (10^10 guesses or more).
+func passwordScore(password string, personalInputs []string) int {
+	runes := []rune(password)
+	lower := []rune(strings.ToLower(password))
+	unleeted := []rune(unleet(strings.ToLower(password)))
+	// leet replacements are one byte for one byte, so the runes stay aligned
+	if len(unleeted) != len(lower) {
+		unleeted = lower
+	}
+
+	words := append(append([]string{}, personalInputs...), commonPasswordWords...)
+	log10Guesses := 0.0
+
+	for i := 0; i < len(runes); {
+		if n := dictionaryMatch(unleeted[i:], words); n > 0 {
+			// a couple hundred words, twice for capitals or leet speak
+			guesses := float64(len(words))
+			if string(runes[i:i+n]) != string(lower[i:i+n]) {
+				guesses *= 2
+			}
+			if string(lower[i:i+n]) != string(unleeted[i:i+n]) {
+				guesses *= 2
+			}
+
+			log10Guesses += math.Log10(guesses)
+			i += n
+
+			continue
+		}
+
+		if n := max(repeatMatch(lower[i:]), sequenceMatch(lower[i:])); n >= 3 {
+			log10Guesses += math.Log10(float64(charsetSize(runes[i]) * n * 2))
+			i += n
+
+			continue
+		}
+
+		log10Guesses += math.Log10(float64(charsetSize(runes[i])))
+		i++
+	}
+
+	switch {
+	case log10Guesses < 3:
+		return 0
+	case log10Guesses < 6:
+		return 1
+	case log10Guesses < 8:
+		return 2
+	case log10Guesses < 10:
+		return 3
+	}
+
+	return 4
+}
+
+// Length of the longest word the password starts with, 0 if none
+func dictionaryMatch(password []rune, words []string) int {
+	longest := 0
+
+	for _, word := range words {
+		n := len([]rune(word))
+		if n > longest && n <= len(password) && string(password[:n]) == word {
+			longest = n
+		}
+	}
+
+	return longest
+}
+
+// Length of the run of the same character the password starts with
+func repeatMatch(password []rune) int {
+	n := 1
+	for n < len(password) && password[n] == password[0] {
+		n++
+	}
+
+	return n
+}
+
+// Length of the run of consecutive characters, like "abc", "321" or "asdf"
+func sequenceMatch(password []rune) int {
+	if len(password) < 2 {
+		return len(password)
+	}
+
+	longest := 1
+
+	for _, step := range []rune{1, -1} {
+		n := 1
+		for n < len(password) && password[n]-password[n-1] == step {
+			n++
+		}
+
+		longest = max(longest, n)
+	}
+
+	for _, row := range keyboardRows {
+		for _, keys := range []string{row, reverse(row)} {
+			start := strings.IndexRune(keys, password[0])
+			if start < 0 {
+				continue
+			}
+
+			n := 1
+			for n < len(password) && start+n < len(keys) && rune(keys[start+n]) == password[n] {
+				n++
+			}
+
+			longest = max(longest, n)
+		}
+	}
+
+	return longest
+}
+
+func charsetSize(r rune) int {
+	switch {
+	case unicode.IsDigit(r):
+		return 10
+	case unicode.IsLower(r), unicode.IsUpper(r):
+		return 26
+	case r < unicode.MaxASCII:
+		return 33
+	}
+
+	// anything outside ASCII comes from a much bigger alphabet
+	return 100
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+
+	return string(runes)
+}
@@ -0,0 +1,36 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordScore(t *testing.T) {
+	tests := []struct {
+		password string
+		score    int
+	}{
+		{"password1", 0},
+		{"qwerty123", 1},
+		{"aaaaaaaaaaaa", 0},
+		{"P@ssw0rd!", 1},
+		{"Summer2024!", 2},
+		{"Tr0ub4dor&3", 4},
+		{"correct horse battery staple", 4},
+		{"x7#Kp9!qLm2$", 4},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.score, passwordScore(tt.password, nil), tt.password)
+	}
+}
+
+func TestPasswordScore_PersonalInputs(t *testing.T) {
+	// the name counts as a dictionary word
+	assert.Less(t, passwordScore("christhian2024", []string{"christhian"}), passwordScore("christhian2024", nil))
+}
+
+func TestUnleet(t *testing.T) {
+	assert.Equal(t, "password", unleet("p@$$w0rd"))
+}
@@ -0,0 +1,68 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+)
+
+var ErrSessionNotFound = domain.NewError(domain.ErrNotFound, "Session not found")
+
+type SessionService interface {
+	ListSessions(ctx context.Context) ([]*domain.Session, error)
+	RevokeSession(ctx context.Context, sessionID string) error
+	RevokeAllSessions(ctx context.Context) error
+}
+
+type sessionService struct {
+	repo   domain.SessionRepository
+	tokens TokenService
+}
+
+func NewSessionService(repo domain.SessionRepository, tokens TokenService) SessionService {
+	return &sessionService{repo, tokens}
+}
+
+func (s *sessionService) ListSessions(ctx context.Context) ([]*domain.Session, error) {
+	principal, err := authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.repo.ListSessions(ctx, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		session.Current = session.ID == principal.SessionID
+	}
+
+	return sessions, nil
+}
+
+func (s *sessionService) RevokeSession(ctx context.Context, sessionID string) error {
+	principal, err := authenticate(ctx)
+	if err != nil {
+		return err
+	}
+
+	// someone else's session is reported as missing, not forbidden
+	err = s.tokens.RevokeSession(ctx, principal.UserID, sessionID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return ErrSessionNotFound
+	}
+
+	return err
+}
+
+// Log out everywhere, including the session making the request
+func (s *sessionService) RevokeAllSessions(ctx context.Context) error {
+	principal, err := authenticate(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.tokens.RevokeUserTokens(ctx, principal.UserID)
+}
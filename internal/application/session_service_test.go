@@ -0,0 +1,118 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type sessionServiceMock struct {
+	repo    *mocks.SessionRepository
+	tokens  *mocks.TokenService
+	service SessionService
+}
+
+func setupSessionService(t *testing.T) *sessionServiceMock {
+	mockSessionRepository := mocks.NewSessionRepository(t)
+	mockTokenService := mocks.NewTokenService(t)
+
+	return &sessionServiceMock{
+		repo:    mockSessionRepository,
+		tokens:  mockTokenService,
+		service: NewSessionService(mockSessionRepository, mockTokenService),
+	}
+}
+
+func sessionContext() context.Context {
+	return domain.WithPrincipal(context.Background(), &domain.Principal{UserID: "1", SessionID: "current", Roles: []domain.Role{domain.RoleUser}})
+}
+
+func TestListSessions_OK(t *testing.T) {
+	ssm := setupSessionService(t)
+	ssm.repo.On("ListSessions", mock.Anything, "1").Return([]*domain.Session{{ID: "current"}, {ID: "other"}}, nil)
+
+	sessions, err := ssm.service.ListSessions(sessionContext())
+
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+	assert.True(t, sessions[0].Current)
+	assert.False(t, sessions[1].Current)
+}
+
+func TestListSessions_Unauthenticated(t *testing.T) {
+	ssm := setupSessionService(t)
+
+	sessions, err := ssm.service.ListSessions(context.Background())
+
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+	assert.Nil(t, sessions)
+}
+
+func TestListSessions_ListSessionsError(t *testing.T) {
+	ssm := setupSessionService(t)
+	ssm.repo.On("ListSessions", mock.Anything, "1").Return(nil, assert.AnError)
+
+	sessions, err := ssm.service.ListSessions(sessionContext())
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+	assert.Nil(t, sessions)
+}
+
+func TestRevokeSessionService_OK(t *testing.T) {
+	ssm := setupSessionService(t)
+	ssm.tokens.On("RevokeSession", mock.Anything, "1", "other").Return(nil)
+
+	err := ssm.service.RevokeSession(sessionContext(), "other")
+
+	assert.NoError(t, err)
+}
+
+func TestRevokeSessionService_NotFound(t *testing.T) {
+	ssm := setupSessionService(t)
+	ssm.tokens.On("RevokeSession", mock.Anything, "1", "other").Return(domain.ErrNotFound)
+
+	err := ssm.service.RevokeSession(sessionContext(), "other")
+
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestRevokeSessionService_Error(t *testing.T) {
+	ssm := setupSessionService(t)
+	ssm.tokens.On("RevokeSession", mock.Anything, "1", "other").Return(assert.AnError)
+
+	err := ssm.service.RevokeSession(sessionContext(), "other")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestRevokeSessionService_Unauthenticated(t *testing.T) {
+	ssm := setupSessionService(t)
+
+	err := ssm.service.RevokeSession(context.Background(), "other")
+
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+}
+
+func TestRevokeAllSessions_OK(t *testing.T) {
+	ssm := setupSessionService(t)
+	ssm.tokens.On("RevokeUserTokens", mock.Anything, "1").Return(nil)
+
+	err := ssm.service.RevokeAllSessions(sessionContext())
+
+	assert.NoError(t, err)
+}
+
+func TestRevokeAllSessions_Unauthenticated(t *testing.T) {
+	ssm := setupSessionService(t)
+
+	err := ssm.service.RevokeAllSessions(context.Background())
+
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+}
@@ -0,0 +1,311 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+)
+
+var (
+	ErrInvalidRefreshToken = domain.NewError(domain.ErrInvalidCredentials, "Invalid refresh token")
+	ErrEmailNotVerified    = domain.NewError(domain.ErrForbidden, "Email not verified")
+	ErrScreeningPending    = domain.NewError(domain.ErrForbidden, "Account is waiting for the PLD screening")
+	ErrReviewPending       = domain.NewError(domain.ErrForbidden, "Account is waiting for a compliance review")
+	ErrAccountBlacklisted  = domain.NewError(domain.ErrForbidden, "Account is blocked")
+)
+
+type TokenService interface {
+	IssueTokens(ctx context.Context, user *domain.User, method domain.AuthMethod) (*domain.TokenPair, error)
+	RefreshTokens(ctx context.Context, refreshToken string) (*domain.TokenPair, error)
+	RevokeTokens(ctx context.Context, token *domain.AccessToken, refreshToken string) error
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+	RevokeOtherSessions(ctx context.Context, userID, sessionID string) error
+	RevokeUserTokens(ctx context.Context, userID string) error
+	IsRevoked(ctx context.Context, token *domain.AccessToken) (bool, error)
+}
+
+type tokenService struct {
+	signer     domain.TokenSigner
+	repo       domain.RefreshTokenRepository
+	revoked    domain.RevokedTokenRepository
+	sessions   domain.SessionRepository
+	userRepo   domain.UserRepository
+	policy     domain.UnverifiedEmailPolicy
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+func NewTokenService(signer domain.TokenSigner, repo domain.RefreshTokenRepository, revoked domain.RevokedTokenRepository, sessions domain.SessionRepository, userRepo domain.UserRepository, policy domain.UnverifiedEmailPolicy, accessTTL, refreshTTL time.Duration) TokenService {
+	return &tokenService{signer, repo, revoked, sessions, userRepo, policy, accessTTL, refreshTTL}
+}
+
+func (t *tokenService) IssueTokens(ctx context.Context, user *domain.User, method domain.AuthMethod) (*domain.TokenPair, error) {
+	familyID, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.issue(ctx, user, familyID, method)
+}
+
+func (t *tokenService) RefreshTokens(ctx context.Context, refreshToken string) (*domain.TokenPair, error) {
+	tokenHash := hashToken(refreshToken)
+
+	stored, err := t.repo.GetRefreshToken(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+
+		return nil, err
+	}
+
+	// a rotated or revoked token being presented again means it leaked,
+	// so the whole family is revoked
+	if !stored.UsedAt.IsZero() || !stored.RevokedAt.IsZero() {
+		return nil, t.revokeFamily(ctx, stored.UserID, stored.FamilyID)
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	used, err := t.repo.UseRefreshToken(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// lost a race against another request presenting the same token
+	if !used {
+		return nil, t.revokeFamily(ctx, stored.UserID, stored.FamilyID)
+	}
+
+	// the user is loaded again so a verified email lifts the restriction
+	user, err := t.userRepo.GetUser(ctx, stored.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// refresh tokens stored before auth methods were tracked
+	method := stored.AuthMethod
+	if method == "" {
+		method = domain.PasswordAuth
+	}
+
+	return t.issue(ctx, user, stored.FamilyID, method)
+}
+
+func (t *tokenService) RevokeTokens(ctx context.Context, token *domain.AccessToken, refreshToken string) error {
+	if err := t.revoked.RevokeToken(ctx, token.ID, token.ExpiresAt); err != nil {
+		return err
+	}
+
+	// logging out ends the session of the access token
+	if token.SessionID != "" {
+		if err := t.endSession(ctx, token.UserID, token.SessionID); err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	stored, err := t.repo.GetRefreshToken(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	// a caller can only end its own sessions
+	if stored.UserID != token.UserID {
+		return nil
+	}
+
+	if stored.FamilyID != token.SessionID {
+		if err := t.endSession(ctx, stored.UserID, stored.FamilyID); err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+	}
+
+	return t.repo.RevokeRefreshTokenFamily(ctx, stored.FamilyID)
+}
+
+func (t *tokenService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	if err := t.endSession(ctx, userID, sessionID); err != nil {
+		return err
+	}
+
+	return t.repo.RevokeRefreshTokenFamily(ctx, sessionID)
+}
+
+// Ends every session of the user but the given one, which keeps working
+func (t *tokenService) RevokeOtherSessions(ctx context.Context, userID, sessionID string) error {
+	if err := t.repo.RevokeOtherRefreshTokens(ctx, userID, sessionID); err != nil {
+		return err
+	}
+
+	sessions, err := t.sessions.ListSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.ID == sessionID {
+			continue
+		}
+
+		if err = t.endSession(ctx, userID, session.ID); err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *tokenService) RevokeUserTokens(ctx context.Context, userID string) error {
+	if err := t.repo.RevokeUserRefreshTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := t.sessions.RevokeUserSessions(ctx, userID); err != nil {
+		return err
+	}
+
+	// iat only has second precision, a generation tells tokens apart even within the same second
+	return t.revoked.RevokeUserTokens(ctx, userID)
+}
+
+func (t *tokenService) IsRevoked(ctx context.Context, token *domain.AccessToken) (bool, error) {
+	return t.revoked.IsTokenRevoked(ctx, token)
+}
+
+func (t *tokenService) issue(ctx context.Context, user *domain.User, familyID string, method domain.AuthMethod) (*domain.TokenPair, error) {
+	if !user.EmailVerified && t.policy == domain.DenyUnverified {
+		return nil, ErrEmailNotVerified
+	}
+
+	switch user.ScreeningStatus {
+	case domain.ScreeningPending:
+		return nil, ErrScreeningPending
+	case domain.ScreeningPendingReview:
+		return nil, ErrReviewPending
+	case domain.ScreeningBlacklisted:
+		return nil, ErrAccountBlacklisted
+	}
+
+	// read before signing, a revocation racing with the login rejects the new token too
+	generation, err := t.revoked.GetTokenGeneration(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	tokenID, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := t.signer.Sign(&domain.AccessToken{
+		ID:         tokenID,
+		UserID:     user.ID,
+		IssuedAt:   now,
+		ExpiresAt:  now.Add(t.accessTTL),
+		Restricted: !user.EmailVerified && t.policy == domain.RestrictUnverified,
+		Roles:      user.Roles,
+		AuthMethod: method,
+		SessionID:  familyID,
+		Generation: generation,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.repo.CreateRefreshToken(ctx, &domain.RefreshToken{
+		Hash:       hashToken(refreshToken),
+		UserID:     user.ID,
+		FamilyID:   familyID,
+		AuthMethod: method,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(t.refreshTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// created on login and touched on every refresh, the device
+	// may have changed network since
+	err = t.sessions.SaveSession(ctx, &domain.Session{
+		ID:         familyID,
+		UserID:     user.ID,
+		UserAgent:  domain.UserAgentFromContext(ctx),
+		IP:         domain.ClientIPFromContext(ctx),
+		AuthMethod: method,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(t.refreshTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    t.accessTTL,
+	}, nil
+}
+
+func (t *tokenService) revokeFamily(ctx context.Context, userID, familyID string) error {
+	if err := t.repo.RevokeRefreshTokenFamily(ctx, familyID); err != nil {
+		return err
+	}
+
+	if err := t.endSession(ctx, userID, familyID); err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+
+	return ErrInvalidRefreshToken
+}
+
+// Marks the session as revoked and blacklists it so the access tokens
+// still in flight are rejected right away
+func (t *tokenService) endSession(ctx context.Context, userID, sessionID string) error {
+	if err := t.sessions.RevokeSession(ctx, userID, sessionID); err != nil {
+		return err
+	}
+
+	return t.revoked.RevokeSession(ctx, sessionID, time.Now().Add(t.accessTTL))
+}
+
+// Helper function to generate opaque url-safe tokens
+func randomToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Helper function to store tokens without keeping them in plain text
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,636 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type tokenServiceMock struct {
+	signer   *mocks.TokenSigner
+	repo     *mocks.RefreshTokenRepository
+	revoked  *mocks.RevokedTokenRepository
+	sessions *mocks.SessionRepository
+	users    *mocks.UserRepository
+	service  TokenService
+}
+
+func setupTokenService(t *testing.T) *tokenServiceMock {
+	mockTokenSigner := mocks.NewTokenSigner(t)
+	mockRefreshTokenRepository := mocks.NewRefreshTokenRepository(t)
+	mockRevokedTokenRepository := mocks.NewRevokedTokenRepository(t)
+	mockSessionRepository := mocks.NewSessionRepository(t)
+	mockUserRepository := mocks.NewUserRepository(t)
+
+	return &tokenServiceMock{
+		signer:   mockTokenSigner,
+		repo:     mockRefreshTokenRepository,
+		revoked:  mockRevokedTokenRepository,
+		sessions: mockSessionRepository,
+		users:    mockUserRepository,
+		service:  NewTokenService(mockTokenSigner, mockRefreshTokenRepository, mockRevokedTokenRepository, mockSessionRepository, mockUserRepository, domain.RestrictUnverified, time.Minute, time.Hour),
+	}
+}
+
+func TestIssueTokens_OK(t *testing.T) {
+	checkAccessToken := func(token *domain.AccessToken) bool {
+		return token.UserID == "1" && token.ID != "" && !token.Restricted && token.ExpiresAt.Sub(token.IssuedAt) == time.Minute
+	}
+	checkRefreshToken := func(token *domain.RefreshToken) bool {
+		return token.UserID == "1" && token.FamilyID != "" && token.ExpiresAt.Sub(token.CreatedAt) == time.Hour
+	}
+
+	tsm := setupTokenService(t)
+	tsm.revoked.On("GetTokenGeneration", mock.Anything, mock.AnythingOfType("string")).Return(int64(0), nil)
+	tsm.signer.On("Sign", mock.MatchedBy(checkAccessToken)).Return("access", nil)
+	tsm.repo.On("CreateRefreshToken", mock.IsType(nil), mock.MatchedBy(checkRefreshToken)).Return(nil)
+	tsm.sessions.On("SaveSession", mock.IsType(nil), mock.AnythingOfType("*domain.Session")).Return(nil)
+
+	pair, err := tsm.service.IssueTokens(context.Context(nil), &domain.User{ID: "1", EmailVerified: true}, domain.PasswordAuth)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "access", pair.AccessToken)
+	assert.NotEmpty(t, pair.RefreshToken)
+	assert.Equal(t, time.Minute, pair.ExpiresIn)
+	tsm.repo.AssertCalled(t, "CreateRefreshToken", mock.IsType(nil), mock.MatchedBy(func(token *domain.RefreshToken) bool {
+		return token.Hash == hashToken(pair.RefreshToken)
+	}))
+}
+
+func TestIssueTokens_Generation(t *testing.T) {
+	withGeneration := func(token *domain.AccessToken) bool {
+		return token.Generation == 3
+	}
+
+	tsm := setupTokenService(t)
+	tsm.revoked.On("GetTokenGeneration", mock.IsType(nil), "1").Return(int64(3), nil)
+	tsm.signer.On("Sign", mock.MatchedBy(withGeneration)).Return("access", nil)
+	tsm.repo.On("CreateRefreshToken", mock.IsType(nil), mock.AnythingOfType("*domain.RefreshToken")).Return(nil)
+	tsm.sessions.On("SaveSession", mock.IsType(nil), mock.AnythingOfType("*domain.Session")).Return(nil)
+
+	_, err := tsm.service.IssueTokens(context.Context(nil), &domain.User{ID: "1", EmailVerified: true}, domain.PasswordAuth)
+
+	assert.NoError(t, err)
+}
+
+func TestIssueTokens_GetTokenGenerationError(t *testing.T) {
+	tsm := setupTokenService(t)
+	tsm.revoked.On("GetTokenGeneration", mock.IsType(nil), "1").Return(int64(0), assert.AnError)
+
+	pair, err := tsm.service.IssueTokens(context.Context(nil), &domain.User{ID: "1", EmailVerified: true}, domain.PasswordAuth)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, pair)
+}
+
+func TestIssueTokens_Roles(t *testing.T) {
+	withRoles := func(token *domain.AccessToken) bool {
+		return len(token.Roles) == 2 && token.Roles[1] == domain.RoleAdmin
+	}
+
+	tsm := setupTokenService(t)
+	tsm.revoked.On("GetTokenGeneration", mock.Anything, mock.AnythingOfType("string")).Return(int64(0), nil)
+	tsm.signer.On("Sign", mock.MatchedBy(withRoles)).Return("access", nil)
+	tsm.repo.On("CreateRefreshToken", mock.IsType(nil), mock.AnythingOfType("*domain.RefreshToken")).Return(nil)
+	tsm.sessions.On("SaveSession", mock.IsType(nil), mock.AnythingOfType("*domain.Session")).Return(nil)
+
+	_, err := tsm.service.IssueTokens(context.Context(nil), &domain.User{ID: "1", EmailVerified: true, Roles: []domain.Role{domain.RoleUser, domain.RoleAdmin}}, domain.PasswordAuth)
+
+	assert.NoError(t, err)
+}
+
+func TestIssueTokens_RestrictedUnverified(t *testing.T) {
+	restricted := func(token *domain.AccessToken) bool {
+		return token.UserID == "1" && token.Restricted
+	}
+
+	tsm := setupTokenService(t)
+	tsm.revoked.On("GetTokenGeneration", mock.Anything, mock.AnythingOfType("string")).Return(int64(0), nil)
+	tsm.signer.On("Sign", mock.MatchedBy(restricted)).Return("access", nil)
+	tsm.repo.On("CreateRefreshToken", mock.IsType(nil), mock.AnythingOfType("*domain.RefreshToken")).Return(nil)
+	tsm.sessions.On("SaveSession", mock.IsType(nil), mock.AnythingOfType("*domain.Session")).Return(nil)
+
+	pair, err := tsm.service.IssueTokens(context.Context(nil), &domain.User{ID: "1"}, domain.PasswordAuth)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "access", pair.AccessToken)
+}
+
+func TestIssueTokens_AllowUnverified(t *testing.T) {
+	unrestricted := func(token *domain.AccessToken) bool {
+		return token.UserID == "1" && !token.Restricted
+	}
+
+	tsm := setupTokenService(t)
+	tsm.service.(*tokenService).policy = domain.AllowUnverified
+	tsm.revoked.On("GetTokenGeneration", mock.Anything, mock.AnythingOfType("string")).Return(int64(0), nil)
+	tsm.signer.On("Sign", mock.MatchedBy(unrestricted)).Return("access", nil)
+	tsm.repo.On("CreateRefreshToken", mock.IsType(nil), mock.AnythingOfType("*domain.RefreshToken")).Return(nil)
+	tsm.sessions.On("SaveSession", mock.IsType(nil), mock.AnythingOfType("*domain.Session")).Return(nil)
+
+	pair, err := tsm.service.IssueTokens(context.Context(nil), &domain.User{ID: "1"}, domain.PasswordAuth)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "access", pair.AccessToken)
+}
+
+func TestIssueTokens_DenyUnverified(t *testing.T) {
+	tsm := setupTokenService(t)
+	tsm.service.(*tokenService).policy = domain.DenyUnverified
+
+	pair, err := tsm.service.IssueTokens(context.Context(nil), &domain.User{ID: "1"}, domain.PasswordAuth)
+
+	assert.ErrorIs(t, err, ErrEmailNotVerified)
+	assert.Nil(t, pair)
+}
+
+func TestIssueTokens_ScreeningPending(t *testing.T) {
+	tsm := setupTokenService(t)
+
+	pair, err := tsm.service.IssueTokens(context.Context(nil), &domain.User{ID: "1", ScreeningStatus: domain.ScreeningPending}, domain.PasswordAuth)
+
+	assert.ErrorIs(t, err, ErrScreeningPending)
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	assert.Nil(t, pair)
+}
+
+func TestIssueTokens_ReviewPending(t *testing.T) {
+	tsm := setupTokenService(t)
+
+	pair, err := tsm.service.IssueTokens(context.Context(nil), &domain.User{ID: "1", ScreeningStatus: domain.ScreeningPendingReview}, domain.PasswordAuth)
+
+	assert.ErrorIs(t, err, ErrReviewPending)
+	assert.Nil(t, pair)
+}
+
+func TestIssueTokens_ScreeningBlacklisted(t *testing.T) {
+	tsm := setupTokenService(t)
+
+	pair, err := tsm.service.IssueTokens(context.Context(nil), &domain.User{ID: "1", ScreeningStatus: domain.ScreeningBlacklisted}, domain.PasswordAuth)
+
+	assert.ErrorIs(t, err, ErrAccountBlacklisted)
+	assert.Nil(t, pair)
+}
+
+func TestIssueTokens_SignError(t *testing.T) {
+	tsm := setupTokenService(t)
+	tsm.revoked.On("GetTokenGeneration", mock.Anything, mock.AnythingOfType("string")).Return(int64(0), nil)
+	tsm.signer.On("Sign", mock.AnythingOfType("*domain.AccessToken")).Return("", assert.AnError)
+
+	pair, err := tsm.service.IssueTokens(context.Context(nil), &domain.User{ID: "1", EmailVerified: true}, domain.PasswordAuth)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+	assert.Nil(t, pair)
+}
+
+func TestIssueTokens_CreateRefreshTokenError(t *testing.T) {
+	tsm := setupTokenService(t)
+	tsm.revoked.On("GetTokenGeneration", mock.Anything, mock.AnythingOfType("string")).Return(int64(0), nil)
+	tsm.signer.On("Sign", mock.AnythingOfType("*domain.AccessToken")).Return("access", nil)
+	tsm.repo.On("CreateRefreshToken", mock.IsType(nil), mock.AnythingOfType("*domain.RefreshToken")).Return(assert.AnError)
+
+	pair, err := tsm.service.IssueTokens(context.Context(nil), &domain.User{ID: "1", EmailVerified: true}, domain.PasswordAuth)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+	assert.Nil(t, pair)
+}
+
+func TestIssueTokens_Session(t *testing.T) {
+	ctx := domain.WithUserAgent(domain.WithClientIP(context.Background(), "192.0.2.1"), "curl/8.0")
+	var sessionID string
+	checkAccessToken := func(token *domain.AccessToken) bool {
+		sessionID = token.SessionID
+		return token.SessionID != ""
+	}
+	checkSession := func(session *domain.Session) bool {
+		return session.ID == sessionID && session.UserID == "1" && session.IP == "192.0.2.1" && session.UserAgent == "curl/8.0" &&
+			session.AuthMethod == domain.TOTPAuth && session.ExpiresAt.Sub(session.CreatedAt) == time.Hour
+	}
+
+	tsm := setupTokenService(t)
+	tsm.revoked.On("GetTokenGeneration", mock.Anything, mock.AnythingOfType("string")).Return(int64(0), nil)
+	tsm.signer.On("Sign", mock.MatchedBy(checkAccessToken)).Return("access", nil)
+	tsm.repo.On("CreateRefreshToken", mock.Anything, mock.AnythingOfType("*domain.RefreshToken")).Return(nil)
+	tsm.sessions.On("SaveSession", mock.Anything, mock.MatchedBy(checkSession)).Return(nil)
+
+	_, err := tsm.service.IssueTokens(ctx, &domain.User{ID: "1", EmailVerified: true}, domain.TOTPAuth)
+
+	assert.NoError(t, err)
+	tsm.repo.AssertCalled(t, "CreateRefreshToken", mock.Anything, mock.MatchedBy(func(token *domain.RefreshToken) bool {
+		return token.FamilyID == sessionID
+	}))
+}
+
+func TestIssueTokens_SaveSessionError(t *testing.T) {
+	tsm := setupTokenService(t)
+	tsm.revoked.On("GetTokenGeneration", mock.Anything, mock.AnythingOfType("string")).Return(int64(0), nil)
+	tsm.signer.On("Sign", mock.AnythingOfType("*domain.AccessToken")).Return("access", nil)
+	tsm.repo.On("CreateRefreshToken", mock.IsType(nil), mock.AnythingOfType("*domain.RefreshToken")).Return(nil)
+	tsm.sessions.On("SaveSession", mock.IsType(nil), mock.AnythingOfType("*domain.Session")).Return(assert.AnError)
+
+	pair, err := tsm.service.IssueTokens(context.Context(nil), &domain.User{ID: "1", EmailVerified: true}, domain.PasswordAuth)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+	assert.Nil(t, pair)
+}
+
+func TestRefreshTokens_OK(t *testing.T) {
+	stored := &domain.RefreshToken{UserID: "1", FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour)}
+	sameFamily := func(token *domain.RefreshToken) bool {
+		return token.UserID == "1" && token.FamilyID == "family"
+	}
+
+	tsm := setupTokenService(t)
+	tsm.repo.On("GetRefreshToken", mock.IsType(nil), hashToken("token")).Return(stored, nil)
+	tsm.repo.On("UseRefreshToken", mock.IsType(nil), hashToken("token")).Return(true, nil)
+	tsm.users.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1", EmailVerified: true}, nil)
+	tsm.revoked.On("GetTokenGeneration", mock.Anything, mock.AnythingOfType("string")).Return(int64(0), nil)
+	tsm.signer.On("Sign", mock.AnythingOfType("*domain.AccessToken")).Return("access", nil)
+	tsm.repo.On("CreateRefreshToken", mock.IsType(nil), mock.MatchedBy(sameFamily)).Return(nil)
+	tsm.sessions.On("SaveSession", mock.IsType(nil), mock.AnythingOfType("*domain.Session")).Return(nil)
+
+	pair, err := tsm.service.RefreshTokens(context.Context(nil), "token")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "access", pair.AccessToken)
+	assert.NotEqual(t, "token", pair.RefreshToken)
+}
+
+func TestRefreshTokens_KeepsAuthMethod(t *testing.T) {
+	stored := &domain.RefreshToken{UserID: "1", FamilyID: "family", AuthMethod: domain.TOTPAuth, ExpiresAt: time.Now().Add(time.Hour)}
+	withTOTP := func(token *domain.AccessToken) bool {
+		return token.AuthMethod == domain.TOTPAuth
+	}
+	storedTOTP := func(token *domain.RefreshToken) bool {
+		return token.AuthMethod == domain.TOTPAuth
+	}
+
+	tsm := setupTokenService(t)
+	tsm.repo.On("GetRefreshToken", mock.IsType(nil), hashToken("token")).Return(stored, nil)
+	tsm.repo.On("UseRefreshToken", mock.IsType(nil), hashToken("token")).Return(true, nil)
+	tsm.users.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1", EmailVerified: true}, nil)
+	tsm.revoked.On("GetTokenGeneration", mock.Anything, mock.AnythingOfType("string")).Return(int64(0), nil)
+	tsm.signer.On("Sign", mock.MatchedBy(withTOTP)).Return("access", nil)
+	tsm.repo.On("CreateRefreshToken", mock.IsType(nil), mock.MatchedBy(storedTOTP)).Return(nil)
+	tsm.sessions.On("SaveSession", mock.IsType(nil), mock.AnythingOfType("*domain.Session")).Return(nil)
+
+	_, err := tsm.service.RefreshTokens(context.Context(nil), "token")
+
+	assert.NoError(t, err)
+}
+
+func TestRefreshTokens_LegacyAuthMethod(t *testing.T) {
+	stored := &domain.RefreshToken{UserID: "1", FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour)}
+	withPassword := func(token *domain.AccessToken) bool {
+		return token.AuthMethod == domain.PasswordAuth
+	}
+
+	tsm := setupTokenService(t)
+	tsm.repo.On("GetRefreshToken", mock.IsType(nil), hashToken("token")).Return(stored, nil)
+	tsm.repo.On("UseRefreshToken", mock.IsType(nil), hashToken("token")).Return(true, nil)
+	tsm.users.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1", EmailVerified: true}, nil)
+	tsm.revoked.On("GetTokenGeneration", mock.Anything, mock.AnythingOfType("string")).Return(int64(0), nil)
+	tsm.signer.On("Sign", mock.MatchedBy(withPassword)).Return("access", nil)
+	tsm.repo.On("CreateRefreshToken", mock.IsType(nil), mock.AnythingOfType("*domain.RefreshToken")).Return(nil)
+	tsm.sessions.On("SaveSession", mock.IsType(nil), mock.AnythingOfType("*domain.Session")).Return(nil)
+
+	_, err := tsm.service.RefreshTokens(context.Context(nil), "token")
+
+	assert.NoError(t, err)
+}
+
+func TestRefreshTokens_GetUserError(t *testing.T) {
+	stored := &domain.RefreshToken{UserID: "1", FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour)}
+
+	tsm := setupTokenService(t)
+	tsm.repo.On("GetRefreshToken", mock.IsType(nil), mock.AnythingOfType("string")).Return(stored, nil)
+	tsm.repo.On("UseRefreshToken", mock.IsType(nil), mock.AnythingOfType("string")).Return(true, nil)
+	tsm.users.On("GetUser", mock.IsType(nil), "1").Return(nil, assert.AnError)
+
+	pair, err := tsm.service.RefreshTokens(context.Context(nil), "token")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+	assert.Nil(t, pair)
+}
+
+func TestRefreshTokens_NotFound(t *testing.T) {
+	tsm := setupTokenService(t)
+	tsm.repo.On("GetRefreshToken", mock.IsType(nil), mock.AnythingOfType("string")).Return(nil, domain.ErrNotFound)
+
+	pair, err := tsm.service.RefreshTokens(context.Context(nil), "token")
+
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	assert.Nil(t, pair)
+}
+
+func TestRefreshTokens_GetRefreshTokenError(t *testing.T) {
+	tsm := setupTokenService(t)
+	tsm.repo.On("GetRefreshToken", mock.IsType(nil), mock.AnythingOfType("string")).Return(nil, assert.AnError)
+
+	pair, err := tsm.service.RefreshTokens(context.Context(nil), "token")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+	assert.Nil(t, pair)
+}
+
+func TestRefreshTokens_ReusedRevokesFamily(t *testing.T) {
+	stored := &domain.RefreshToken{UserID: "1", FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour), UsedAt: time.Now()}
+
+	tsm := setupTokenService(t)
+	tsm.repo.On("GetRefreshToken", mock.IsType(nil), mock.AnythingOfType("string")).Return(stored, nil)
+	tsm.repo.On("RevokeRefreshTokenFamily", mock.IsType(nil), "family").Return(nil)
+	tsm.sessions.On("RevokeSession", mock.IsType(nil), "1", "family").Return(nil)
+	tsm.revoked.On("RevokeSession", mock.IsType(nil), "family", mock.AnythingOfType("time.Time")).Return(nil)
+
+	pair, err := tsm.service.RefreshTokens(context.Context(nil), "token")
+
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	assert.Nil(t, pair)
+}
+
+func TestRefreshTokens_RevokeFamilyError(t *testing.T) {
+	stored := &domain.RefreshToken{UserID: "1", FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour), RevokedAt: time.Now()}
+
+	tsm := setupTokenService(t)
+	tsm.repo.On("GetRefreshToken", mock.IsType(nil), mock.AnythingOfType("string")).Return(stored, nil)
+	tsm.repo.On("RevokeRefreshTokenFamily", mock.IsType(nil), "family").Return(assert.AnError)
+
+	pair, err := tsm.service.RefreshTokens(context.Context(nil), "token")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+	assert.Nil(t, pair)
+}
+
+func TestRefreshTokens_Expired(t *testing.T) {
+	stored := &domain.RefreshToken{UserID: "1", FamilyID: "family", ExpiresAt: time.Now().Add(-time.Hour)}
+
+	tsm := setupTokenService(t)
+	tsm.repo.On("GetRefreshToken", mock.IsType(nil), mock.AnythingOfType("string")).Return(stored, nil)
+
+	pair, err := tsm.service.RefreshTokens(context.Context(nil), "token")
+
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	assert.Nil(t, pair)
+}
+
+func TestRefreshTokens_UseRefreshTokenError(t *testing.T) {
+	stored := &domain.RefreshToken{UserID: "1", FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour)}
+
+	tsm := setupTokenService(t)
+	tsm.repo.On("GetRefreshToken", mock.IsType(nil), mock.AnythingOfType("string")).Return(stored, nil)
+	tsm.repo.On("UseRefreshToken", mock.IsType(nil), mock.AnythingOfType("string")).Return(false, assert.AnError)
+
+	pair, err := tsm.service.RefreshTokens(context.Context(nil), "token")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+	assert.Nil(t, pair)
+}
+
+func TestRefreshTokens_ConcurrentUseRevokesFamily(t *testing.T) {
+	stored := &domain.RefreshToken{UserID: "1", FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour)}
+
+	tsm := setupTokenService(t)
+	tsm.repo.On("GetRefreshToken", mock.IsType(nil), mock.AnythingOfType("string")).Return(stored, nil)
+	tsm.repo.On("UseRefreshToken", mock.IsType(nil), mock.AnythingOfType("string")).Return(false, nil)
+	tsm.repo.On("RevokeRefreshTokenFamily", mock.IsType(nil), "family").Return(nil)
+	tsm.sessions.On("RevokeSession", mock.IsType(nil), "1", "family").Return(nil)
+	tsm.revoked.On("RevokeSession", mock.IsType(nil), "family", mock.AnythingOfType("time.Time")).Return(nil)
+
+	pair, err := tsm.service.RefreshTokens(context.Context(nil), "token")
+
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	assert.Nil(t, pair)
+}
+
+func TestRevokeTokens_AccessTokenOnly(t *testing.T) {
+	token := &domain.AccessToken{ID: "jti", UserID: "1", ExpiresAt: time.Now()}
+
+	tsm := setupTokenService(t)
+	tsm.revoked.On("RevokeToken", mock.IsType(nil), "jti", token.ExpiresAt).Return(nil)
+
+	err := tsm.service.RevokeTokens(context.Context(nil), token, "")
+
+	assert.NoError(t, err)
+}
+
+func TestRevokeTokens_EndsSession(t *testing.T) {
+	token := &domain.AccessToken{ID: "jti", UserID: "1", SessionID: "family", ExpiresAt: time.Now()}
+
+	tsm := setupTokenService(t)
+	tsm.revoked.On("RevokeToken", mock.IsType(nil), "jti", token.ExpiresAt).Return(nil)
+	tsm.sessions.On("RevokeSession", mock.IsType(nil), "1", "family").Return(nil)
+	tsm.revoked.On("RevokeSession", mock.IsType(nil), "family", mock.AnythingOfType("time.Time")).Return(nil)
+
+	err := tsm.service.RevokeTokens(context.Context(nil), token, "")
+
+	assert.NoError(t, err)
+}
+
+func TestRevokeTokens_SessionAlreadyEnded(t *testing.T) {
+	token := &domain.AccessToken{ID: "jti", UserID: "1", SessionID: "family", ExpiresAt: time.Now()}
+
+	tsm := setupTokenService(t)
+	tsm.revoked.On("RevokeToken", mock.IsType(nil), "jti", token.ExpiresAt).Return(nil)
+	tsm.sessions.On("RevokeSession", mock.IsType(nil), "1", "family").Return(domain.ErrNotFound)
+
+	err := tsm.service.RevokeTokens(context.Context(nil), token, "")
+
+	assert.NoError(t, err)
+	tsm.revoked.AssertNotCalled(t, "RevokeSession", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRevokeTokens_RevokeSessionError(t *testing.T) {
+	tsm := setupTokenService(t)
+	tsm.revoked.On("RevokeToken", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
+	tsm.sessions.On("RevokeSession", mock.IsType(nil), "1", "family").Return(assert.AnError)
+
+	err := tsm.service.RevokeTokens(context.Context(nil), &domain.AccessToken{UserID: "1", SessionID: "family"}, "")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestRevokeTokens_RevokeTokenError(t *testing.T) {
+	tsm := setupTokenService(t)
+	tsm.revoked.On("RevokeToken", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(assert.AnError)
+
+	err := tsm.service.RevokeTokens(context.Context(nil), &domain.AccessToken{}, "token")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestRevokeTokens_RefreshTokenFamily(t *testing.T) {
+	stored := &domain.RefreshToken{UserID: "1", FamilyID: "family"}
+
+	tsm := setupTokenService(t)
+	tsm.revoked.On("RevokeToken", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
+	tsm.repo.On("GetRefreshToken", mock.IsType(nil), hashToken("token")).Return(stored, nil)
+	tsm.repo.On("RevokeRefreshTokenFamily", mock.IsType(nil), "family").Return(nil)
+	tsm.sessions.On("RevokeSession", mock.IsType(nil), "1", "family").Return(nil)
+	tsm.revoked.On("RevokeSession", mock.IsType(nil), "family", mock.AnythingOfType("time.Time")).Return(nil)
+
+	err := tsm.service.RevokeTokens(context.Context(nil), &domain.AccessToken{UserID: "1"}, "token")
+
+	assert.NoError(t, err)
+}
+
+func TestRevokeTokens_RefreshTokenNotFound(t *testing.T) {
+	tsm := setupTokenService(t)
+	tsm.revoked.On("RevokeToken", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
+	tsm.repo.On("GetRefreshToken", mock.IsType(nil), mock.AnythingOfType("string")).Return(nil, domain.ErrNotFound)
+
+	err := tsm.service.RevokeTokens(context.Context(nil), &domain.AccessToken{UserID: "1"}, "token")
+
+	assert.NoError(t, err)
+}
+
+func TestRevokeTokens_GetRefreshTokenError(t *testing.T) {
+	tsm := setupTokenService(t)
+	tsm.revoked.On("RevokeToken", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
+	tsm.repo.On("GetRefreshToken", mock.IsType(nil), mock.AnythingOfType("string")).Return(nil, assert.AnError)
+
+	err := tsm.service.RevokeTokens(context.Context(nil), &domain.AccessToken{UserID: "1"}, "token")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestRevokeTokens_RefreshTokenFromOtherUser(t *testing.T) {
+	stored := &domain.RefreshToken{UserID: "2", FamilyID: "family"}
+
+	tsm := setupTokenService(t)
+	tsm.revoked.On("RevokeToken", mock.IsType(nil), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
+	tsm.repo.On("GetRefreshToken", mock.IsType(nil), mock.AnythingOfType("string")).Return(stored, nil)
+
+	err := tsm.service.RevokeTokens(context.Context(nil), &domain.AccessToken{UserID: "1"}, "token")
+
+	assert.NoError(t, err)
+	tsm.repo.AssertNotCalled(t, "RevokeRefreshTokenFamily", mock.Anything, mock.Anything)
+}
+
+func TestRevokeSession_OK(t *testing.T) {
+	checkExpiration := func(expiresAt time.Time) bool {
+		return expiresAt.After(time.Now())
+	}
+
+	tsm := setupTokenService(t)
+	tsm.sessions.On("RevokeSession", mock.IsType(nil), "1", "family").Return(nil)
+	tsm.revoked.On("RevokeSession", mock.IsType(nil), "family", mock.MatchedBy(checkExpiration)).Return(nil)
+	tsm.repo.On("RevokeRefreshTokenFamily", mock.IsType(nil), "family").Return(nil)
+
+	err := tsm.service.RevokeSession(context.Context(nil), "1", "family")
+
+	assert.NoError(t, err)
+}
+
+func TestRevokeSession_NotFound(t *testing.T) {
+	tsm := setupTokenService(t)
+	tsm.sessions.On("RevokeSession", mock.IsType(nil), "1", "family").Return(domain.ErrNotFound)
+
+	err := tsm.service.RevokeSession(context.Context(nil), "1", "family")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	tsm.repo.AssertNotCalled(t, "RevokeRefreshTokenFamily", mock.Anything, mock.Anything)
+}
+
+func TestRevokeSession_BlacklistError(t *testing.T) {
+	tsm := setupTokenService(t)
+	tsm.sessions.On("RevokeSession", mock.IsType(nil), "1", "family").Return(nil)
+	tsm.revoked.On("RevokeSession", mock.IsType(nil), "family", mock.AnythingOfType("time.Time")).Return(assert.AnError)
+
+	err := tsm.service.RevokeSession(context.Context(nil), "1", "family")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestRevokeOtherSessions_OK(t *testing.T) {
+	tsm := setupTokenService(t)
+	tsm.repo.On("RevokeOtherRefreshTokens", mock.IsType(nil), "1", "current").Return(nil)
+	tsm.sessions.On("ListSessions", mock.IsType(nil), "1").Return([]*domain.Session{{ID: "current"}, {ID: "other"}}, nil)
+	tsm.sessions.On("RevokeSession", mock.IsType(nil), "1", "other").Return(nil)
+	tsm.revoked.On("RevokeSession", mock.IsType(nil), "other", mock.AnythingOfType("time.Time")).Return(nil)
+
+	err := tsm.service.RevokeOtherSessions(context.Context(nil), "1", "current")
+
+	assert.NoError(t, err)
+	tsm.sessions.AssertNotCalled(t, "RevokeSession", mock.Anything, "1", "current")
+}
+
+func TestRevokeOtherSessions_RevokeOtherRefreshTokensError(t *testing.T) {
+	tsm := setupTokenService(t)
+	tsm.repo.On("RevokeOtherRefreshTokens", mock.IsType(nil), "1", "current").Return(assert.AnError)
+
+	err := tsm.service.RevokeOtherSessions(context.Context(nil), "1", "current")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestRevokeOtherSessions_ListSessionsError(t *testing.T) {
+	tsm := setupTokenService(t)
+	tsm.repo.On("RevokeOtherRefreshTokens", mock.IsType(nil), "1", "current").Return(nil)
+	tsm.sessions.On("ListSessions", mock.IsType(nil), "1").Return(nil, assert.AnError)
+
+	err := tsm.service.RevokeOtherSessions(context.Context(nil), "1", "current")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestRevokeUserTokens_OK(t *testing.T) {
+	tsm := setupTokenService(t)
+	tsm.repo.On("RevokeUserRefreshTokens", mock.IsType(nil), "1").Return(nil)
+	tsm.sessions.On("RevokeUserSessions", mock.IsType(nil), "1").Return(nil)
+	tsm.revoked.On("RevokeUserTokens", mock.IsType(nil), "1").Return(nil)
+
+	err := tsm.service.RevokeUserTokens(context.Context(nil), "1")
+
+	assert.NoError(t, err)
+}
+
+func TestRevokeUserTokens_RevokeUserRefreshTokensError(t *testing.T) {
+	tsm := setupTokenService(t)
+	tsm.repo.On("RevokeUserRefreshTokens", mock.IsType(nil), "1").Return(assert.AnError)
+
+	err := tsm.service.RevokeUserTokens(context.Context(nil), "1")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestRevokeUserTokens_RevokeUserSessionsError(t *testing.T) {
+	tsm := setupTokenService(t)
+	tsm.repo.On("RevokeUserRefreshTokens", mock.IsType(nil), "1").Return(nil)
+	tsm.sessions.On("RevokeUserSessions", mock.IsType(nil), "1").Return(assert.AnError)
+
+	err := tsm.service.RevokeUserTokens(context.Context(nil), "1")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestIsRevoked_OK(t *testing.T) {
+	token := &domain.AccessToken{ID: "jti"}
+
+	tsm := setupTokenService(t)
+	tsm.revoked.On("IsTokenRevoked", mock.IsType(nil), token).Return(true, nil)
+
+	revoked, err := tsm.service.IsRevoked(context.Context(nil), token)
+
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
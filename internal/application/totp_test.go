@@ -0,0 +1,82 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RFC 6238 appendix B secret, base32 encoded
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode_RFCVectors(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	// last 6 digits of the RFC 6238 SHA1 test vectors
+	assert.Equal(t, "287082", totpCode(key, 59/totpPeriod))
+	assert.Equal(t, "081804", totpCode(key, 1111111109/totpPeriod))
+	assert.Equal(t, "050471", totpCode(key, 1111111111/totpPeriod))
+	assert.Equal(t, "005924", totpCode(key, 1234567890/totpPeriod))
+	assert.Equal(t, "279037", totpCode(key, 2000000000/totpPeriod))
+}
+
+func TestValidateTOTP_OK(t *testing.T) {
+	step, ok := validateTOTP(rfcSecret, "005924", time.Unix(1234567890, 0))
+
+	assert.True(t, ok)
+	assert.Equal(t, int64(1234567890/totpPeriod), step)
+}
+
+func TestValidateTOTP_ClockSkew(t *testing.T) {
+	step, ok := validateTOTP(rfcSecret, "005924", time.Unix(1234567890+totpPeriod, 0))
+
+	assert.True(t, ok)
+	assert.Equal(t, int64(1234567890/totpPeriod), step)
+}
+
+func TestValidateTOTP_OutOfWindow(t *testing.T) {
+	_, ok := validateTOTP(rfcSecret, "005924", time.Unix(1234567890+3*totpPeriod, 0))
+
+	assert.False(t, ok)
+}
+
+func TestValidateTOTP_InvalidSecret(t *testing.T) {
+	_, ok := validateTOTP("!", "005924", time.Unix(1234567890, 0))
+
+	assert.False(t, ok)
+}
+
+func TestValidateTOTP_WrongLength(t *testing.T) {
+	_, ok := validateTOTP(rfcSecret, "05924", time.Unix(1234567890, 0))
+
+	assert.False(t, ok)
+}
+
+func TestNewTOTPSecret_OK(t *testing.T) {
+	secret, err := newTOTPSecret()
+	key, _ := totpEncoding.DecodeString(secret)
+
+	assert.NoError(t, err)
+	assert.Len(t, key, totpKeySize)
+}
+
+func TestTOTPURI_OK(t *testing.T) {
+	uri := totpURI("crabi", "an@email.com", "SECRET")
+
+	assert.Equal(t, "otpauth://totp/crabi:an@email.com?algorithm=SHA1&digits=6&issuer=crabi&period=30&secret=SECRET", uri)
+}
+
+func TestNewRecoveryCodes_OK(t *testing.T) {
+	codes, err := newRecoveryCodes(10)
+
+	assert.NoError(t, err)
+	assert.Len(t, codes, 10)
+	assert.Len(t, codes[0], 9)
+	assert.NotEqual(t, codes[0], codes[1])
+}
+
+func TestNormalizeRecoveryCode_OK(t *testing.T) {
+	assert.Equal(t, "ABCDEFGH", normalizeRecoveryCode(" abcd-efgh"))
+	assert.Equal(t, normalizeRecoveryCode("ABCD-EFGH"), normalizeRecoveryCode("abcdefgh"))
+}
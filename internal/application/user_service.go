@@ -3,37 +3,508 @@ package application
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/christhianjesus/crabi-challenge/internal/domain"
 )
 
+var (
+	ErrStaleUser       = domain.NewError(domain.ErrPreconditionFailed, "User was modified by another request")
+	ErrNothingToUpdate = domain.NewError(domain.ErrInvalidInput, "Nothing to update")
+	ErrUnknownRole     = domain.NewError(domain.ErrInvalidInput, "Unknown role")
+	ErrSelfRevoke      = domain.NewError(domain.ErrForbidden, "Admins cannot revoke their own admin role")
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+	// users screened again on each retry run
+	screeningBatchSize = 100
+	// deleted users purged on each run
+	purgeBatchSize = 100
+)
+
+// What a retry or a match records in the audit trail
+var screeningActions = map[domain.ScreeningStatus]domain.AuditAction{
+	domain.ScreeningPassed:        domain.AuditScreeningPassed,
+	domain.ScreeningPossibleMatch: domain.AuditScreeningPossibleMatch,
+	domain.ScreeningPendingReview: domain.AuditScreeningPendingReview,
+}
+
+// Tells the caller why it was rejected, matches domain.ErrBlacklisted.
+// Matched lists and entities are left out, only compliance sees them
+type BlacklistedError struct {
+	ReasonCodes []string
+}
+
+func (e *BlacklistedError) Error() string {
+	return domain.ErrBlacklisted.Error()
+}
+
+func (e *BlacklistedError) Unwrap() error {
+	return domain.ErrBlacklisted
+}
+
+type ScreeningPolicy struct {
+	Outage domain.PLDOutagePolicy
+	// from 0 to 1, hits scoring less are fuzzy matches
+	MatchThreshold float64
+}
+
+type DeletionPolicy struct {
+	Email domain.DeletedEmailPolicy
+	// how long deleted users keep their personal data
+	Retention time.Duration
+}
+
 type UserService interface {
 	CreateUser(ctx context.Context, user *domain.User) error
 	GetUser(ctx context.Context, userID string) (*domain.User, error)
+	UpdateUser(ctx context.Context, userID string, update *domain.UserUpdate, version int64) (*domain.User, error)
+	DeleteUser(ctx context.Context, userID string) error
+	PurgeDeletedUsers(ctx context.Context) (int64, error)
+	RetryScreenings(ctx context.Context) (int64, error)
+	ListUsers(ctx context.Context, query *domain.UserQuery, withTotal bool) (*domain.UserPage, error)
+	GrantRole(ctx context.Context, userID string, role domain.Role) error
+	RevokeRole(ctx context.Context, userID string, role domain.Role) error
 }
 
 type userService struct {
-	repo    domain.UserRepository
-	pldRepo domain.PLDRepository
+	repo          domain.UserRepository
+	pldRepo       domain.PLDRepository
+	screeningRepo domain.ScreeningRepository
+	auditRepo     domain.AuditRepository
+	mfaRepo       domain.MFARepository
+	sessionRepo   domain.SessionRepository
+	attemptRepo   domain.LoginAttemptRepository
+	tokenSrv      TokenService
+	deletion      DeletionPolicy
+	screening     ScreeningPolicy
 }
 
-func NewUserService(repo domain.UserRepository, pldRepo domain.PLDRepository) UserService {
-	return &userService{repo, pldRepo}
+func NewUserService(repo domain.UserRepository, pldRepo domain.PLDRepository, screeningRepo domain.ScreeningRepository, auditRepo domain.AuditRepository, mfaRepo domain.MFARepository, sessionRepo domain.SessionRepository, attemptRepo domain.LoginAttemptRepository, tokenSrv TokenService, deletion DeletionPolicy, screening ScreeningPolicy) UserService {
+	return &userService{repo, pldRepo, screeningRepo, auditRepo, mfaRepo, sessionRepo, attemptRepo, tokenSrv, deletion, screening}
 }
 
 func (u *userService) CreateUser(ctx context.Context, user *domain.User) error {
-	valid, err := u.pldRepo.IsValidUser(ctx, user)
-	if err != nil {
+	// other roles are only granted by an admin
+	user.Roles = []domain.Role{domain.RoleUser}
+	user.ScreeningStatus = domain.ScreeningPassed
+
+	result, record, err := u.screen(ctx, user)
+	err = u.createScreened(ctx, user, record, result, err)
+
+	// saved even when no user was created, it proves the check was done
+	if saveErr := u.screeningRepo.SaveScreening(ctx, record); saveErr != nil && err == nil {
+		log.Printf("saving the screening of user %s: %v", user.ID, saveErr)
+	}
+
+	return err
+}
+
+// Once the user exists the signup goes on, failing it would leave the account
+// without its verification email and a retry would only hit the conflict
+func (u *userService) recordCreation(ctx context.Context, event *domain.AuditEvent) {
+	if err := u.auditRepo.RecordEvent(ctx, event); err != nil {
+		log.Printf("recording %s for user %s: %v", event.Action, event.UserID, err)
+	}
+}
+
+// Matches are created as well, fuzzy ones can log in while compliance looks
+// at them, hard ones cannot until compliance approves them
+func (u *userService) createScreened(ctx context.Context, user *domain.User, record *domain.ScreeningRecord, result *domain.ScreeningResult, screenErr error) error {
+	if screenErr != nil {
+		return u.createUnscreened(ctx, user, record, screenErr)
+	}
+
+	user.ScreeningStatus = u.verdict(result)
+
+	if err := u.repo.CreateUser(ctx, user); err != nil {
 		return err
 	}
 
-	if !valid {
-		return errors.New("User is in blacklist")
+	record.UserID = user.ID
+
+	if user.ScreeningStatus != domain.ScreeningPassed {
+		u.recordCreation(ctx, &domain.AuditEvent{
+			Action:  screeningActions[user.ScreeningStatus],
+			UserID:  user.ID,
+			Details: matchDetails(result),
+		})
 	}
 
-	return u.repo.CreateUser(ctx, user)
+	return nil
+}
+
+// Applies the outage policy, only when the PLD service itself is unavailable
+func (u *userService) createUnscreened(ctx context.Context, user *domain.User, record *domain.ScreeningRecord, screenErr error) error {
+	if !errors.Is(screenErr, domain.ErrUpstreamUnavailable) {
+		return screenErr
+	}
+
+	var action domain.AuditAction
+
+	switch u.screening.Outage {
+	case domain.FailOpenOnOutage:
+		user.ScreeningStatus, action = domain.ScreeningFlagged, domain.AuditScreeningFailedOpen
+	case domain.DeferOnOutage:
+		user.ScreeningStatus, action = domain.ScreeningPending, domain.AuditScreeningDeferred
+	default:
+		return screenErr
+	}
+
+	if err := u.repo.CreateUser(ctx, user); err != nil {
+		return err
+	}
+
+	record.UserID = user.ID
+
+	u.recordCreation(ctx, &domain.AuditEvent{
+		Action:  action,
+		UserID:  user.ID,
+		Details: map[string]string{"error": screenErr.Error()},
+	})
+
+	return nil
 }
 
 func (u *userService) GetUser(ctx context.Context, userID string) (*domain.User, error) {
 	return u.repo.GetUser(ctx, userID)
 }
+
+// Version is the one the caller read, stale updates are rejected
+func (u *userService) UpdateUser(ctx context.Context, userID string, update *domain.UserUpdate, version int64) (*domain.User, error) {
+	if update.FirstName == nil && update.LastName == nil {
+		return nil, ErrNothingToUpdate
+	}
+
+	user, err := u.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Version != version {
+		return nil, ErrStaleUser
+	}
+
+	renamed := false
+
+	if update.FirstName != nil && *update.FirstName != user.FirstName {
+		user.FirstName = *update.FirstName
+		renamed = true
+	}
+
+	if update.LastName != nil && *update.LastName != user.LastName {
+		user.LastName = *update.LastName
+		renamed = true
+	}
+
+	if !renamed {
+		return user, nil
+	}
+
+	// a rename could hide a blacklisted person
+	result, err := u.screenExisting(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	// an existing account keeps its name rather than waiting for a review
+	status := u.verdict(result)
+	if status == domain.ScreeningPendingReview {
+		return nil, &BlacklistedError{result.ReasonCodes}
+	}
+
+	if err = u.repo.UpdateUser(ctx, user); err != nil {
+		// changed between the read and the write
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, ErrStaleUser
+		}
+
+		return nil, err
+	}
+
+	// a passing rename keeps the status, compliance decides on earlier matches
+	if status == domain.ScreeningPossibleMatch && user.ScreeningStatus != status {
+		if err = u.setScreeningStatus(ctx, user, status, result); err != nil {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+func (u *userService) DeleteUser(ctx context.Context, userID string) error {
+	// sessions end first, a failed delete only logs the user out
+	if err := u.tokenSrv.RevokeUserTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	return u.repo.DeleteUser(ctx, userID, u.deletion.Email == domain.ReleaseDeletedEmail)
+}
+
+// Purges in batches until none is left. A failure only skips the user it belongs
+// to and stops after that batch, the user is purged again on the next run
+func (u *userService) PurgeDeletedUsers(ctx context.Context) (int64, error) {
+	deletedBefore := time.Now().Add(-u.deletion.Retention)
+
+	var purged int64
+	var errs []error
+
+	for len(errs) == 0 {
+		users, err := u.repo.ListPurgeableUsers(ctx, deletedBefore, purgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		for _, user := range users {
+			if err = u.purge(ctx, user); err != nil {
+				errs = append(errs, fmt.Errorf("purging user %s: %w", user.ID, err))
+				continue
+			}
+
+			purged++
+		}
+
+		if len(users) < purgeBatchSize {
+			break
+		}
+	}
+
+	return purged, errors.Join(errs...)
+}
+
+// The user document goes last, it is what lists the user for the next run
+func (u *userService) purge(ctx context.Context, user *domain.User) error {
+	if err := u.mfaRepo.DeleteMFA(ctx, user.ID); err != nil {
+		return err
+	}
+
+	if err := u.sessionRepo.DeleteUserSessions(ctx, user.ID); err != nil {
+		return err
+	}
+
+	for _, key := range loginKeys(user.Email, "") {
+		if err := u.attemptRepo.ResetLoginAttempts(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	err := u.repo.PurgeUser(ctx, user.ID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+// Screens the users created during an outage. Stops when the PLD service
+// is still unavailable, other failures only skip the user they belong to
+func (u *userService) RetryScreenings(ctx context.Context) (int64, error) {
+	users, err := u.repo.ListUnscreenedUsers(ctx, screeningBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var screened int64
+	var errs []error
+
+	for _, user := range users {
+		if err = u.retryScreening(ctx, user); err != nil {
+			errs = append(errs, fmt.Errorf("screening user %s: %w", user.ID, err))
+
+			// the rest wait for the next run
+			if errors.Is(err, domain.ErrUpstreamUnavailable) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				break
+			}
+
+			continue
+		}
+
+		screened++
+	}
+
+	return screened, errors.Join(errs...)
+}
+
+func (u *userService) retryScreening(ctx context.Context, user *domain.User) error {
+	result, err := u.screenExisting(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	status := u.verdict(result)
+
+	if err = u.setScreeningStatus(ctx, user, status, result); err != nil {
+		// deleted in the meantime, nothing left to screen
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	// users let in by the fail open policy may be logged in
+	if status == domain.ScreeningPendingReview {
+		return u.tokenSrv.RevokeUserTokens(ctx, user.ID)
+	}
+
+	return nil
+}
+
+// Saves the status of a screened user and records it in the audit trail
+func (u *userService) setScreeningStatus(ctx context.Context, user *domain.User, status domain.ScreeningStatus, result *domain.ScreeningResult) error {
+	previous := user.ScreeningStatus
+	if err := u.repo.SetScreeningStatus(ctx, user, status); err != nil {
+		return err
+	}
+
+	details := matchDetails(result)
+	details["previous_status"] = string(previous)
+
+	user.ScreeningStatus = status
+
+	return u.auditRepo.RecordEvent(ctx, &domain.AuditEvent{
+		Action:  screeningActions[status],
+		UserID:  user.ID,
+		Details: details,
+	})
+}
+
+// Hits scoring under the threshold are fuzzy matches, the rest wait for a review
+func (u *userService) verdict(result *domain.ScreeningResult) domain.ScreeningStatus {
+	switch {
+	case !result.InBlacklist:
+		return domain.ScreeningPassed
+	case result.Score < u.screening.MatchThreshold:
+		return domain.ScreeningPossibleMatch
+	default:
+		return domain.ScreeningPendingReview
+	}
+}
+
+// The record is left to the caller, the user may not have an id yet
+func (u *userService) screen(ctx context.Context, user *domain.User) (*domain.ScreeningResult, *domain.ScreeningRecord, error) {
+	start := time.Now()
+	result, err := u.pldRepo.ScreenUser(ctx, user)
+
+	record := &domain.ScreeningRecord{
+		NameHash:  domain.HashScreeningSubject(user.FirstName + " " + user.LastName),
+		EmailHash: domain.HashScreeningSubject(user.Email),
+		Provider:  u.pldRepo.Provider(),
+		LatencyMS: time.Since(start).Milliseconds(),
+	}
+
+	if err != nil {
+		record.Error = err.Error()
+	} else {
+		record.InBlacklist = &result.InBlacklist
+		record.Score = result.Score
+		record.MatchedLists = result.MatchedLists
+		record.EntityID = result.EntityID
+		record.ReasonCodes = result.ReasonCodes
+	}
+
+	return result, record, err
+}
+
+// A screening that could not be recorded fails as well
+func (u *userService) screenExisting(ctx context.Context, user *domain.User) (*domain.ScreeningResult, error) {
+	result, record, err := u.screen(ctx, user)
+	record.UserID = user.ID
+
+	if saveErr := u.screeningRepo.SaveScreening(ctx, record); err == nil {
+		err = saveErr
+	}
+
+	return result, err
+}
+
+// Helper function to keep what compliance needs to look at a match
+func matchDetails(result *domain.ScreeningResult) map[string]string {
+	details := map[string]string{}
+
+	if !result.InBlacklist {
+		return details
+	}
+
+	details["score"] = strconv.FormatFloat(result.Score, 'f', -1, 64)
+
+	if len(result.MatchedLists) > 0 {
+		details["matched_lists"] = strings.Join(result.MatchedLists, ",")
+	}
+
+	if result.EntityID != "" {
+		details["entity_id"] = result.EntityID
+	}
+
+	return details
+}
+
+// The total is optional, counting a large collection is slow
+func (u *userService) ListUsers(ctx context.Context, query *domain.UserQuery, withTotal bool) (*domain.UserPage, error) {
+	if _, err := authorize(ctx, domain.PermissionReadUsers); err != nil {
+		return nil, err
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = defaultPageSize
+	}
+
+	query.Limit = min(query.Limit, maxPageSize)
+
+	page, err := u.repo.ListUsers(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if withTotal {
+		total, err := u.repo.CountUsers(ctx, &query.Filter)
+		if err != nil {
+			return nil, err
+		}
+
+		page.Total = &total
+	}
+
+	return page, nil
+}
+
+func (u *userService) GrantRole(ctx context.Context, userID string, role domain.Role) error {
+	if _, err := authorize(ctx, domain.PermissionManageRoles); err != nil {
+		return err
+	}
+
+	if !role.IsValid() {
+		return ErrUnknownRole
+	}
+
+	return u.repo.AddRole(ctx, userID, role)
+}
+
+// Sessions are ended, otherwise the role lives on in the access tokens
+func (u *userService) RevokeRole(ctx context.Context, userID string, role domain.Role) error {
+	principal, err := authorize(ctx, domain.PermissionManageRoles)
+	if err != nil {
+		return err
+	}
+
+	if !role.IsValid() {
+		return ErrUnknownRole
+	}
+
+	// otherwise the last admin could lock everyone out
+	if principal.UserID == userID && role == domain.RoleAdmin {
+		return ErrSelfRevoke
+	}
+
+	if err := u.repo.RemoveRole(ctx, userID, role); err != nil {
+		return err
+	}
+
+	return u.tokenSrv.RevokeUserTokens(ctx, userID)
+}
@@ -1,8 +1,14 @@
 package application
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"log"
+	"os"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/christhianjesus/crabi-challenge/internal/domain"
 	"github.com/christhianjesus/crabi-challenge/internal/mocks"
@@ -11,35 +17,87 @@ import (
 )
 
 type userServiceMock struct {
-	repo    *mocks.UserRepository
-	pldRepo *mocks.PLDRepository
-	service UserService
+	repo          *mocks.UserRepository
+	pldRepo       *mocks.PLDRepository
+	screeningRepo *mocks.ScreeningRepository
+	auditRepo     *mocks.AuditRepository
+	mfaRepo       *mocks.MFARepository
+	sessionRepo   *mocks.SessionRepository
+	attemptRepo   *mocks.LoginAttemptRepository
+	tokenSrv      *mocks.TokenService
+	service       UserService
 }
 
+var (
+	testDeletionPolicy  = DeletionPolicy{Email: domain.ReleaseDeletedEmail, Retention: time.Hour}
+	testScreeningPolicy = ScreeningPolicy{Outage: domain.FailClosedOnOutage, MatchThreshold: 0.9}
+)
+
 func setupUserService(t *testing.T) *userServiceMock {
+	return setupUserServiceWithPolicies(t, testDeletionPolicy, testScreeningPolicy)
+}
+
+func setupUserServiceWithPolicy(t *testing.T, deletion DeletionPolicy) *userServiceMock {
+	return setupUserServiceWithPolicies(t, deletion, testScreeningPolicy)
+}
+
+// Helper function to set up the service with another outage policy
+func setupUserServiceWithOutagePolicy(t *testing.T, outage domain.PLDOutagePolicy) *userServiceMock {
+	return setupUserServiceWithPolicies(t, testDeletionPolicy, ScreeningPolicy{Outage: outage, MatchThreshold: testScreeningPolicy.MatchThreshold})
+}
+
+func setupUserServiceWithPolicies(t *testing.T, deletion DeletionPolicy, screening ScreeningPolicy) *userServiceMock {
 	mockUserRepository := mocks.NewUserRepository(t)
 	mockPLDRepository := mocks.NewPLDRepository(t)
+	mockScreeningRepository := mocks.NewScreeningRepository(t)
+	mockAuditRepository := mocks.NewAuditRepository(t)
+	mockMFARepository := mocks.NewMFARepository(t)
+	mockSessionRepository := mocks.NewSessionRepository(t)
+	mockLoginAttemptRepository := mocks.NewLoginAttemptRepository(t)
+	mockTokenService := mocks.NewTokenService(t)
+	mockPLDRepository.On("Provider").Return("pld").Maybe()
 
 	return &userServiceMock{
-		repo:    mockUserRepository,
-		pldRepo: mockPLDRepository,
-		service: NewUserService(mockUserRepository, mockPLDRepository),
+		repo:          mockUserRepository,
+		pldRepo:       mockPLDRepository,
+		screeningRepo: mockScreeningRepository,
+		auditRepo:     mockAuditRepository,
+		mfaRepo:       mockMFARepository,
+		sessionRepo:   mockSessionRepository,
+		attemptRepo:   mockLoginAttemptRepository,
+		tokenSrv:      mockTokenService,
+		service:       NewUserService(mockUserRepository, mockPLDRepository, mockScreeningRepository, mockAuditRepository, mockMFARepository, mockSessionRepository, mockLoginAttemptRepository, mockTokenService, deletion, screening),
 	}
 }
 
 func TestCreateUser_OK(t *testing.T) {
 	usm := setupUserService(t)
 	usm.repo.On("CreateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil)
-	usm.pldRepo.On("IsValidUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(true, nil)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(&domain.ScreeningResult{}, nil)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
 
 	err := usm.service.CreateUser(context.Context(nil), &domain.User{})
 
 	assert.NoError(t, err)
 }
 
+func TestCreateUser_OnlyUserRole(t *testing.T) {
+	usm := setupUserService(t)
+	usm.repo.On("CreateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(&domain.ScreeningResult{}, nil)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+
+	user := &domain.User{Roles: []domain.Role{domain.RoleAdmin}}
+	err := usm.service.CreateUser(context.Context(nil), user)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Role{domain.RoleUser}, user.Roles)
+}
+
 func TestCreateUser_PLDError(t *testing.T) {
 	usm := setupUserService(t)
-	usm.pldRepo.On("IsValidUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(false, assert.AnError)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil, assert.AnError)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
 
 	err := usm.service.CreateUser(context.Context(nil), &domain.User{})
 
@@ -47,20 +105,268 @@ func TestCreateUser_PLDError(t *testing.T) {
 	assert.EqualError(t, err, assert.AnError.Error())
 }
 
-func TestCreateUser_IsValidUserFalse(t *testing.T) {
+func TestCreateUser_PendingReview(t *testing.T) {
+	recorded := func(record *domain.ScreeningRecord) bool {
+		return record.UserID == "1" && record.InBlacklist != nil && *record.InBlacklist
+	}
+
 	usm := setupUserService(t)
-	usm.pldRepo.On("IsValidUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(false, nil)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(&domain.ScreeningResult{InBlacklist: true, Score: 1, EntityID: "e1"}, nil)
+	usm.repo.On("CreateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.User).ID = "1"
+	})
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.MatchedBy(recorded)).Return(nil)
+	usm.auditRepo.On("RecordEvent", mock.IsType(nil), &domain.AuditEvent{
+		Action:  domain.AuditScreeningPendingReview,
+		UserID:  "1",
+		Details: map[string]string{"score": "1", "entity_id": "e1"},
+	}).Return(nil)
+
+	user := &domain.User{}
+	err := usm.service.CreateUser(context.Context(nil), user)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ScreeningPendingReview, user.ScreeningStatus)
+}
+
+func TestCreateUser_RecordsScreening(t *testing.T) {
+	recorded := func(record *domain.ScreeningRecord) bool {
+		return record.UserID == "1" &&
+			record.Provider == "pld" &&
+			record.EmailHash == domain.HashScreeningSubject("AN@email.com ") &&
+			record.NameHash == domain.HashScreeningSubject("first last") &&
+			record.InBlacklist != nil && !*record.InBlacklist &&
+			record.Error == ""
+	}
+
+	usm := setupUserService(t)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(&domain.ScreeningResult{}, nil)
+	usm.repo.On("CreateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.User).ID = "1"
+	})
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.MatchedBy(recorded)).Return(nil)
+
+	err := usm.service.CreateUser(context.Context(nil), &domain.User{Email: "an@email.com", FirstName: "First", LastName: "Last"})
+
+	assert.NoError(t, err)
+}
+
+func TestCreateUser_RecordsUncreatedScreening(t *testing.T) {
+	recorded := func(record *domain.ScreeningRecord) bool {
+		return record.UserID == "" && record.InBlacklist != nil && !*record.InBlacklist
+	}
+
+	usm := setupUserService(t)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(&domain.ScreeningResult{}, nil)
+	usm.repo.On("CreateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(domain.ErrConflict)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.MatchedBy(recorded)).Return(nil)
+
+	err := usm.service.CreateUser(context.Context(nil), &domain.User{ID: "sent by the client"})
+
+	assert.ErrorIs(t, err, domain.ErrConflict)
+}
+
+func TestCreateUser_RecordsFailedScreening(t *testing.T) {
+	recorded := func(record *domain.ScreeningRecord) bool {
+		return record.InBlacklist == nil && record.Error == domain.ErrUpstreamUnavailable.Error()
+	}
+
+	usm := setupUserService(t)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil, domain.ErrUpstreamUnavailable)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.MatchedBy(recorded)).Return(nil)
 
 	err := usm.service.CreateUser(context.Context(nil), &domain.User{})
 
-	assert.Error(t, err)
-	assert.EqualError(t, err, "User is in blacklist")
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+}
+
+// Helper function to capture what the service logs
+func captureLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	return &buf
+}
+
+func TestCreateUser_SaveScreeningError(t *testing.T) {
+	logged := captureLog(t)
+
+	usm := setupUserService(t)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(&domain.ScreeningResult{}, nil)
+	usm.repo.On("CreateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.User).ID = "1"
+	})
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(assert.AnError)
+
+	err := usm.service.CreateUser(context.Context(nil), &domain.User{})
+
+	// the user exists, the signup goes on
+	assert.NoError(t, err)
+	assert.Contains(t, logged.String(), "saving the screening of user 1: "+assert.AnError.Error())
+}
+
+func TestCreateUser_ReasonCodes(t *testing.T) {
+	result := &domain.ScreeningResult{InBlacklist: true, Score: 0.95, MatchedLists: []string{"OFAC"}, EntityID: "e1", ReasonCodes: []string{"NAME_MATCH"}}
+	recorded := func(record *domain.ScreeningRecord) bool {
+		return record.Score == 0.95 && record.EntityID == "e1" && record.MatchedLists[0] == "OFAC" && record.ReasonCodes[0] == "NAME_MATCH"
+	}
+
+	usm := setupUserService(t)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(result, nil)
+	usm.repo.On("CreateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil)
+	usm.auditRepo.On("RecordEvent", mock.IsType(nil), mock.AnythingOfType("*domain.AuditEvent")).Return(nil)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.MatchedBy(recorded)).Return(nil)
+
+	err := usm.service.CreateUser(context.Context(nil), &domain.User{})
+
+	assert.NoError(t, err)
+}
+
+func TestCreateUser_PossibleMatch(t *testing.T) {
+	result := &domain.ScreeningResult{InBlacklist: true, Score: 0.6, MatchedLists: []string{"PEP", "OFAC"}, EntityID: "e1"}
+
+	usm := setupUserService(t)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(result, nil)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+	usm.repo.On("CreateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.User).ID = "1"
+	})
+	usm.auditRepo.On("RecordEvent", mock.IsType(nil), &domain.AuditEvent{
+		Action:  domain.AuditScreeningPossibleMatch,
+		UserID:  "1",
+		Details: map[string]string{"score": "0.6", "matched_lists": "PEP,OFAC", "entity_id": "e1"},
+	}).Return(nil)
+
+	user := &domain.User{}
+	err := usm.service.CreateUser(context.Context(nil), user)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ScreeningPossibleMatch, user.ScreeningStatus)
+}
+
+func TestCreateUser_PossibleMatchRecordEventError(t *testing.T) {
+	captureLog(t)
+
+	usm := setupUserService(t)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(&domain.ScreeningResult{InBlacklist: true, Score: 0.5}, nil)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+	usm.repo.On("CreateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil)
+	usm.auditRepo.On("RecordEvent", mock.IsType(nil), mock.AnythingOfType("*domain.AuditEvent")).Return(assert.AnError)
+
+	user := &domain.User{}
+	err := usm.service.CreateUser(context.Context(nil), user)
+
+	// the user exists, the screening is still saved and the signup goes on
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ScreeningPossibleMatch, user.ScreeningStatus)
+	usm.screeningRepo.AssertCalled(t, "SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord"))
+}
+
+func TestCreateUser_ScreeningPassed(t *testing.T) {
+	usm := setupUserService(t)
+	usm.repo.On("CreateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(&domain.ScreeningResult{}, nil)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+
+	// sent by the client, it must be ignored
+	user := &domain.User{ScreeningStatus: domain.ScreeningBlacklisted}
+	err := usm.service.CreateUser(context.Context(nil), user)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ScreeningPassed, user.ScreeningStatus)
+}
+
+func TestCreateUser_OutageFailClosed(t *testing.T) {
+	usm := setupUserService(t)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil, domain.ErrUpstreamUnavailable)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+
+	err := usm.service.CreateUser(context.Context(nil), &domain.User{})
+
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+}
+
+func TestCreateUser_OutageFailOpen(t *testing.T) {
+	usm := setupUserServiceWithOutagePolicy(t, domain.FailOpenOnOutage)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil, domain.ErrUpstreamUnavailable)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+	usm.repo.On("CreateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.User).ID = "1"
+	})
+	usm.auditRepo.On("RecordEvent", mock.IsType(nil), &domain.AuditEvent{
+		Action:  domain.AuditScreeningFailedOpen,
+		UserID:  "1",
+		Details: map[string]string{"error": domain.ErrUpstreamUnavailable.Error()},
+	}).Return(nil)
+
+	user := &domain.User{}
+	err := usm.service.CreateUser(context.Context(nil), user)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ScreeningFlagged, user.ScreeningStatus)
+}
+
+func TestCreateUser_OutageDefer(t *testing.T) {
+	usm := setupUserServiceWithOutagePolicy(t, domain.DeferOnOutage)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil, domain.ErrUpstreamUnavailable)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+	usm.repo.On("CreateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil)
+	usm.auditRepo.On("RecordEvent", mock.IsType(nil), mock.MatchedBy(func(event *domain.AuditEvent) bool {
+		return event.Action == domain.AuditScreeningDeferred
+	})).Return(nil)
+
+	user := &domain.User{}
+	err := usm.service.CreateUser(context.Context(nil), user)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ScreeningPending, user.ScreeningStatus)
+}
+
+func TestCreateUser_OutagePolicyOnlyForOutages(t *testing.T) {
+	usm := setupUserServiceWithOutagePolicy(t, domain.FailOpenOnOutage)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil, assert.AnError)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+
+	err := usm.service.CreateUser(context.Context(nil), &domain.User{})
+
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestCreateUser_OutageCreateUserError(t *testing.T) {
+	usm := setupUserServiceWithOutagePolicy(t, domain.DeferOnOutage)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil, domain.ErrUpstreamUnavailable)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+	usm.repo.On("CreateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(assert.AnError)
+
+	err := usm.service.CreateUser(context.Context(nil), &domain.User{})
+
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestCreateUser_OutageRecordEventError(t *testing.T) {
+	logged := captureLog(t)
+
+	usm := setupUserServiceWithOutagePolicy(t, domain.FailOpenOnOutage)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil, domain.ErrUpstreamUnavailable)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+	usm.repo.On("CreateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.User).ID = "1"
+	})
+	usm.auditRepo.On("RecordEvent", mock.IsType(nil), mock.AnythingOfType("*domain.AuditEvent")).Return(assert.AnError)
+
+	err := usm.service.CreateUser(context.Context(nil), &domain.User{})
+
+	assert.NoError(t, err)
+	assert.Contains(t, logged.String(), "recording screening.failed_open for user 1: "+assert.AnError.Error())
 }
 
 func TestCreateUser_Error(t *testing.T) {
 	usm := setupUserService(t)
 	usm.repo.On("CreateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(assert.AnError)
-	usm.pldRepo.On("IsValidUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(true, nil)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(&domain.ScreeningResult{}, nil)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
 
 	err := usm.service.CreateUser(context.Context(nil), &domain.User{})
 
@@ -91,3 +397,617 @@ func TestGetUser_Error(t *testing.T) {
 	assert.EqualError(t, err, assert.AnError.Error())
 	assert.Nil(t, user)
 }
+
+func TestUpdateUser_OK(t *testing.T) {
+	name := "Newname"
+	renamed := func(user *domain.User) bool {
+		return user.FirstName == "Newname" && user.LastName == "Lastname"
+	}
+
+	usm := setupUserService(t)
+	usm.repo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1", FirstName: "Oldname", LastName: "Lastname", Version: 3}, nil)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.MatchedBy(renamed)).Return(&domain.ScreeningResult{}, nil)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+	usm.repo.On("UpdateUser", mock.IsType(nil), mock.MatchedBy(renamed)).Return(nil)
+
+	user, err := usm.service.UpdateUser(context.Context(nil), "1", &domain.UserUpdate{FirstName: &name}, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Newname", user.FirstName)
+}
+
+func TestUpdateUser_SaveScreeningError(t *testing.T) {
+	name := "Newname"
+	recorded := func(record *domain.ScreeningRecord) bool {
+		return record.UserID == "1"
+	}
+
+	usm := setupUserService(t)
+	usm.repo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1", FirstName: "Oldname", Version: 3}, nil)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(&domain.ScreeningResult{}, nil)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.MatchedBy(recorded)).Return(assert.AnError)
+
+	user, err := usm.service.UpdateUser(context.Context(nil), "1", &domain.UserUpdate{FirstName: &name}, 3)
+
+	// the rename is not saved without its screening
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, user)
+}
+
+func TestUpdateUser_PossibleMatch(t *testing.T) {
+	name := "Newname"
+
+	usm := setupUserService(t)
+	usm.repo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1", FirstName: "Oldname", ScreeningStatus: domain.ScreeningPassed, Version: 3}, nil)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(&domain.ScreeningResult{InBlacklist: true, Score: 0.5}, nil)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+	usm.repo.On("UpdateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.User).Version++
+	})
+	usm.repo.On("SetScreeningStatus", mock.IsType(nil), mock.AnythingOfType("*domain.User"), domain.ScreeningPossibleMatch).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.User).Version++
+	})
+	usm.auditRepo.On("RecordEvent", mock.IsType(nil), &domain.AuditEvent{
+		Action:  domain.AuditScreeningPossibleMatch,
+		UserID:  "1",
+		Details: map[string]string{"previous_status": "passed", "score": "0.5"},
+	}).Return(nil)
+
+	user, err := usm.service.UpdateUser(context.Context(nil), "1", &domain.UserUpdate{FirstName: &name}, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ScreeningPossibleMatch, user.ScreeningStatus)
+	assert.Equal(t, int64(5), user.Version)
+}
+
+func TestUpdateUser_SetScreeningStatusError(t *testing.T) {
+	name := "Newname"
+
+	usm := setupUserService(t)
+	usm.repo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1", FirstName: "Oldname", Version: 3}, nil)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(&domain.ScreeningResult{InBlacklist: true, Score: 0.5}, nil)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+	usm.repo.On("UpdateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil)
+	usm.repo.On("SetScreeningStatus", mock.IsType(nil), mock.AnythingOfType("*domain.User"), domain.ScreeningPossibleMatch).Return(assert.AnError)
+
+	user, err := usm.service.UpdateUser(context.Context(nil), "1", &domain.UserUpdate{FirstName: &name}, 3)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, user)
+}
+
+func TestUpdateUser_NothingToUpdate(t *testing.T) {
+	usm := setupUserService(t)
+
+	user, err := usm.service.UpdateUser(context.Context(nil), "1", &domain.UserUpdate{}, 3)
+
+	assert.ErrorIs(t, err, ErrNothingToUpdate)
+	assert.Nil(t, user)
+}
+
+func TestUpdateUser_SameNames(t *testing.T) {
+	name := "Oldname"
+
+	usm := setupUserService(t)
+	usm.repo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1", FirstName: "Oldname", Version: 3}, nil)
+
+	user, err := usm.service.UpdateUser(context.Context(nil), "1", &domain.UserUpdate{FirstName: &name}, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), user.Version)
+}
+
+func TestUpdateUser_GetUserError(t *testing.T) {
+	name := "Newname"
+
+	usm := setupUserService(t)
+	usm.repo.On("GetUser", mock.IsType(nil), "1").Return(nil, assert.AnError)
+
+	user, err := usm.service.UpdateUser(context.Context(nil), "1", &domain.UserUpdate{FirstName: &name}, 3)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, user)
+}
+
+func TestUpdateUser_StaleVersion(t *testing.T) {
+	name := "Newname"
+
+	usm := setupUserService(t)
+	usm.repo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1", Version: 4}, nil)
+
+	user, err := usm.service.UpdateUser(context.Context(nil), "1", &domain.UserUpdate{FirstName: &name}, 3)
+
+	assert.ErrorIs(t, err, ErrStaleUser)
+	assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
+	assert.Nil(t, user)
+}
+
+func TestUpdateUser_Blacklisted(t *testing.T) {
+	name := "Newname"
+
+	usm := setupUserService(t)
+	usm.repo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1", Version: 3}, nil)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(&domain.ScreeningResult{InBlacklist: true, Score: 1, ReasonCodes: []string{"NAME_MATCH"}}, nil)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+
+	user, err := usm.service.UpdateUser(context.Context(nil), "1", &domain.UserUpdate{LastName: &name}, 3)
+
+	var blacklisted *BlacklistedError
+	assert.ErrorAs(t, err, &blacklisted)
+	assert.Equal(t, []string{"NAME_MATCH"}, blacklisted.ReasonCodes)
+	assert.Nil(t, user)
+}
+
+func TestUpdateUser_PLDError(t *testing.T) {
+	name := "Newname"
+
+	usm := setupUserService(t)
+	usm.repo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1", Version: 3}, nil)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(nil, assert.AnError)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+
+	user, err := usm.service.UpdateUser(context.Context(nil), "1", &domain.UserUpdate{LastName: &name}, 3)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, user)
+}
+
+func TestUpdateUser_ConcurrentUpdate(t *testing.T) {
+	name := "Newname"
+
+	usm := setupUserService(t)
+	usm.repo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1", Version: 3}, nil)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(&domain.ScreeningResult{}, nil)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+	usm.repo.On("UpdateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(domain.ErrNotFound)
+
+	user, err := usm.service.UpdateUser(context.Context(nil), "1", &domain.UserUpdate{FirstName: &name}, 3)
+
+	assert.ErrorIs(t, err, ErrStaleUser)
+	assert.Nil(t, user)
+}
+
+func TestUpdateUser_UpdateUserError(t *testing.T) {
+	name := "Newname"
+
+	usm := setupUserService(t)
+	usm.repo.On("GetUser", mock.IsType(nil), "1").Return(&domain.User{ID: "1", Version: 3}, nil)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(&domain.ScreeningResult{}, nil)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+	usm.repo.On("UpdateUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(assert.AnError)
+
+	user, err := usm.service.UpdateUser(context.Context(nil), "1", &domain.UserUpdate{FirstName: &name}, 3)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, user)
+}
+
+func TestDeleteUser_OK(t *testing.T) {
+	usm := setupUserService(t)
+	usm.tokenSrv.On("RevokeUserTokens", mock.IsType(nil), "1").Return(nil)
+	usm.repo.On("DeleteUser", mock.IsType(nil), "1", true).Return(nil)
+
+	err := usm.service.DeleteUser(context.Context(nil), "1")
+
+	assert.NoError(t, err)
+}
+
+func TestDeleteUser_RetainEmail(t *testing.T) {
+	usm := setupUserServiceWithPolicy(t, DeletionPolicy{Email: domain.RetainDeletedEmail})
+	usm.tokenSrv.On("RevokeUserTokens", mock.IsType(nil), "1").Return(nil)
+	usm.repo.On("DeleteUser", mock.IsType(nil), "1", false).Return(nil)
+
+	err := usm.service.DeleteUser(context.Context(nil), "1")
+
+	assert.NoError(t, err)
+}
+
+func TestDeleteUser_RevokeError(t *testing.T) {
+	usm := setupUserService(t)
+	usm.tokenSrv.On("RevokeUserTokens", mock.IsType(nil), "1").Return(assert.AnError)
+
+	err := usm.service.DeleteUser(context.Context(nil), "1")
+
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestDeleteUser_NotFound(t *testing.T) {
+	usm := setupUserService(t)
+	usm.tokenSrv.On("RevokeUserTokens", mock.IsType(nil), "1").Return(nil)
+	usm.repo.On("DeleteUser", mock.IsType(nil), "1", true).Return(domain.ErrNotFound)
+
+	err := usm.service.DeleteUser(context.Context(nil), "1")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestPurgeDeletedUsers_OK(t *testing.T) {
+	retained := func(deletedBefore time.Time) bool {
+		return time.Until(deletedBefore) < -59*time.Minute
+	}
+	users := []*domain.User{{ID: "1", Email: "Deleted@Email.com"}, {ID: "2"}}
+
+	usm := setupUserService(t)
+	usm.repo.On("ListPurgeableUsers", mock.IsType(nil), mock.MatchedBy(retained), purgeBatchSize).Return(users, nil)
+	usm.mfaRepo.On("DeleteMFA", mock.IsType(nil), "1").Return(nil)
+	usm.sessionRepo.On("DeleteUserSessions", mock.IsType(nil), "1").Return(nil)
+	usm.attemptRepo.On("ResetLoginAttempts", mock.IsType(nil), "email:deleted@email.com").Return(nil)
+	usm.repo.On("PurgeUser", mock.IsType(nil), "1").Return(nil)
+	usm.mfaRepo.On("DeleteMFA", mock.IsType(nil), "2").Return(nil)
+	usm.sessionRepo.On("DeleteUserSessions", mock.IsType(nil), "2").Return(nil)
+	usm.repo.On("PurgeUser", mock.IsType(nil), "2").Return(nil)
+
+	purged, err := usm.service.PurgeDeletedUsers(context.Context(nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+}
+
+func TestPurgeDeletedUsers_Batches(t *testing.T) {
+	users := make([]*domain.User, purgeBatchSize)
+	for i := range users {
+		users[i] = &domain.User{ID: strconv.Itoa(i)}
+	}
+
+	usm := setupUserService(t)
+	usm.repo.On("ListPurgeableUsers", mock.IsType(nil), mock.AnythingOfType("time.Time"), purgeBatchSize).Return(users, nil).Once()
+	usm.repo.On("ListPurgeableUsers", mock.IsType(nil), mock.AnythingOfType("time.Time"), purgeBatchSize).Return(users[:1], nil).Once()
+	usm.mfaRepo.On("DeleteMFA", mock.IsType(nil), mock.AnythingOfType("string")).Return(nil)
+	usm.sessionRepo.On("DeleteUserSessions", mock.IsType(nil), mock.AnythingOfType("string")).Return(nil)
+	usm.repo.On("PurgeUser", mock.IsType(nil), mock.AnythingOfType("string")).Return(nil)
+
+	purged, err := usm.service.PurgeDeletedUsers(context.Context(nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(purgeBatchSize+1), purged)
+}
+
+func TestPurgeDeletedUsers_ListPurgeableUsersError(t *testing.T) {
+	usm := setupUserService(t)
+	usm.repo.On("ListPurgeableUsers", mock.IsType(nil), mock.AnythingOfType("time.Time"), purgeBatchSize).Return(nil, assert.AnError)
+
+	purged, err := usm.service.PurgeDeletedUsers(context.Context(nil))
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Zero(t, purged)
+}
+
+func TestPurgeDeletedUsers_DeleteSessionsError(t *testing.T) {
+	users := []*domain.User{{ID: "1"}, {ID: "2"}}
+
+	usm := setupUserService(t)
+	usm.repo.On("ListPurgeableUsers", mock.IsType(nil), mock.AnythingOfType("time.Time"), purgeBatchSize).Return(users, nil)
+	usm.mfaRepo.On("DeleteMFA", mock.IsType(nil), mock.AnythingOfType("string")).Return(nil)
+	usm.sessionRepo.On("DeleteUserSessions", mock.IsType(nil), "1").Return(assert.AnError)
+	usm.sessionRepo.On("DeleteUserSessions", mock.IsType(nil), "2").Return(nil)
+	usm.repo.On("PurgeUser", mock.IsType(nil), "2").Return(nil)
+
+	purged, err := usm.service.PurgeDeletedUsers(context.Context(nil))
+
+	// the user keeps its data, so it is listed again on the next run
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.ErrorContains(t, err, "purging user 1")
+	assert.Equal(t, int64(1), purged)
+	usm.repo.AssertNotCalled(t, "PurgeUser", mock.Anything, "1")
+}
+
+func TestPurgeDeletedUsers_AlreadyPurged(t *testing.T) {
+	usm := setupUserService(t)
+	usm.repo.On("ListPurgeableUsers", mock.IsType(nil), mock.AnythingOfType("time.Time"), purgeBatchSize).Return([]*domain.User{{ID: "1"}}, nil)
+	usm.mfaRepo.On("DeleteMFA", mock.IsType(nil), "1").Return(nil)
+	usm.sessionRepo.On("DeleteUserSessions", mock.IsType(nil), "1").Return(nil)
+	usm.repo.On("PurgeUser", mock.IsType(nil), "1").Return(domain.ErrNotFound)
+
+	purged, err := usm.service.PurgeDeletedUsers(context.Context(nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+}
+
+func TestRetryScreenings_OK(t *testing.T) {
+	users := []*domain.User{
+		{ID: "1", ScreeningStatus: domain.ScreeningPending},
+		{ID: "2", ScreeningStatus: domain.ScreeningFlagged},
+	}
+
+	usm := setupUserService(t)
+	usm.repo.On("ListUnscreenedUsers", mock.IsType(nil), screeningBatchSize).Return(users, nil)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), users[0]).Return(&domain.ScreeningResult{}, nil)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), users[1]).Return(&domain.ScreeningResult{InBlacklist: true, Score: 1}, nil)
+	usm.repo.On("SetScreeningStatus", mock.IsType(nil), users[0], domain.ScreeningPassed).Return(nil)
+	usm.repo.On("SetScreeningStatus", mock.IsType(nil), users[1], domain.ScreeningPendingReview).Return(nil)
+	usm.tokenSrv.On("RevokeUserTokens", mock.IsType(nil), "2").Return(nil)
+	usm.auditRepo.On("RecordEvent", mock.IsType(nil), &domain.AuditEvent{
+		Action:  domain.AuditScreeningPassed,
+		UserID:  "1",
+		Details: map[string]string{"previous_status": "pending_screening"},
+	}).Return(nil)
+	usm.auditRepo.On("RecordEvent", mock.IsType(nil), &domain.AuditEvent{
+		Action:  domain.AuditScreeningPendingReview,
+		UserID:  "2",
+		Details: map[string]string{"previous_status": "flagged", "score": "1"},
+	}).Return(nil)
+
+	screened, err := usm.service.RetryScreenings(context.Context(nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), screened)
+}
+
+func TestRetryScreenings_PossibleMatch(t *testing.T) {
+	user := &domain.User{ID: "1", ScreeningStatus: domain.ScreeningPending}
+
+	usm := setupUserService(t)
+	usm.repo.On("ListUnscreenedUsers", mock.IsType(nil), screeningBatchSize).Return([]*domain.User{user}, nil)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), user).Return(&domain.ScreeningResult{InBlacklist: true, Score: 0.4}, nil)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+	usm.repo.On("SetScreeningStatus", mock.IsType(nil), user, domain.ScreeningPossibleMatch).Return(nil)
+	usm.auditRepo.On("RecordEvent", mock.IsType(nil), mock.MatchedBy(func(event *domain.AuditEvent) bool {
+		return event.Action == domain.AuditScreeningPossibleMatch
+	})).Return(nil)
+
+	screened, err := usm.service.RetryScreenings(context.Context(nil))
+
+	// fuzzy matches keep their sessions
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), screened)
+}
+
+func TestVerdict(t *testing.T) {
+	service := &userService{screening: ScreeningPolicy{MatchThreshold: 0.9}}
+
+	assert.Equal(t, domain.ScreeningPassed, service.verdict(&domain.ScreeningResult{Score: 0.95}))
+	assert.Equal(t, domain.ScreeningPossibleMatch, service.verdict(&domain.ScreeningResult{InBlacklist: true, Score: 0.89}))
+	assert.Equal(t, domain.ScreeningPendingReview, service.verdict(&domain.ScreeningResult{InBlacklist: true, Score: 0.9}))
+}
+
+func TestRetryScreenings_ListUnscreenedUsersError(t *testing.T) {
+	usm := setupUserService(t)
+	usm.repo.On("ListUnscreenedUsers", mock.IsType(nil), screeningBatchSize).Return(nil, assert.AnError)
+
+	screened, err := usm.service.RetryScreenings(context.Context(nil))
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Zero(t, screened)
+}
+
+func TestRetryScreenings_StillUnavailable(t *testing.T) {
+	users := []*domain.User{{ID: "1"}, {ID: "2"}}
+
+	usm := setupUserService(t)
+	usm.repo.On("ListUnscreenedUsers", mock.IsType(nil), screeningBatchSize).Return(users, nil)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), users[0]).Return(nil, domain.ErrUpstreamUnavailable).Once()
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+
+	screened, err := usm.service.RetryScreenings(context.Context(nil))
+
+	// the rest wait for the next run
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	assert.Zero(t, screened)
+}
+
+func TestRetryScreenings_DeletedUser(t *testing.T) {
+	usm := setupUserService(t)
+	usm.repo.On("ListUnscreenedUsers", mock.IsType(nil), screeningBatchSize).Return([]*domain.User{{ID: "1"}}, nil)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(&domain.ScreeningResult{}, nil)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+	usm.repo.On("SetScreeningStatus", mock.IsType(nil), mock.AnythingOfType("*domain.User"), domain.ScreeningPassed).Return(domain.ErrNotFound)
+
+	screened, err := usm.service.RetryScreenings(context.Context(nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), screened)
+}
+
+func TestRetryScreenings_SkipsFailedUser(t *testing.T) {
+	users := []*domain.User{{ID: "1"}, {ID: "2"}}
+
+	usm := setupUserService(t)
+	usm.repo.On("ListUnscreenedUsers", mock.IsType(nil), screeningBatchSize).Return(users, nil)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), users[0]).Return(nil, errors.New("PLD answered 400"))
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), users[1]).Return(&domain.ScreeningResult{}, nil)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+	usm.repo.On("SetScreeningStatus", mock.IsType(nil), users[1], domain.ScreeningPassed).Return(nil)
+	usm.auditRepo.On("RecordEvent", mock.IsType(nil), mock.AnythingOfType("*domain.AuditEvent")).Return(nil)
+
+	screened, err := usm.service.RetryScreenings(context.Context(nil))
+
+	// the first user would otherwise hold back the queue on every run
+	assert.ErrorContains(t, err, "screening user 1: PLD answered 400")
+	assert.Equal(t, int64(1), screened)
+}
+
+func TestRetryScreenings_SetScreeningStatusError(t *testing.T) {
+	usm := setupUserService(t)
+	usm.repo.On("ListUnscreenedUsers", mock.IsType(nil), screeningBatchSize).Return([]*domain.User{{ID: "1"}}, nil)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(&domain.ScreeningResult{}, nil)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+	usm.repo.On("SetScreeningStatus", mock.IsType(nil), mock.AnythingOfType("*domain.User"), domain.ScreeningPassed).Return(assert.AnError)
+
+	_, err := usm.service.RetryScreenings(context.Context(nil))
+
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestRetryScreenings_RevokeUserTokensError(t *testing.T) {
+	usm := setupUserService(t)
+	usm.repo.On("ListUnscreenedUsers", mock.IsType(nil), screeningBatchSize).Return([]*domain.User{{ID: "1"}}, nil)
+	usm.pldRepo.On("ScreenUser", mock.IsType(nil), mock.AnythingOfType("*domain.User")).Return(&domain.ScreeningResult{InBlacklist: true, Score: 1}, nil)
+	usm.screeningRepo.On("SaveScreening", mock.IsType(nil), mock.AnythingOfType("*domain.ScreeningRecord")).Return(nil)
+	usm.repo.On("SetScreeningStatus", mock.IsType(nil), mock.AnythingOfType("*domain.User"), domain.ScreeningPendingReview).Return(nil)
+	usm.auditRepo.On("RecordEvent", mock.IsType(nil), mock.AnythingOfType("*domain.AuditEvent")).Return(nil)
+	usm.tokenSrv.On("RevokeUserTokens", mock.IsType(nil), "1").Return(assert.AnError)
+
+	_, err := usm.service.RetryScreenings(context.Context(nil))
+
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestListUsers_OK(t *testing.T) {
+	page := &domain.UserPage{Users: []*domain.User{{ID: "1"}}}
+
+	usm := setupUserService(t)
+	usm.repo.On("ListUsers", mock.Anything, &domain.UserQuery{Limit: 20}).Return(page, nil)
+
+	res, err := usm.service.ListUsers(supportContext(), &domain.UserQuery{}, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, page, res)
+	assert.Nil(t, res.Total)
+}
+
+func TestListUsers_MaxLimit(t *testing.T) {
+	usm := setupUserService(t)
+	usm.repo.On("ListUsers", mock.Anything, &domain.UserQuery{Limit: 100}).Return(&domain.UserPage{}, nil)
+
+	_, err := usm.service.ListUsers(supportContext(), &domain.UserQuery{Limit: 1000}, false)
+
+	assert.NoError(t, err)
+}
+
+func TestListUsers_WithTotal(t *testing.T) {
+	query := &domain.UserQuery{Filter: domain.UserFilter{Status: domain.ActiveUser}, Limit: 5}
+
+	usm := setupUserService(t)
+	usm.repo.On("ListUsers", mock.Anything, query).Return(&domain.UserPage{}, nil)
+	usm.repo.On("CountUsers", mock.Anything, &query.Filter).Return(int64(42), nil)
+
+	res, err := usm.service.ListUsers(supportContext(), query, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), *res.Total)
+}
+
+func TestListUsers_ListError(t *testing.T) {
+	usm := setupUserService(t)
+	usm.repo.On("ListUsers", mock.Anything, mock.AnythingOfType("*domain.UserQuery")).Return(nil, assert.AnError)
+
+	res, err := usm.service.ListUsers(supportContext(), &domain.UserQuery{}, true)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, res)
+}
+
+func TestListUsers_CountError(t *testing.T) {
+	usm := setupUserService(t)
+	usm.repo.On("ListUsers", mock.Anything, mock.AnythingOfType("*domain.UserQuery")).Return(&domain.UserPage{}, nil)
+	usm.repo.On("CountUsers", mock.Anything, mock.AnythingOfType("*domain.UserFilter")).Return(int64(0), assert.AnError)
+
+	res, err := usm.service.ListUsers(supportContext(), &domain.UserQuery{}, true)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, res)
+}
+
+func TestListUsers_PermissionDenied(t *testing.T) {
+	ctx := domain.WithPrincipal(context.Background(), &domain.Principal{UserID: "1", Roles: []domain.Role{domain.RoleUser}})
+
+	usm := setupUserService(t)
+
+	res, err := usm.service.ListUsers(ctx, &domain.UserQuery{}, false)
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	assert.Nil(t, res)
+}
+
+// Helper function to build the context of a request made by a support agent
+func supportContext() context.Context {
+	return domain.WithPrincipal(context.Background(), &domain.Principal{UserID: "8", Roles: []domain.Role{domain.RoleSupport}})
+}
+
+// Helper function to build the context of a request made by an admin
+func adminContext() context.Context {
+	return domain.WithPrincipal(context.Background(), &domain.Principal{UserID: "9", Roles: []domain.Role{domain.RoleAdmin}})
+}
+
+func TestGrantRole_OK(t *testing.T) {
+	usm := setupUserService(t)
+	usm.repo.On("AddRole", mock.Anything, "1", domain.RoleSupport).Return(nil)
+
+	err := usm.service.GrantRole(adminContext(), "1", domain.RoleSupport)
+
+	assert.NoError(t, err)
+}
+
+func TestGrantRole_UnknownRole(t *testing.T) {
+	usm := setupUserService(t)
+
+	err := usm.service.GrantRole(adminContext(), "1", domain.Role("root"))
+
+	assert.ErrorIs(t, err, ErrUnknownRole)
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+}
+
+func TestGrantRole_Error(t *testing.T) {
+	usm := setupUserService(t)
+	usm.repo.On("AddRole", mock.Anything, "1", domain.RoleSupport).Return(domain.ErrNotFound)
+
+	err := usm.service.GrantRole(adminContext(), "1", domain.RoleSupport)
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestRevokeRole_OK(t *testing.T) {
+	usm := setupUserService(t)
+	usm.repo.On("RemoveRole", mock.Anything, "1", domain.RoleAdmin).Return(nil)
+	usm.tokenSrv.On("RevokeUserTokens", mock.Anything, "1").Return(nil)
+
+	err := usm.service.RevokeRole(adminContext(), "1", domain.RoleAdmin)
+
+	assert.NoError(t, err)
+}
+
+func TestRevokeRole_UnknownRole(t *testing.T) {
+	usm := setupUserService(t)
+
+	err := usm.service.RevokeRole(adminContext(), "1", domain.Role("root"))
+
+	assert.ErrorIs(t, err, ErrUnknownRole)
+}
+
+func TestRevokeRole_RemoveRoleError(t *testing.T) {
+	usm := setupUserService(t)
+	usm.repo.On("RemoveRole", mock.Anything, "1", domain.RoleAdmin).Return(assert.AnError)
+
+	err := usm.service.RevokeRole(adminContext(), "1", domain.RoleAdmin)
+
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestRevokeRole_RevokeTokensError(t *testing.T) {
+	usm := setupUserService(t)
+	usm.repo.On("RemoveRole", mock.Anything, "1", domain.RoleAdmin).Return(nil)
+	usm.tokenSrv.On("RevokeUserTokens", mock.Anything, "1").Return(assert.AnError)
+
+	err := usm.service.RevokeRole(adminContext(), "1", domain.RoleAdmin)
+
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestGrantRole_PermissionDenied(t *testing.T) {
+	ctx := domain.WithPrincipal(context.Background(), &domain.Principal{UserID: "1", Roles: []domain.Role{domain.RoleSupport}})
+
+	usm := setupUserService(t)
+
+	err := usm.service.GrantRole(ctx, "1", domain.RoleAdmin)
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+}
+
+func TestRevokeRole_Unauthenticated(t *testing.T) {
+	usm := setupUserService(t)
+
+	err := usm.service.RevokeRole(context.Context(nil), "1", domain.RoleSupport)
+
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+}
+
+func TestRevokeRole_OwnAdminRole(t *testing.T) {
+	usm := setupUserService(t)
+
+	err := usm.service.RevokeRole(adminContext(), "9", domain.RoleAdmin)
+
+	assert.ErrorIs(t, err, ErrSelfRevoke)
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
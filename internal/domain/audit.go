@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"time"
+)
+
+type AuditAction string
+
+const (
+	AuditScreeningPassed        AuditAction = "screening.passed"
+	AuditScreeningFailedOpen    AuditAction = "screening.failed_open"
+	AuditScreeningDeferred      AuditAction = "screening.deferred"
+	AuditScreeningPendingReview AuditAction = "screening.pending_review"
+	AuditScreeningPossibleMatch AuditAction = "screening.possible_match"
+	AuditReviewApproved         AuditAction = "review.approved"
+	AuditReviewRejected         AuditAction = "review.rejected"
+)
+
+type AuditEvent struct {
+	Action AuditAction
+	UserID string
+	// who made the decision, empty for the system
+	ActorID string
+	Details map[string]string
+	// set by the repository
+	CreatedAt time.Time
+}
@@ -0,0 +1,8 @@
+package domain
+
+import "context"
+
+// Append only, recorded events are never changed
+type AuditRepository interface {
+	RecordEvent(ctx context.Context, event *AuditEvent) error
+}
@@ -6,4 +6,10 @@ import (
 
 type AuthRepository interface {
 	GetIdAndHash(ctx context.Context, email string) (string, string, error)
+	// The current hash first, then the previous ones from newest to oldest
+	GetPasswordHashes(ctx context.Context, userID string) ([]string, error)
+	// The replaced hash is kept in the history, which holds up to historySize of them
+	UpdatePassword(ctx context.Context, userID, hash string, historySize int) error
+	// Swaps the hash for one of the same password, only if it is still oldHash
+	RehashPassword(ctx context.Context, userID, oldHash, newHash string) error
 }
@@ -0,0 +1,11 @@
+package domain
+
+import (
+	"context"
+)
+
+// k-anonymity lookup, only the first 5 hex characters of the SHA-1 of a password are sent
+type BreachedPasswordRepository interface {
+	// Uppercase hex SHA-1 suffixes of the breached passwords sharing the prefix
+	GetBreachedSuffixes(ctx context.Context, prefix string) ([]string, error)
+}
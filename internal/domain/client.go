@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"context"
+)
+
+type clientIPKey struct{}
+
+type userAgentKey struct{}
+
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// Returns an empty string when the caller IP is unknown
+func ClientIPFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+
+	return ip
+}
+
+func WithUserAgent(ctx context.Context, userAgent string) context.Context {
+	return context.WithValue(ctx, userAgentKey{}, userAgent)
+}
+
+// Returns an empty string when the caller did not send one
+func UserAgentFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	userAgent, _ := ctx.Value(userAgentKey{}).(string)
+
+	return userAgent
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "errors"
+
+// Kinds of failure, adapters translate into them and the http layer maps them to status codes
+var (
+	ErrNotFound            = errors.New("Not found")
+	ErrInvalidInput        = errors.New("Invalid input")
+	ErrInvalidCredentials  = errors.New("Invalid credentials")
+	ErrForbidden           = errors.New("Forbidden")
+	ErrConflict            = errors.New("Conflict")
+	ErrPreconditionFailed  = errors.New("Precondition failed")
+	ErrBlacklisted         = errors.New("User is in blacklist")
+	ErrRateLimited         = errors.New("Too many requests")
+	ErrUpstreamUnavailable = errors.New("Upstream service unavailable")
+	ErrOverloaded          = errors.New("Service overloaded")
+)
+
+// Error has its own message and still matches its kind with errors.Is
+type Error struct {
+	Kind    error
+	Message string
+}
+
+func NewError(kind error, message string) error {
+	return &Error{kind, message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
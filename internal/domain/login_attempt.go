@@ -0,0 +1,12 @@
+package domain
+
+import (
+	"time"
+)
+
+type LoginAttempts struct {
+	Key         string
+	Failures    int
+	LockedUntil time.Time
+	ExpiresAt   time.Time
+}
@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type LoginAttemptRepository interface {
+	GetLoginAttempts(ctx context.Context, key string) (*LoginAttempts, error)
+	// counting starts again once expiresAt is reached
+	AddLoginFailure(ctx context.Context, key string, expiresAt time.Time) (int, error)
+	LockLogin(ctx context.Context, key string, lockedUntil, expiresAt time.Time) error
+	ResetLoginAttempts(ctx context.Context, key string) error
+}
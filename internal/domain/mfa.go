@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+)
+
+type MFA struct {
+	UserID  string
+	Secret  string
+	Enabled bool
+	// hashes of the unused recovery codes
+	RecoveryCodes []string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type TOTPEnrollment struct {
+	Secret string
+	URI    string
+}
+
+type MFAChallenge struct {
+	Token     string
+	ExpiresIn time.Duration
+}
+
+// Either Tokens or Challenge is set
+type LoginResult struct {
+	Tokens    *TokenPair
+	Challenge *MFAChallenge
+}
@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"context"
+)
+
+type MFARepository interface {
+	SaveMFA(ctx context.Context, mfa *MFA) error
+	GetMFA(ctx context.Context, userID string) (*MFA, error)
+	EnableMFA(ctx context.Context, userID string, recoveryCodes []string) error
+	UseTOTPStep(ctx context.Context, userID string, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+	DeleteMFA(ctx context.Context, userID string) error
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+const (
+	PasswordResetPurpose     = "password_reset"
+	EmailVerificationPurpose = "email_verification"
+	MFAChallengePurpose      = "mfa_challenge"
+)
+
+type OneTimeToken struct {
+	Hash      string
+	UserID    string
+	Purpose   string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"context"
+)
+
+type OneTimeTokenRepository interface {
+	CreateOneTimeToken(ctx context.Context, token *OneTimeToken) error
+	// Reads the token without consuming it
+	GetOneTimeToken(ctx context.Context, tokenHash, purpose string) (*OneTimeToken, error)
+	ConsumeOneTimeToken(ctx context.Context, tokenHash, purpose string) (*OneTimeToken, error)
+	GetLatestOneTimeToken(ctx context.Context, userID, purpose string) (*OneTimeToken, error)
+	DeleteOneTimeTokens(ctx context.Context, userID, purpose string) error
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "context"
+
+type PasswordHashAlgorithm string
+
+const (
+	BcryptHash   PasswordHashAlgorithm = "bcrypt"
+	Argon2idHash PasswordHashAlgorithm = "argon2id"
+)
+
+// Parameters for new hashes, hashes made with other ones are upgraded on login
+type PasswordHashPolicy struct {
+	Algorithm  PasswordHashAlgorithm
+	BcryptCost int
+	// in KiB
+	Argon2Memory      uint32
+	Argon2Iterations  uint32
+	Argon2Parallelism uint8
+}
+
+type PasswordHasher interface {
+	Hash(ctx context.Context, password string) (string, error)
+	// Tells whether the password matches and whether the hash should be made again with the current policy
+	Verify(ctx context.Context, hash, password string) (bool, bool, error)
+}
@@ -3,5 +3,7 @@ package domain
 import "context"
 
 type PLDRepository interface {
-	IsValidUser(ctx context.Context, user *User) (bool, error)
+	ScreenUser(ctx context.Context, user *User) (*ScreeningResult, error)
+	// Names the service doing the screening, for the screening records
+	Provider() string
 }
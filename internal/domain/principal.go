@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"context"
+)
+
+type AuthMethod string
+
+const (
+	PasswordAuth AuthMethod = "password"
+	// password and a TOTP or recovery code
+	TOTPAuth AuthMethod = "totp"
+)
+
+// Number of factors the caller proved
+func (m AuthMethod) MFALevel() int {
+	if m == TOTPAuth {
+		return 2
+	}
+
+	return 1
+}
+
+// The authenticated caller of a request
+type Principal struct {
+	UserID     string
+	Roles      []Role
+	TokenID    string
+	SessionID  string
+	AuthMethod AuthMethod
+	MFALevel   int
+}
+
+func (p *Principal) HasPermission(permission Permission) bool {
+	return HasPermission(p.Roles, permission)
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// Returns false when the request is not authenticated
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+
+	return principal, ok && principal != nil
+}
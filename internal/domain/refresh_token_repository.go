@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"context"
+)
+
+type RefreshTokenRepository interface {
+	CreateRefreshToken(ctx context.Context, token *RefreshToken) error
+	GetRefreshToken(ctx context.Context, tokenHash string) (*RefreshToken, error)
+	UseRefreshToken(ctx context.Context, tokenHash string) (bool, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string) error
+	RevokeUserRefreshTokens(ctx context.Context, userID string) error
+	// Revokes every family of the user except the given one
+	RevokeOtherRefreshTokens(ctx context.Context, userID, familyID string) error
+}
@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type RevokedTokenRepository interface {
+	RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+	// Bumps the token generation of the user, rejecting every token issued before
+	RevokeUserTokens(ctx context.Context, userID string) error
+	GetTokenGeneration(ctx context.Context, userID string) (int64, error)
+	RevokeSession(ctx context.Context, sessionID string, expiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, token *AccessToken) (bool, error)
+}
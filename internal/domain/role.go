@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"slices"
+)
+
+type Role string
+
+const (
+	// every user has it, only grants access to its own account
+	RoleUser       Role = "user"
+	RoleSupport    Role = "support"
+	RoleCompliance Role = "compliance"
+	RoleAdmin      Role = "admin"
+)
+
+type Permission string
+
+const (
+	PermissionReadUsers   Permission = "users:read"
+	PermissionUnlockUsers Permission = "users:unlock"
+	PermissionManageRoles Permission = "roles:manage"
+	PermissionCompliance  Permission = "compliance:review"
+	PermissionReadMetrics Permission = "metrics:read"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleUser:       {},
+	RoleSupport:    {PermissionReadUsers, PermissionUnlockUsers},
+	RoleCompliance: {PermissionReadUsers, PermissionCompliance},
+	RoleAdmin:      {PermissionReadUsers, PermissionUnlockUsers, PermissionManageRoles, PermissionCompliance, PermissionReadMetrics},
+}
+
+func (r Role) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Any of the roles granting the permission is enough
+func HasPermission(roles []Role, permission Permission) bool {
+	for _, role := range roles {
+		if slices.Contains(rolePermissions[role], permission) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+type PLDOutagePolicy string
+
+const (
+	// signups fail while the PLD service is unavailable
+	FailClosedOnOutage PLDOutagePolicy = "fail_closed"
+	// users are created and can log in, they are screened once the service is back
+	FailOpenOnOutage PLDOutagePolicy = "fail_open"
+	// users are created but cannot log in until they are screened
+	DeferOnOutage PLDOutagePolicy = "defer"
+)
+
+type ScreeningStatus string
+
+const (
+	ScreeningPassed ScreeningStatus = "passed"
+	// created by the fail open policy, waiting for a retry
+	ScreeningFlagged ScreeningStatus = "flagged"
+	// created by the defer policy, waiting for a retry
+	ScreeningPending ScreeningStatus = "pending_screening"
+	// a hard match, cannot log in until compliance approves it
+	ScreeningPendingReview ScreeningStatus = "pending_review"
+	// rejected by compliance
+	ScreeningBlacklisted ScreeningStatus = "blacklisted"
+	// a fuzzy match, left for compliance to look at
+	ScreeningPossibleMatch ScreeningStatus = "possible_match"
+)
+
+// Verdict of the PLD service
+type ScreeningResult struct {
+	InBlacklist bool
+	// from 0 to 1, how close the best match is
+	Score float64
+	// names of the lists the match was found in
+	MatchedLists []string
+	// id of the matched person in the provider
+	EntityID    string
+	ReasonCodes []string
+}
+
+// One call to the PLD service, names and emails are only kept as SHA-256 hashes
+type ScreeningRecord struct {
+	ID string `json:"id"`
+	// empty when the signup did not create the user
+	UserID    string `json:"user_id,omitempty"`
+	NameHash  string `json:"name_hash"`
+	EmailHash string `json:"email_hash"`
+	Provider  string `json:"provider"`
+	// as answered by the provider, missing when the call failed
+	InBlacklist  *bool     `json:"in_blacklist,omitempty"`
+	Score        float64   `json:"score,omitempty"`
+	MatchedLists []string  `json:"matched_lists,omitempty"`
+	EntityID     string    `json:"entity_id,omitempty"`
+	ReasonCodes  []string  `json:"reason_codes,omitempty"`
+	LatencyMS    int64     `json:"latency_ms"`
+	Error        string    `json:"error,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Empty fields do not filter
+type ScreeningFilter struct {
+	UserID        string
+	EmailHash     string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+}
+
+// Newest first
+type ScreeningQuery struct {
+	Filter ScreeningFilter
+	// opaque, taken from the previous page
+	Cursor string
+	Limit  int
+}
+
+type ScreeningPage struct {
+	Screenings []*ScreeningRecord `json:"screenings"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
+// A user waiting for a compliance decision, with the screenings behind it
+type ReviewCase struct {
+	User *User `json:"user"`
+	// newest first
+	Screenings []*ScreeningRecord `json:"screenings"`
+}
+
+// Normalized before hashing, so records can be found from the submitted values
+func HashScreeningSubject(value string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(value))))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,9 @@
+package domain
+
+import "context"
+
+// Append only, saved records are never changed
+type ScreeningRepository interface {
+	SaveScreening(ctx context.Context, record *ScreeningRecord) error
+	ListScreenings(ctx context.Context, query *ScreeningQuery) (*ScreeningPage, error)
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"time"
+)
+
+// A login and the tokens refreshed from it, its id is the refresh token family
+type Session struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"-"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
+	AuthMethod AuthMethod `json:"auth_method"`
+	CreatedAt  time.Time  `json:"created_at"`
+	// updated on every token refresh
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// the session of the access token used to list them
+	Current bool `json:"current"`
+}
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"context"
+)
+
+type SessionRepository interface {
+	// Creates the session, or updates where and when it was last seen
+	SaveSession(ctx context.Context, session *Session) error
+	// Only the sessions that are not revoked nor expired
+	ListSessions(ctx context.Context, userID string) ([]*Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+	RevokeUserSessions(ctx context.Context, userID string) error
+	// Removes the sessions of a user, revoked or not, with where they were used from
+	DeleteUserSessions(ctx context.Context, userID string) error
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+type AccessToken struct {
+	ID        string
+	UserID    string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	// only valid for routes that do not need a verified email
+	Restricted bool
+	Roles      []Role
+	AuthMethod AuthMethod
+	SessionID  string
+	// tokens of an older generation were revoked along with every other token of the user
+	Generation int64
+}
+
+type RefreshToken struct {
+	Hash     string
+	UserID   string
+	FamilyID string
+	// kept by the tokens issued on refresh
+	AuthMethod AuthMethod
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	UsedAt     time.Time
+	RevokedAt  time.Time
+}
+
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration
+}
@@ -0,0 +1,5 @@
+package domain
+
+type TokenSigner interface {
+	Sign(token *AccessToken) (string, error)
+}
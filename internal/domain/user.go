@@ -4,12 +4,46 @@ import (
 	"time"
 )
 
+type UnverifiedEmailPolicy string
+
+const (
+	// unverified users get full tokens
+	AllowUnverified UnverifiedEmailPolicy = "allow"
+	// unverified users get tokens only valid for a few routes
+	RestrictUnverified UnverifiedEmailPolicy = "restricted"
+	// unverified users cannot log in
+	DenyUnverified UnverifiedEmailPolicy = "deny"
+)
+
+type DeletedEmailPolicy string
+
+const (
+	// the email can sign up again as soon as the account is deleted
+	ReleaseDeletedEmail DeletedEmailPolicy = "release"
+	// the email stays taken until the account is purged
+	RetainDeletedEmail DeletedEmailPolicy = "retain"
+)
+
 type User struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email" validate:"required,email"`
-	Password  string    `json:"password,omitempty" validate:"required,min=8"`
-	FirstName string    `json:"first_name" validate:"required,alpha"`
-	LastName  string    `json:"last_name" validate:"required,alpha"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            string     `json:"id"`
+	Email         string     `json:"email" validate:"required,email"`
+	Password      string     `json:"password,omitempty" validate:"required"`
+	FirstName     string     `json:"first_name" validate:"required,alpha"`
+	LastName      string     `json:"last_name" validate:"required,alpha"`
+	EmailVerified bool       `json:"email_verified"`
+	Roles         []Role     `json:"roles,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+	// outcome of the PLD screening, see PLDOutagePolicy, never shown to the user
+	// itself since telling someone they are under review tips them off
+	ScreeningStatus ScreeningStatus `json:"-"`
+	// bumped on every change, sent as the ETag
+	Version int64 `json:"-"`
+}
+
+// Only the fields that are set get updated
+type UserUpdate struct {
+	FirstName *string
+	LastName  *string
 }
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+)
+
+type UserStatus string
+
+const (
+	ActiveUser     UserStatus = "active"
+	UnverifiedUser UserStatus = "unverified"
+	DeletedUser    UserStatus = "deleted"
+)
+
+type UserSort string
+
+const (
+	// creation order, the default
+	SortByCreatedAt UserSort = "created_at"
+	// users without an email, like purged ones, are left out
+	SortByEmail UserSort = "email"
+)
+
+// Empty fields do not filter, except Status
+type UserFilter struct {
+	EmailPrefix string
+	Name        string
+	// when empty, every user but the deleted ones
+	Status UserStatus
+	// any of them
+	ScreeningStatuses []ScreeningStatus
+	CreatedAfter      time.Time
+	CreatedBefore     time.Time
+}
+
+type UserQuery struct {
+	Filter     UserFilter
+	Sort       UserSort
+	Descending bool
+	// opaque, taken from the previous page
+	Cursor string
+	Limit  int
+}
+
+type UserPage struct {
+	Users      []*User `json:"users"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+	Total      *int64  `json:"total,omitempty"`
+}
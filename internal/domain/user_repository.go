@@ -2,9 +2,27 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *User) error
 	GetUser(ctx context.Context, userID string) (*User, error)
+	// Saves the names if the stored version still matches user.Version
+	UpdateUser(ctx context.Context, user *User) error
+	MarkEmailVerified(ctx context.Context, userID string) error
+	// Soft delete, deleted users are not found anymore
+	DeleteUser(ctx context.Context, userID string, releaseEmail bool) error
+	// Users deleted before the given time and not purged yet, with their email even when released
+	ListPurgeableUsers(ctx context.Context, deletedBefore time.Time, limit int) ([]*User, error)
+	// Removes the personal data of a deleted user, only the id and the dates are kept
+	PurgeUser(ctx context.Context, userID string) error
+	AddRole(ctx context.Context, userID string, role Role) error
+	RemoveRole(ctx context.Context, userID string, role Role) error
+	// Saves the status if the stored version still matches user.Version
+	SetScreeningStatus(ctx context.Context, user *User, status ScreeningStatus) error
+	// Users waiting for a screening retry, oldest first
+	ListUnscreenedUsers(ctx context.Context, limit int) ([]*User, error)
+	ListUsers(ctx context.Context, query *UserQuery) (*UserPage, error)
+	CountUsers(ctx context.Context, filter *UserFilter) (int64, error)
 }
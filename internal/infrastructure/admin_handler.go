@@ -0,0 +1,173 @@
+package infrastructure
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/application"
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type adminHandler struct {
+	lockout application.LockoutService
+	userSrv application.UserService
+}
+
+func NewAdminHandler(lockout application.LockoutService, userSrv application.UserService) *adminHandler {
+	return &adminHandler{lockout, userSrv}
+}
+
+type UnlockRequest struct {
+	Email string `json:"email" validate:"required_without=IP,omitempty,email"`
+	IP    string `json:"ip" validate:"required_without=Email,omitempty,ip"`
+}
+
+// Dates are RFC 3339, a leading "-" in sort means descending
+type ListUsersRequest struct {
+	Email         string    `query:"email" validate:"omitempty,max=254"`
+	Name          string    `query:"name" validate:"omitempty,alpha"`
+	Status        string    `query:"status" validate:"omitempty,oneof=active unverified deleted"`
+	CreatedAfter  time.Time `query:"created_after"`
+	CreatedBefore time.Time `query:"created_before"`
+	Sort          string    `query:"sort" validate:"omitempty,oneof=created_at -created_at email -email"`
+	Cursor        string    `query:"cursor"`
+	Limit         int       `query:"limit" validate:"omitempty,min=1,max=100"`
+	Total         bool      `query:"total"`
+}
+
+// The user as staff see it, with its screening status
+type UserDetailsResponse struct {
+	*domain.User
+	ScreeningStatus domain.ScreeningStatus `json:"screening_status,omitempty"`
+}
+
+type UserPageResponse struct {
+	Users      []*UserDetailsResponse `json:"users"`
+	NextCursor string                 `json:"next_cursor,omitempty"`
+	Total      *int64                 `json:"total,omitempty"`
+}
+
+func newUserDetailsResponse(user *domain.User) *UserDetailsResponse {
+	return &UserDetailsResponse{user, user.ScreeningStatus}
+}
+
+func newUserPageResponse(page *domain.UserPage) *UserPageResponse {
+	users := make([]*UserDetailsResponse, len(page.Users))
+	for i, user := range page.Users {
+		users[i] = newUserDetailsResponse(user)
+	}
+
+	return &UserPageResponse{users, page.NextCursor, page.Total}
+}
+
+type RoleRequest struct {
+	UserID string `param:"id" validate:"required"`
+	Role   string `param:"role" validate:"required,oneof=user support compliance admin"`
+}
+
+func (h *adminHandler) Unlock(c echo.Context) error {
+	ctx := c.Request().Context()
+	request := new(UnlockRequest)
+
+	if err := c.Bind(request); err != nil {
+		return err
+	}
+
+	if validate, ok := c.Get(ValidatorCtxKey).(*validator.Validate); ok {
+		if err := validate.Struct(request); err != nil {
+			return err
+		}
+	}
+
+	err := h.lockout.Unlock(ctx, request.Email, request.IP)
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *adminHandler) ListUsers(c echo.Context) error {
+	ctx := c.Request().Context()
+	request := new(ListUsersRequest)
+
+	if err := c.Bind(request); err != nil {
+		return err
+	}
+
+	if validate, ok := c.Get(ValidatorCtxKey).(*validator.Validate); ok {
+		if err := validate.Struct(request); err != nil {
+			return err
+		}
+	}
+
+	query := &domain.UserQuery{
+		Filter: domain.UserFilter{
+			EmailPrefix:   request.Email,
+			Name:          request.Name,
+			Status:        domain.UserStatus(request.Status),
+			CreatedAfter:  request.CreatedAfter,
+			CreatedBefore: request.CreatedBefore,
+		},
+		Sort:       domain.UserSort(strings.TrimPrefix(request.Sort, "-")),
+		Descending: strings.HasPrefix(request.Sort, "-"),
+		Cursor:     request.Cursor,
+		Limit:      request.Limit,
+	}
+
+	page, err := h.userSrv.ListUsers(ctx, query, request.Total)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newUserPageResponse(page))
+}
+
+func (h *adminHandler) GrantRole(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	request, err := bindRoleRequest(c)
+	if err != nil {
+		return err
+	}
+
+	if err = h.userSrv.GrantRole(ctx, request.UserID, domain.Role(request.Role)); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *adminHandler) RevokeRole(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	request, err := bindRoleRequest(c)
+	if err != nil {
+		return err
+	}
+
+	if err = h.userSrv.RevokeRole(ctx, request.UserID, domain.Role(request.Role)); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func bindRoleRequest(c echo.Context) (*RoleRequest, error) {
+	request := new(RoleRequest)
+
+	if err := c.Bind(request); err != nil {
+		return nil, err
+	}
+
+	if validate, ok := c.Get(ValidatorCtxKey).(*validator.Validate); ok {
+		if err := validate.Struct(request); err != nil {
+			return nil, err
+		}
+	}
+
+	return request, nil
+}
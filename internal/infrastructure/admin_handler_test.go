@@ -0,0 +1,247 @@
+package infrastructure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type adminHandlerMock struct {
+	lockout *mocks.LockoutService
+	userSrv *mocks.UserService
+	handler *adminHandler
+}
+
+func setupAdminHandler(t *testing.T) *adminHandlerMock {
+	mockLockoutService := mocks.NewLockoutService(t)
+	mockUserService := mocks.NewUserService(t)
+
+	return &adminHandlerMock{
+		lockout: mockLockoutService,
+		userSrv: mockUserService,
+		handler: NewAdminHandler(mockLockoutService, mockUserService),
+	}
+}
+
+func TestUnlock_OK(t *testing.T) {
+	ctx, rec := newUserJSONContext(http.MethodPost, "/v1/admin/users/unlock", `{"email": "an@email.com", "ip": "10.0.0.1"}`)
+
+	ah := setupAdminHandler(t)
+	ah.lockout.On("Unlock", mock.Anything, "an@email.com", "10.0.0.1").Return(nil)
+
+	err := SetValidator(ah.handler.Unlock)(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestUnlock_OnlyIP(t *testing.T) {
+	ctx, rec := newUserJSONContext(http.MethodPost, "/v1/admin/users/unlock", `{"ip": "10.0.0.1"}`)
+
+	ah := setupAdminHandler(t)
+	ah.lockout.On("Unlock", mock.Anything, "", "10.0.0.1").Return(nil)
+
+	err := SetValidator(ah.handler.Unlock)(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestUnlock_BindError(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodPost, "/v1/admin/users/unlock", `{`)
+
+	ah := setupAdminHandler(t)
+
+	err := ah.handler.Unlock(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+}
+
+func TestUnlock_ValidateError(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodPost, "/v1/admin/users/unlock", `{}`)
+
+	ah := setupAdminHandler(t)
+
+	err := SetValidator(ah.handler.Unlock)(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+}
+
+func TestUnlock_InvalidIP(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodPost, "/v1/admin/users/unlock", `{"ip": "nope"}`)
+
+	ah := setupAdminHandler(t)
+
+	err := SetValidator(ah.handler.Unlock)(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+}
+
+func TestUnlock_UnlockError(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodPost, "/v1/admin/users/unlock", `{"email": "an@email.com"}`)
+
+	ah := setupAdminHandler(t)
+	ah.lockout.On("Unlock", mock.Anything, "an@email.com", "").Return(assert.AnError)
+
+	err := SetValidator(ah.handler.Unlock)(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, problem.Status)
+}
+
+func TestListUsers_OK(t *testing.T) {
+	ctx, rec := newUserJSONContext(http.MethodGet, "/v1/admin/users?email=an&name=Chris&status=active&created_after=2025-01-01T00:00:00Z&sort=-email&cursor=abc&limit=10&total=true", "")
+	expected := &domain.UserQuery{
+		Filter: domain.UserFilter{
+			EmailPrefix:  "an",
+			Name:         "Chris",
+			Status:       domain.ActiveUser,
+			CreatedAfter: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Sort:       domain.SortByEmail,
+		Descending: true,
+		Cursor:     "abc",
+		Limit:      10,
+	}
+	total := int64(1)
+	page := &domain.UserPage{Users: []*domain.User{{ID: "1", ScreeningStatus: domain.ScreeningPassed}}, NextCursor: "def", Total: &total}
+
+	ah := setupAdminHandler(t)
+	ah.userSrv.On("ListUsers", mock.Anything, expected, true).Return(page, nil)
+
+	err := SetValidator(ah.handler.ListUsers)(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"screening_status":"passed"`)
+	assert.Contains(t, rec.Body.String(), `"next_cursor":"def","total":1`)
+}
+
+func TestListUsers_Defaults(t *testing.T) {
+	ctx, rec := newUserJSONContext(http.MethodGet, "/v1/admin/users", "")
+
+	ah := setupAdminHandler(t)
+	ah.userSrv.On("ListUsers", mock.Anything, &domain.UserQuery{}, false).Return(&domain.UserPage{Users: []*domain.User{}}, nil)
+
+	err := SetValidator(ah.handler.ListUsers)(ctx)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"users":[]}`, rec.Body.String())
+}
+
+func TestListUsers_ValidationError(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodGet, "/v1/admin/users?status=locked&limit=500", "")
+
+	ah := setupAdminHandler(t)
+
+	err := SetValidator(ah.handler.ListUsers)(ctx)
+	problem := newProblem(err)
+
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "status", problem.Errors[0].Field)
+	assert.Equal(t, "oneof", problem.Errors[0].Tag)
+	assert.Equal(t, "limit", problem.Errors[1].Field)
+}
+
+func TestListUsers_BindError(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodGet, "/v1/admin/users?created_after=yesterday", "")
+
+	ah := setupAdminHandler(t)
+
+	err := SetValidator(ah.handler.ListUsers)(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, newProblem(err).Status)
+}
+
+func TestListUsers_ServiceError(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodGet, "/v1/admin/users?cursor=abc", "")
+
+	ah := setupAdminHandler(t)
+	ah.userSrv.On("ListUsers", mock.Anything, mock.AnythingOfType("*domain.UserQuery"), false).Return(nil, ErrInvalidCursor)
+
+	err := SetValidator(ah.handler.ListUsers)(ctx)
+
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+}
+
+// Helper function to build a role request, path params are set by the router
+func newRoleContext(method, userID, role string) (echo.Context, *httptest.ResponseRecorder) {
+	ctx, rec := newUserJSONContext(method, "/v1/admin/users/"+userID+"/roles/"+role, "")
+	ctx.SetParamNames("id", "role")
+	ctx.SetParamValues(userID, role)
+
+	return ctx, rec
+}
+
+func TestGrantRole_OK(t *testing.T) {
+	ctx, rec := newRoleContext(http.MethodPut, "2", "support")
+
+	ah := setupAdminHandler(t)
+	ah.userSrv.On("GrantRole", mock.Anything, "2", domain.RoleSupport).Return(nil)
+
+	err := SetValidator(ah.handler.GrantRole)(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestGrantRole_UnknownRole(t *testing.T) {
+	ctx, _ := newRoleContext(http.MethodPut, "2", "root")
+
+	ah := setupAdminHandler(t)
+
+	err := SetValidator(ah.handler.GrantRole)(ctx)
+	problem := newProblem(err)
+
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "role", problem.Errors[0].Field)
+	assert.Equal(t, "oneof", problem.Errors[0].Tag)
+}
+
+func TestGrantRole_NotFound(t *testing.T) {
+	ctx, _ := newRoleContext(http.MethodPut, "2", "admin")
+
+	ah := setupAdminHandler(t)
+	ah.userSrv.On("GrantRole", mock.Anything, "2", domain.RoleAdmin).Return(domain.ErrNotFound)
+
+	err := SetValidator(ah.handler.GrantRole)(ctx)
+
+	assert.Equal(t, http.StatusNotFound, newProblem(err).Status)
+}
+
+func TestRevokeRole_OK(t *testing.T) {
+	ctx, rec := newRoleContext(http.MethodDelete, "2", "compliance")
+
+	ah := setupAdminHandler(t)
+	ah.userSrv.On("RevokeRole", mock.Anything, "2", domain.RoleCompliance).Return(nil)
+
+	err := SetValidator(ah.handler.RevokeRole)(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestRevokeRole_Error(t *testing.T) {
+	ctx, _ := newRoleContext(http.MethodDelete, "2", "compliance")
+
+	ah := setupAdminHandler(t)
+	ah.userSrv.On("RevokeRole", mock.Anything, "2", domain.RoleCompliance).Return(assert.AnError)
+
+	err := SetValidator(ah.handler.RevokeRole)(ctx)
+
+	assert.ErrorIs(t, err, assert.AnError)
+}
@@ -1,31 +1,61 @@
 package infrastructure
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/christhianjesus/crabi-challenge/internal/application"
 	"github.com/christhianjesus/crabi-challenge/internal/domain"
 	"github.com/go-playground/validator/v10"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 )
 
 type authHandler struct {
 	srv application.AuthService
-	sec string
 }
 
-func NewAuthHandler(srv application.AuthService, secret string) *authHandler {
-	return &authHandler{srv, secret}
+func NewAuthHandler(srv application.AuthService) *authHandler {
+	return &authHandler{srv}
 }
 
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=8"`
+	Password string `json:"password" validate:"required"`
 }
 
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+type LoginMFARequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func newLoginResponse(pair *domain.TokenPair) *LoginResponse {
+	return &LoginResponse{
+		Token:        pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(pair.ExpiresIn.Seconds()),
+	}
 }
 
 func (h *authHandler) Login(c echo.Context) error {
@@ -38,25 +68,73 @@ func (h *authHandler) Login(c echo.Context) error {
 
 	if validate, ok := c.Get(ValidatorCtxKey).(*validator.Validate); ok {
 		if err := validate.Struct(request); err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return err
 		}
 	}
 
-	userID, err := h.srv.Login(ctx, request.Email, request.Password)
+	result, err := h.srv.Login(ctx, request.Email, request.Password)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": userID,
-	})
+	if result.Challenge != nil {
+		return c.JSON(http.StatusOK, &MFAChallengeResponse{
+			MFARequired: true,
+			MFAToken:    result.Challenge.Token,
+			ExpiresIn:   int64(result.Challenge.ExpiresIn.Seconds()),
+		})
+	}
+
+	return c.JSON(http.StatusOK, newLoginResponse(result.Tokens))
+}
 
-	tokenString, err := token.SignedString([]byte(h.sec))
+func (h *authHandler) LoginMFA(c echo.Context) error {
+	ctx := c.Request().Context()
+	request := new(LoginMFARequest)
+
+	if err := c.Bind(request); err != nil {
+		return err
+	}
+
+	if validate, ok := c.Get(ValidatorCtxKey).(*validator.Validate); ok {
+		if err := validate.Struct(request); err != nil {
+			return err
+		}
+	}
+
+	pair, err := h.srv.LoginMFA(ctx, request.MFAToken, request.Code)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		// a wrong code means logging in again, not fixing the request
+		if errors.Is(err, application.ErrInvalidMFACode) || errors.Is(err, application.ErrMFANotEnrolled) {
+			return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		}
+
+		return err
 	}
 
-	return c.JSON(http.StatusOK, &LoginResponse{Token: tokenString})
+	return c.JSON(http.StatusOK, newLoginResponse(pair))
+}
+
+func (h *authHandler) Refresh(c echo.Context) error {
+	ctx := c.Request().Context()
+	request := new(RefreshRequest)
+
+	if err := c.Bind(request); err != nil {
+		return err
+	}
+
+	if validate, ok := c.Get(ValidatorCtxKey).(*validator.Validate); ok {
+		if err := validate.Struct(request); err != nil {
+			return err
+		}
+	}
+
+	pair, err := h.srv.Refresh(ctx, request.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newLoginResponse(pair))
 }
 
 func (h *authHandler) Signin(c echo.Context) error {
@@ -69,14 +147,35 @@ func (h *authHandler) Signin(c echo.Context) error {
 
 	if validate, ok := c.Get(ValidatorCtxKey).(*validator.Validate); ok {
 		if err := validate.Struct(request); err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return err
 		}
 	}
 
 	err := h.srv.Signin(ctx, request)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	return c.NoContent(http.StatusCreated)
 }
+
+func (h *authHandler) Logout(c echo.Context) error {
+	ctx := c.Request().Context()
+	request := new(LogoutRequest)
+
+	if err := c.Bind(request); err != nil {
+		return err
+	}
+
+	token, ok := accessTokenFromContext(c)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing access token")
+	}
+
+	err := h.srv.Logout(ctx, token, request.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,105 @@
+package infrastructure
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+)
+
+const (
+	breachedPrefixLength = 5
+	// the biggest Pwned Passwords range is around 40 KiB
+	maxBreachedRangeSize = 1 << 20
+)
+
+type breachedPasswordRepository struct {
+	// one range file per prefix, read on every lookup, empty when no list is configured
+	dir string
+}
+
+// Knows no breached password, used when no list is configured
+func NewBreachedPasswordRepository() domain.BreachedPasswordRepository {
+	return &breachedPasswordRepository{}
+}
+
+// Reads the ranges of the Pwned Passwords list from a directory with one file per
+// prefix, like E38AD.txt, holding the hash suffixes of that prefix one per line,
+// each one optionally followed by ":" and its count, as the range API answers them
+func OpenBreachedPasswordRepository(dir string) (domain.BreachedPasswordRepository, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s: not a directory", dir)
+	}
+
+	return &breachedPasswordRepository{dir}, nil
+}
+
+func (r *breachedPasswordRepository) GetBreachedSuffixes(ctx context.Context, prefix string) ([]string, error) {
+	if r.dir == "" {
+		return nil, nil
+	}
+
+	// the prefix becomes a file name, nothing but hex can get through
+	if !isHex(prefix) || len(prefix) != breachedPrefixLength {
+		return nil, fmt.Errorf("invalid hash prefix %q", prefix)
+	}
+
+	file := filepath.Join(r.dir, strings.ToUpper(prefix)+".txt")
+
+	f, err := os.Open(file)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Size() > maxBreachedRangeSize {
+		return nil, fmt.Errorf("%s: range is larger than %d bytes", file, maxBreachedRangeSize)
+	}
+
+	var suffixes []string
+	scanner := bufio.NewScanner(f)
+
+	for line := 1; scanner.Scan(); line++ {
+		suffix, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if suffix == "" {
+			continue
+		}
+
+		if !isHex(suffix) || len(suffix) != 40-breachedPrefixLength {
+			return nil, fmt.Errorf("%s:%d: invalid SHA-1 hash suffix", file, line)
+		}
+
+		suffixes = append(suffixes, strings.ToUpper(suffix))
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return suffixes, nil
+}
+
+// Helper function to tell hex strings of any length apart
+func isHex(value string) bool {
+	return strings.Trim(value, "0123456789abcdefABCDEF") == ""
+}
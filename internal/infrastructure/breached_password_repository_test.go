@@ -0,0 +1,115 @@
+package infrastructure
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Helper function to write breached password ranges into a temporary directory
+func writeBreachedRanges(t *testing.T, ranges map[string]string) string {
+	dir := t.TempDir()
+	for prefix, content := range ranges {
+		_ = os.WriteFile(filepath.Join(dir, prefix+".txt"), []byte(content), 0600)
+	}
+
+	return dir
+}
+
+func TestGetBreachedSuffixes_OK(t *testing.T) {
+	// password1 with its count, then a bare lowercase suffix
+	dir := writeBreachedRanges(t, map[string]string{"E38AD": "214943DAAD1D64C102FAEC29DE4AFE9DA3D:2413945\r\n\r\n0000000000000000000000000000000000a\r\n"})
+
+	repo, err := OpenBreachedPasswordRepository(dir)
+	assert.NoError(t, err)
+
+	suffixes, err := repo.GetBreachedSuffixes(context.Context(nil), "e38ad")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"214943DAAD1D64C102FAEC29DE4AFE9DA3D", "0000000000000000000000000000000000A"}, suffixes)
+}
+
+func TestGetBreachedSuffixes_ReadOnLookup(t *testing.T) {
+	dir := writeBreachedRanges(t, nil)
+
+	repo, err := OpenBreachedPasswordRepository(dir)
+	assert.NoError(t, err)
+
+	// nothing is loaded up front, ranges written afterwards are found
+	assert.Equal(t, &breachedPasswordRepository{dir}, repo)
+	_ = os.WriteFile(filepath.Join(dir, "E38AD.txt"), []byte("214943DAAD1D64C102FAEC29DE4AFE9DA3D:1\n"), 0600)
+
+	suffixes, err := repo.GetBreachedSuffixes(context.Context(nil), "E38AD")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"214943DAAD1D64C102FAEC29DE4AFE9DA3D"}, suffixes)
+}
+
+func TestGetBreachedSuffixes_MissingRange(t *testing.T) {
+	repo, _ := OpenBreachedPasswordRepository(writeBreachedRanges(t, nil))
+
+	suffixes, err := repo.GetBreachedSuffixes(context.Context(nil), "E38AD")
+
+	assert.NoError(t, err)
+	assert.Empty(t, suffixes)
+}
+
+func TestGetBreachedSuffixes_InvalidPrefix(t *testing.T) {
+	repo, _ := OpenBreachedPasswordRepository(writeBreachedRanges(t, nil))
+
+	suffixes, err := repo.GetBreachedSuffixes(context.Context(nil), "../..")
+
+	assert.EqualError(t, err, `invalid hash prefix "../.."`)
+	assert.Nil(t, suffixes)
+}
+
+func TestGetBreachedSuffixes_InvalidSuffix(t *testing.T) {
+	dir := writeBreachedRanges(t, map[string]string{"E38AD": "214943DAAD1D64C102FAEC29DE4AFE9DA3D\nnot a hash\n"})
+	repo, _ := OpenBreachedPasswordRepository(dir)
+
+	suffixes, err := repo.GetBreachedSuffixes(context.Context(nil), "E38AD")
+
+	assert.EqualError(t, err, filepath.Join(dir, "E38AD.txt")+":2: invalid SHA-1 hash suffix")
+	assert.Nil(t, suffixes)
+}
+
+func TestGetBreachedSuffixes_RangeTooLarge(t *testing.T) {
+	line := "214943DAAD1D64C102FAEC29DE4AFE9DA3D:1\n"
+	dir := writeBreachedRanges(t, map[string]string{"E38AD": strings.Repeat(line, maxBreachedRangeSize/len(line)+1)})
+	repo, _ := OpenBreachedPasswordRepository(dir)
+
+	suffixes, err := repo.GetBreachedSuffixes(context.Context(nil), "E38AD")
+
+	assert.ErrorContains(t, err, "range is larger than 1048576 bytes")
+	assert.Nil(t, suffixes)
+}
+
+func TestOpenBreachedPasswordRepository_MissingDir(t *testing.T) {
+	repo, err := OpenBreachedPasswordRepository(filepath.Join(t.TempDir(), "missing"))
+
+	assert.Error(t, err)
+	assert.Nil(t, repo)
+}
+
+func TestOpenBreachedPasswordRepository_NotADir(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "pwned.txt")
+	_ = os.WriteFile(file, nil, 0600)
+
+	repo, err := OpenBreachedPasswordRepository(file)
+
+	assert.EqualError(t, err, file+": not a directory")
+	assert.Nil(t, repo)
+}
+
+func TestGetBreachedSuffixes_NoList(t *testing.T) {
+	repo := NewBreachedPasswordRepository()
+
+	suffixes, err := repo.GetBreachedSuffixes(context.Context(nil), "E38AD")
+
+	assert.NoError(t, err)
+	assert.Empty(t, suffixes)
+}
@@ -0,0 +1,72 @@
+package infrastructure
+
+import (
+	"sync"
+	"time"
+)
+
+// Opens after threshold failures in a row and turns calls away for the cooldown,
+// then lets a single trial call through: a success closes it, a failure opens it again
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	// a trial call is running while half open
+	probing bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+// Tells whether a call can go through, and if not how long until it may
+func (b *circuitBreaker) allow() (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return 0, true
+	}
+
+	if wait := time.Until(b.openUntil); wait > 0 {
+		return wait, false
+	}
+
+	if b.probing {
+		return b.cooldown, false
+	}
+
+	b.probing = true
+
+	return 0, true
+}
+
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probing = false
+}
+
+// Ends a call that tells nothing about the service, such as one the caller cancelled
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+func (b *circuitBreaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
+}
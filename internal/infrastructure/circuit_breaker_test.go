@@ -0,0 +1,75 @@
+package infrastructure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker_OpensAfterThreshold(t *testing.T) {
+	breaker := newCircuitBreaker(2, time.Minute)
+
+	breaker.failure()
+	_, ok := breaker.allow()
+	assert.True(t, ok)
+
+	breaker.failure()
+	wait, ok := breaker.allow()
+	assert.False(t, ok)
+	assert.InDelta(t, time.Minute, wait, float64(time.Second))
+}
+
+func TestCircuitBreaker_SuccessResetsFailures(t *testing.T) {
+	breaker := newCircuitBreaker(2, time.Minute)
+
+	breaker.failure()
+	breaker.success()
+	breaker.failure()
+	_, ok := breaker.allow()
+
+	assert.True(t, ok)
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	breaker := newCircuitBreaker(1, 10*time.Millisecond)
+	breaker.failure()
+	time.Sleep(20 * time.Millisecond)
+
+	// a single trial call goes through
+	_, ok := breaker.allow()
+	assert.True(t, ok)
+	_, ok = breaker.allow()
+	assert.False(t, ok)
+
+	breaker.success()
+	_, ok = breaker.allow()
+	assert.True(t, ok)
+}
+
+func TestCircuitBreaker_HalfOpenFailure(t *testing.T) {
+	breaker := newCircuitBreaker(1, 10*time.Millisecond)
+	breaker.failure()
+	time.Sleep(20 * time.Millisecond)
+
+	_, _ = breaker.allow()
+	breaker.failure()
+	_, ok := breaker.allow()
+
+	assert.False(t, ok)
+}
+
+func TestCircuitBreaker_HalfOpenRelease(t *testing.T) {
+	breaker := newCircuitBreaker(1, 10*time.Millisecond)
+	breaker.failure()
+	time.Sleep(20 * time.Millisecond)
+
+	_, _ = breaker.allow()
+	breaker.release()
+
+	// still half open, a new trial call goes through but only one
+	_, ok := breaker.allow()
+	assert.True(t, ok)
+	_, ok = breaker.allow()
+	assert.False(t, ok)
+}
@@ -0,0 +1,160 @@
+package infrastructure
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/application"
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type complianceHandler struct {
+	srv application.ComplianceService
+}
+
+func NewComplianceHandler(srv application.ComplianceService) *complianceHandler {
+	return &complianceHandler{srv}
+}
+
+// Dates are RFC 3339, the email is hashed the same way as in the records
+type ListScreeningsRequest struct {
+	UserID        string    `query:"user_id"`
+	Email         string    `query:"email" validate:"omitempty,max=254"`
+	CreatedAfter  time.Time `query:"created_after"`
+	CreatedBefore time.Time `query:"created_before"`
+	Cursor        string    `query:"cursor"`
+	Limit         int       `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type ListReviewsRequest struct {
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// The comment is mandatory, it explains the decision in the audit trail
+type ReviewDecisionRequest struct {
+	UserID  string `param:"id" validate:"required"`
+	Comment string `json:"comment" validate:"required,max=2000"`
+}
+
+type ReviewCaseResponse struct {
+	User       *UserDetailsResponse      `json:"user"`
+	Screenings []*domain.ScreeningRecord `json:"screenings"`
+}
+
+func (h *complianceHandler) ListScreenings(c echo.Context) error {
+	ctx := c.Request().Context()
+	request := new(ListScreeningsRequest)
+
+	if err := c.Bind(request); err != nil {
+		return err
+	}
+
+	if validate, ok := c.Get(ValidatorCtxKey).(*validator.Validate); ok {
+		if err := validate.Struct(request); err != nil {
+			return err
+		}
+	}
+
+	query := &domain.ScreeningQuery{
+		Filter: domain.ScreeningFilter{
+			UserID:        request.UserID,
+			CreatedAfter:  request.CreatedAfter,
+			CreatedBefore: request.CreatedBefore,
+		},
+		Cursor: request.Cursor,
+		Limit:  request.Limit,
+	}
+
+	if request.Email != "" {
+		query.Filter.EmailHash = domain.HashScreeningSubject(request.Email)
+	}
+
+	page, err := h.srv.ListScreenings(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, page)
+}
+
+func (h *complianceHandler) ListReviews(c echo.Context) error {
+	ctx := c.Request().Context()
+	request := new(ListReviewsRequest)
+
+	if err := c.Bind(request); err != nil {
+		return err
+	}
+
+	if validate, ok := c.Get(ValidatorCtxKey).(*validator.Validate); ok {
+		if err := validate.Struct(request); err != nil {
+			return err
+		}
+	}
+
+	page, err := h.srv.ListReviews(ctx, request.Cursor, request.Limit)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newUserPageResponse(page))
+}
+
+func (h *complianceHandler) GetReview(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	review, err := h.srv.GetReview(ctx, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &ReviewCaseResponse{newUserDetailsResponse(review.User), review.Screenings})
+}
+
+func (h *complianceHandler) ApproveReview(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	request, err := bindReviewDecisionRequest(c)
+	if err != nil {
+		return err
+	}
+
+	if err = h.srv.ApproveReview(ctx, request.UserID, request.Comment); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *complianceHandler) RejectReview(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	request, err := bindReviewDecisionRequest(c)
+	if err != nil {
+		return err
+	}
+
+	if err = h.srv.RejectReview(ctx, request.UserID, request.Comment); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func bindReviewDecisionRequest(c echo.Context) (*ReviewDecisionRequest, error) {
+	request := new(ReviewDecisionRequest)
+
+	if err := c.Bind(request); err != nil {
+		return nil, err
+	}
+
+	if validate, ok := c.Get(ValidatorCtxKey).(*validator.Validate); ok {
+		if err := validate.Struct(request); err != nil {
+			return nil, err
+		}
+	}
+
+	return request, nil
+}
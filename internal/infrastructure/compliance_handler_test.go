@@ -0,0 +1,224 @@
+package infrastructure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/application"
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type complianceHandlerMock struct {
+	srv     *mocks.ComplianceService
+	handler *complianceHandler
+}
+
+func setupComplianceHandler(t *testing.T) *complianceHandlerMock {
+	mockComplianceService := mocks.NewComplianceService(t)
+
+	return &complianceHandlerMock{
+		srv:     mockComplianceService,
+		handler: NewComplianceHandler(mockComplianceService),
+	}
+}
+
+func TestListScreenings_OK(t *testing.T) {
+	ctx, rec := newUserJSONContext(http.MethodGet, "/v1/compliance/screenings?user_id=1&email=An@email.com&created_before=2025-01-01T00:00:00Z&cursor=abc&limit=10", "")
+	expected := &domain.ScreeningQuery{
+		Filter: domain.ScreeningFilter{
+			UserID:        "1",
+			EmailHash:     domain.HashScreeningSubject("an@email.com"),
+			CreatedBefore: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Cursor: "abc",
+		Limit:  10,
+	}
+	page := &domain.ScreeningPage{Screenings: []*domain.ScreeningRecord{{ID: "2", UserID: "1"}}, NextCursor: "2"}
+
+	ch := setupComplianceHandler(t)
+	ch.srv.On("ListScreenings", mock.Anything, expected).Return(page, nil)
+
+	err := SetValidator(ch.handler.ListScreenings)(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"next_cursor":"2"`)
+}
+
+func TestListScreenings_Defaults(t *testing.T) {
+	ctx, rec := newUserJSONContext(http.MethodGet, "/v1/compliance/screenings", "")
+
+	ch := setupComplianceHandler(t)
+	ch.srv.On("ListScreenings", mock.Anything, &domain.ScreeningQuery{}).Return(&domain.ScreeningPage{Screenings: []*domain.ScreeningRecord{}}, nil)
+
+	err := SetValidator(ch.handler.ListScreenings)(ctx)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"screenings":[]}`, rec.Body.String())
+}
+
+func TestListScreenings_ValidationError(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodGet, "/v1/compliance/screenings?limit=500", "")
+
+	ch := setupComplianceHandler(t)
+
+	err := SetValidator(ch.handler.ListScreenings)(ctx)
+	problem := newProblem(err)
+
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "limit", problem.Errors[0].Field)
+}
+
+func TestListScreenings_BindError(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodGet, "/v1/compliance/screenings?created_after=yesterday", "")
+
+	ch := setupComplianceHandler(t)
+
+	err := SetValidator(ch.handler.ListScreenings)(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, newProblem(err).Status)
+}
+
+func TestListScreenings_ServiceError(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodGet, "/v1/compliance/screenings", "")
+
+	ch := setupComplianceHandler(t)
+	ch.srv.On("ListScreenings", mock.Anything, mock.AnythingOfType("*domain.ScreeningQuery")).Return(nil, assert.AnError)
+
+	err := SetValidator(ch.handler.ListScreenings)(ctx)
+
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func newReviewContext(method, userID, action, body string) (echo.Context, *httptest.ResponseRecorder) {
+	ctx, rec := newUserJSONContext(method, "/v1/compliance/reviews/"+userID+action, body)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(userID)
+
+	return ctx, rec
+}
+
+func TestListReviews_OK(t *testing.T) {
+	ctx, rec := newUserJSONContext(http.MethodGet, "/v1/compliance/reviews?cursor=abc&limit=10", "")
+	page := &domain.UserPage{Users: []*domain.User{{ID: "2", ScreeningStatus: domain.ScreeningPendingReview}}}
+
+	ch := setupComplianceHandler(t)
+	ch.srv.On("ListReviews", mock.Anything, "abc", 10).Return(page, nil)
+
+	err := SetValidator(ch.handler.ListReviews)(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"screening_status":"pending_review"`)
+}
+
+func TestListReviews_ValidationError(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodGet, "/v1/compliance/reviews?limit=500", "")
+
+	ch := setupComplianceHandler(t)
+
+	err := SetValidator(ch.handler.ListReviews)(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, newProblem(err).Status)
+}
+
+func TestListReviews_Error(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodGet, "/v1/compliance/reviews", "")
+
+	ch := setupComplianceHandler(t)
+	ch.srv.On("ListReviews", mock.Anything, "", 0).Return(nil, assert.AnError)
+
+	err := SetValidator(ch.handler.ListReviews)(ctx)
+
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestGetReview_OK(t *testing.T) {
+	ctx, rec := newReviewContext(http.MethodGet, "2", "", "")
+	review := &domain.ReviewCase{User: &domain.User{ID: "2", ScreeningStatus: domain.ScreeningPossibleMatch}, Screenings: []*domain.ScreeningRecord{{ID: "3", UserID: "2"}}}
+
+	ch := setupComplianceHandler(t)
+	ch.srv.On("GetReview", mock.Anything, "2").Return(review, nil)
+
+	err := ch.handler.GetReview(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"screening_status":"possible_match"`)
+	assert.Contains(t, rec.Body.String(), `"screenings":[{"id":"3"`)
+}
+
+func TestGetReview_NotFound(t *testing.T) {
+	ctx, _ := newReviewContext(http.MethodGet, "2", "", "")
+
+	ch := setupComplianceHandler(t)
+	ch.srv.On("GetReview", mock.Anything, "2").Return(nil, domain.ErrNotFound)
+
+	err := ch.handler.GetReview(ctx)
+
+	assert.Equal(t, http.StatusNotFound, newProblem(err).Status)
+}
+
+func TestApproveReview_OK(t *testing.T) {
+	ctx, rec := newReviewContext(http.MethodPost, "2", "/approve", `{"comment":"Different birth date"}`)
+
+	ch := setupComplianceHandler(t)
+	ch.srv.On("ApproveReview", mock.Anything, "2", "Different birth date").Return(nil)
+
+	err := SetValidator(ch.handler.ApproveReview)(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestApproveReview_CommentRequired(t *testing.T) {
+	ctx, _ := newReviewContext(http.MethodPost, "2", "/approve", `{}`)
+
+	ch := setupComplianceHandler(t)
+
+	err := SetValidator(ch.handler.ApproveReview)(ctx)
+	problem := newProblem(err)
+
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "comment", problem.Errors[0].Field)
+}
+
+func TestApproveReview_NotUnderReview(t *testing.T) {
+	ctx, _ := newReviewContext(http.MethodPost, "2", "/approve", `{"comment":"ok"}`)
+
+	ch := setupComplianceHandler(t)
+	ch.srv.On("ApproveReview", mock.Anything, "2", "ok").Return(application.ErrNotUnderReview)
+
+	err := SetValidator(ch.handler.ApproveReview)(ctx)
+
+	assert.Equal(t, http.StatusConflict, newProblem(err).Status)
+}
+
+func TestRejectReview_OK(t *testing.T) {
+	ctx, rec := newReviewContext(http.MethodPost, "2", "/reject", `{"comment":"Same person as listed"}`)
+
+	ch := setupComplianceHandler(t)
+	ch.srv.On("RejectReview", mock.Anything, "2", "Same person as listed").Return(nil)
+
+	err := SetValidator(ch.handler.RejectReview)(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestRejectReview_Error(t *testing.T) {
+	ctx, _ := newReviewContext(http.MethodPost, "2", "/reject", `{"comment":"Same person as listed"}`)
+
+	ch := setupComplianceHandler(t)
+	ch.srv.On("RejectReview", mock.Anything, "2", "Same person as listed").Return(assert.AnError)
+
+	err := SetValidator(ch.handler.RejectReview)(ctx)
+
+	assert.ErrorIs(t, err, assert.AnError)
+}
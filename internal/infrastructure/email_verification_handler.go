@@ -0,0 +1,70 @@
+package infrastructure
+
+import (
+	"net/http"
+
+	"github.com/christhianjesus/crabi-challenge/internal/application"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type emailVerificationHandler struct {
+	srv application.EmailVerificationService
+}
+
+func NewEmailVerificationHandler(srv application.EmailVerificationService) *emailVerificationHandler {
+	return &emailVerificationHandler{srv}
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+func (h *emailVerificationHandler) Verify(c echo.Context) error {
+	ctx := c.Request().Context()
+	request := new(VerifyEmailRequest)
+
+	if err := c.Bind(request); err != nil {
+		return err
+	}
+
+	if validate, ok := c.Get(ValidatorCtxKey).(*validator.Validate); ok {
+		if err := validate.Struct(request); err != nil {
+			return err
+		}
+	}
+
+	err := h.srv.VerifyEmail(ctx, request.Token)
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *emailVerificationHandler) Resend(c echo.Context) error {
+	ctx := c.Request().Context()
+	request := new(ResendVerificationRequest)
+
+	if err := c.Bind(request); err != nil {
+		return err
+	}
+
+	if validate, ok := c.Get(ValidatorCtxKey).(*validator.Validate); ok {
+		if err := validate.Struct(request); err != nil {
+			return err
+		}
+	}
+
+	err := h.srv.ResendVerification(ctx, request.Email)
+	if err != nil {
+		return err
+	}
+
+	// same answer whether the email exists, is verified or was throttled
+	return c.NoContent(http.StatusAccepted)
+}
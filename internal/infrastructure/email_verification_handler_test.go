@@ -0,0 +1,140 @@
+package infrastructure
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/christhianjesus/crabi-challenge/internal/application"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type emailVerificationHandlerMock struct {
+	service *mocks.EmailVerificationService
+	handler *emailVerificationHandler
+}
+
+func setupEmailVerificationHandler(t *testing.T) *emailVerificationHandlerMock {
+	mockEmailVerificationService := mocks.NewEmailVerificationService(t)
+
+	return &emailVerificationHandlerMock{
+		service: mockEmailVerificationService,
+		handler: NewEmailVerificationHandler(mockEmailVerificationService),
+	}
+}
+
+func TestVerify_OK(t *testing.T) {
+	ctx, rec := newJSONContext(http.MethodPost, "/email/verify", `{"token": "token"}`)
+
+	eh := setupEmailVerificationHandler(t)
+	eh.service.On("VerifyEmail", mock.Anything, "token").Return(nil)
+
+	err := SetValidator(eh.handler.Verify)(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestVerify_BindError(t *testing.T) {
+	ctx, _ := newJSONContext(http.MethodPost, "/email/verify", `{`)
+
+	eh := setupEmailVerificationHandler(t)
+
+	err := eh.handler.Verify(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+}
+
+func TestVerify_ValidateError(t *testing.T) {
+	ctx, _ := newJSONContext(http.MethodPost, "/email/verify", `{}`)
+
+	eh := setupEmailVerificationHandler(t)
+
+	err := SetValidator(eh.handler.Verify)(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "token", problem.Errors[0].Field)
+	assert.Equal(t, "required", problem.Errors[0].Tag)
+}
+
+func TestVerify_InvalidVerificationToken(t *testing.T) {
+	ctx, _ := newJSONContext(http.MethodPost, "/email/verify", `{"token": "token"}`)
+
+	eh := setupEmailVerificationHandler(t)
+	eh.service.On("VerifyEmail", mock.Anything, "token").Return(application.ErrInvalidVerificationToken)
+
+	err := eh.handler.Verify(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, application.ErrInvalidVerificationToken.Error(), problem.Detail)
+}
+
+func TestVerify_VerifyEmailError(t *testing.T) {
+	ctx, _ := newJSONContext(http.MethodPost, "/email/verify", `{"token": "token"}`)
+
+	eh := setupEmailVerificationHandler(t)
+	eh.service.On("VerifyEmail", mock.Anything, "token").Return(assert.AnError)
+
+	err := eh.handler.Verify(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, problem.Status)
+}
+
+func TestResend_OK(t *testing.T) {
+	ctx, rec := newJSONContext(http.MethodPost, "/email/verify/resend", `{"email": "an@email.com"}`)
+
+	eh := setupEmailVerificationHandler(t)
+	eh.service.On("ResendVerification", mock.Anything, "an@email.com").Return(nil)
+
+	err := SetValidator(eh.handler.Resend)(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+}
+
+func TestResend_BindError(t *testing.T) {
+	ctx, _ := newJSONContext(http.MethodPost, "/email/verify/resend", `{`)
+
+	eh := setupEmailVerificationHandler(t)
+
+	err := eh.handler.Resend(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+}
+
+func TestResend_ValidateError(t *testing.T) {
+	ctx, _ := newJSONContext(http.MethodPost, "/email/verify/resend", `{"email": "invalid"}`)
+
+	eh := setupEmailVerificationHandler(t)
+
+	err := SetValidator(eh.handler.Resend)(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+}
+
+func TestResend_ResendVerificationError(t *testing.T) {
+	ctx, _ := newJSONContext(http.MethodPost, "/email/verify/resend", `{"email": "an@email.com"}`)
+
+	eh := setupEmailVerificationHandler(t)
+	eh.service.On("ResendVerification", mock.Anything, "an@email.com").Return(assert.AnError)
+
+	err := eh.handler.Resend(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, problem.Status)
+	assert.Equal(t, "/problems/internal", problem.Type)
+}
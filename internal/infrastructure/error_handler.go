@@ -0,0 +1,227 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/application"
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	MIMEApplicationProblemJSON = "application/problem+json"
+	// problem types are stable, clients can switch on them
+	problemTypeBase = "/problems/"
+)
+
+// RFC 7807 error body
+type Problem struct {
+	Type      string         `json:"type"`
+	Title     string         `json:"title"`
+	Status    int            `json:"status"`
+	Detail    string         `json:"detail,omitempty"`
+	Instance  string         `json:"instance,omitempty"`
+	RequestID string         `json:"request_id,omitempty"`
+	Errors    []ProblemField `json:"errors,omitempty"`
+	// reason codes of a blacklisted user, as sent by the PLD service
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+type ProblemField struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+var problemKinds = []struct {
+	kind   error
+	name   string
+	status int
+}{
+	{domain.ErrInvalidInput, "invalid-input", http.StatusBadRequest},
+	{domain.ErrInvalidCredentials, "invalid-credentials", http.StatusUnauthorized},
+	{domain.ErrForbidden, "forbidden", http.StatusForbidden},
+	{domain.ErrNotFound, "not-found", http.StatusNotFound},
+	{domain.ErrConflict, "conflict", http.StatusConflict},
+	{domain.ErrPreconditionFailed, "precondition-failed", http.StatusPreconditionFailed},
+	{domain.ErrBlacklisted, "blacklisted", http.StatusUnprocessableEntity},
+	{domain.ErrRateLimited, "rate-limited", http.StatusTooManyRequests},
+	{domain.ErrUpstreamUnavailable, "upstream-unavailable", http.StatusServiceUnavailable},
+	{domain.ErrOverloaded, "overloaded", http.StatusServiceUnavailable},
+}
+
+// Central error handler, handlers return domain errors and they are written here as problem+json
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	if retryAfter, ok := retryAfter(err); ok {
+		seconds := int64(math.Ceil(retryAfter.Seconds()))
+		c.Response().Header().Set(echo.HeaderRetryAfter, strconv.FormatInt(seconds, 10))
+	}
+
+	problem := newProblem(err)
+	if problem.Status >= http.StatusInternalServerError {
+		c.Logger().Error(err)
+	}
+
+	problem.Instance = c.Request().URL.Path
+	problem.RequestID = c.Response().Header().Get(echo.HeaderXRequestID)
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(problem.Status)
+	} else {
+		c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+		err = c.JSON(problem.Status, problem)
+	}
+
+	if err != nil {
+		c.Logger().Error(err)
+	}
+}
+
+// Helper function to find how long the caller should wait before trying again
+func retryAfter(err error) (time.Duration, bool) {
+	var locked *application.LoginLockedError
+	if errors.As(err, &locked) {
+		return locked.RetryAfter, true
+	}
+
+	var saturated *HashingSaturatedError
+	if errors.As(err, &saturated) {
+		return saturated.RetryAfter, true
+	}
+
+	var open *CircuitOpenError
+	if errors.As(err, &open) {
+		return open.RetryAfter, true
+	}
+
+	return 0, false
+}
+
+// Helper function to turn any error into a problem, without leaking internal details
+func newProblem(err error) *Problem {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		return &Problem{
+			Type:   problemTypeBase + "validation",
+			Title:  "Invalid request",
+			Status: http.StatusBadRequest,
+			Detail: "Some fields are not valid",
+			Errors: problemFields(validationErrors),
+		}
+	}
+
+	var rejected *application.PasswordRejectedError
+	if errors.As(err, &rejected) {
+		return &Problem{
+			Type:   problemTypeBase + "weak-password",
+			Title:  "Weak password",
+			Status: http.StatusBadRequest,
+			Detail: rejected.Error(),
+			Errors: passwordFields(rejected.Rejections),
+		}
+	}
+
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		return &Problem{
+			// no type of our own, the status says it all
+			Type:   "about:blank",
+			Title:  http.StatusText(he.Code),
+			Status: he.Code,
+			Detail: fmt.Sprint(he.Message),
+		}
+	}
+
+	for _, k := range problemKinds {
+		if !errors.Is(err, k.kind) {
+			continue
+		}
+
+		problem := &Problem{
+			Type:   problemTypeBase + k.name,
+			Title:  k.kind.Error(),
+			Status: k.status,
+			Detail: err.Error(),
+		}
+
+		// server side failures only show their kind
+		if k.status >= http.StatusInternalServerError {
+			problem.Detail = ""
+		}
+
+		var blacklisted *application.BlacklistedError
+		if errors.As(err, &blacklisted) {
+			problem.Reasons = blacklisted.ReasonCodes
+		}
+
+		return problem
+	}
+
+	return &Problem{
+		Type:   problemTypeBase + "internal",
+		Title:  http.StatusText(http.StatusInternalServerError),
+		Status: http.StatusInternalServerError,
+	}
+}
+
+// Field names come from the json tags, see SetValidator
+func problemFields(validationErrors validator.ValidationErrors) []ProblemField {
+	fields := make([]ProblemField, 0, len(validationErrors))
+
+	for _, fe := range validationErrors {
+		fields = append(fields, ProblemField{
+			Field:   fe.Field(),
+			Tag:     fe.Tag(),
+			Param:   fe.Param(),
+			Message: fieldMessage(fe),
+		})
+	}
+
+	return fields
+}
+
+// Every broken rule is reported, so the client can show them all at once
+func passwordFields(rejections []application.PasswordRejection) []ProblemField {
+	fields := make([]ProblemField, 0, len(rejections))
+
+	for _, rejection := range rejections {
+		fields = append(fields, ProblemField{
+			Field:   "password",
+			Tag:     string(rejection.Reason),
+			Message: rejection.Message,
+		})
+	}
+
+	return fields
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fe.Field() + " is required"
+	case "required_without":
+		return fe.Field() + " is required when " + strings.ToLower(fe.Param()) + " is missing"
+	case "email":
+		return fe.Field() + " must be a valid email"
+	case "ip":
+		return fe.Field() + " must be a valid IP address"
+	case "alpha":
+		return fe.Field() + " must only contain letters"
+	case "min":
+		return fe.Field() + " must be at least " + fe.Param() + " characters long"
+	}
+
+	return fe.Field() + " failed the " + fe.Tag() + " validation"
+}
@@ -0,0 +1,167 @@
+package infrastructure
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/application"
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPErrorHandler_DomainError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/v1/user", nil), rec)
+	ctx.Response().Header().Set(echo.HeaderXRequestID, "request-id")
+
+	HTTPErrorHandler(domain.ErrNotFound, ctx)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+	assert.JSONEq(t, `{"type":"/problems/not-found", "title":"Not found", "status":404, "detail":"Not found", "instance":"/v1/user", "request_id":"request-id"}`, rec.Body.String())
+}
+
+func TestHTTPErrorHandler_ValidationError(t *testing.T) {
+	ctx, rec := newJSONContext(http.MethodPost, "/login", `{"email": "an@email", "password": ""}`)
+
+	lg := setupAuthHandler(t)
+	err := SetValidator(lg.handler.Login)(ctx)
+	HTTPErrorHandler(err, ctx)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{
+		"type": "/problems/validation",
+		"title": "Invalid request",
+		"status": 400,
+		"detail": "Some fields are not valid",
+		"instance": "/login",
+		"errors": [
+			{"field": "email", "tag": "email", "message": "email must be a valid email"},
+			{"field": "password", "tag": "required", "message": "password is required"}
+		]
+	}`, rec.Body.String())
+}
+
+func TestHTTPErrorHandler_UnknownError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/v1/user", nil), rec)
+
+	HTTPErrorHandler(assert.AnError, ctx)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), assert.AnError.Error())
+}
+
+func TestHTTPErrorHandler_Head(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(httptest.NewRequest(http.MethodHead, "/v1/user", nil), rec)
+
+	HTTPErrorHandler(domain.ErrNotFound, ctx)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestHTTPErrorHandler_LoginLocked(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/login", nil), rec)
+
+	HTTPErrorHandler(&application.LoginLockedError{RetryAfter: 30 * time.Second}, ctx)
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "30", rec.Header().Get(echo.HeaderRetryAfter))
+}
+
+func TestHTTPErrorHandler_HashingSaturated(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/login", nil), rec)
+
+	HTTPErrorHandler(&HashingSaturatedError{RetryAfter: 1500 * time.Millisecond}, ctx)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get(echo.HeaderRetryAfter))
+	assert.Contains(t, rec.Body.String(), `"type":"/problems/overloaded"`)
+}
+
+func TestHTTPErrorHandler_CircuitOpen(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/signin", nil), rec)
+
+	HTTPErrorHandler(&CircuitOpenError{RetryAfter: 30 * time.Second}, ctx)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "30", rec.Header().Get(echo.HeaderRetryAfter))
+	assert.Contains(t, rec.Body.String(), `"type":"/problems/upstream-unavailable"`)
+}
+
+func TestHTTPErrorHandler_WeakPassword(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/signin", nil), rec)
+
+	HTTPErrorHandler(&application.PasswordRejectedError{Rejections: []application.PasswordRejection{
+		{Reason: application.PasswordTooGuessable, Message: "password is too easy to guess"},
+		{Reason: application.PasswordBreached, Message: "password appeared in a data breach"},
+	}}, ctx)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{
+		"type": "/problems/weak-password",
+		"title": "Weak password",
+		"status": 400,
+		"detail": "Password does not meet the policy",
+		"instance": "/signin",
+		"errors": [
+			{"field": "password", "tag": "too_guessable", "message": "password is too easy to guess"},
+			{"field": "password", "tag": "breached", "message": "password appeared in a data breach"}
+		]
+	}`, rec.Body.String())
+}
+
+func TestHTTPErrorHandler_Blacklisted(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/v1/users", nil), rec)
+
+	HTTPErrorHandler(&application.BlacklistedError{ReasonCodes: []string{"NAME_MATCH"}}, ctx)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.JSONEq(t, `{
+		"type": "/problems/blacklisted",
+		"title": "`+domain.ErrBlacklisted.Error()+`",
+		"status": 422,
+		"detail": "`+domain.ErrBlacklisted.Error()+`",
+		"instance": "/v1/users",
+		"reasons": ["NAME_MATCH"]
+	}`, rec.Body.String())
+}
+
+func TestNewProblem_Kinds(t *testing.T) {
+	tests := []struct {
+		err     error
+		status  int
+		kind    string
+		message string
+	}{
+		{application.ErrInvalidResetToken, http.StatusBadRequest, "/problems/invalid-input", "Invalid or expired reset token"},
+		{domain.ErrInvalidCredentials, http.StatusUnauthorized, "/problems/invalid-credentials", "Invalid credentials"},
+		{application.ErrEmailNotVerified, http.StatusForbidden, "/problems/forbidden", "Email not verified"},
+		{domain.ErrNotFound, http.StatusNotFound, "/problems/not-found", "Not found"},
+		{application.ErrMFAAlreadyEnabled, http.StatusConflict, "/problems/conflict", "MFA is already enabled"},
+		{domain.ErrBlacklisted, http.StatusUnprocessableEntity, "/problems/blacklisted", "User is in blacklist"},
+		{application.ErrLoginLocked, http.StatusTooManyRequests, "/problems/rate-limited", "Too many failed login attempts"},
+		{fmt.Errorf("%w: connection refused", domain.ErrUpstreamUnavailable), http.StatusServiceUnavailable, "/problems/upstream-unavailable", ""},
+		{echo.NewHTTPError(http.StatusBadRequest, "bad"), http.StatusBadRequest, "about:blank", "bad"},
+		{assert.AnError, http.StatusInternalServerError, "/problems/internal", ""},
+	}
+
+	for _, tt := range tests {
+		problem := newProblem(tt.err)
+
+		assert.Equal(t, tt.status, problem.Status, tt.err.Error())
+		assert.Equal(t, tt.kind, problem.Type, tt.err.Error())
+		assert.Equal(t, tt.message, problem.Detail, tt.err.Error())
+	}
+}
@@ -0,0 +1,129 @@
+package infrastructure
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+)
+
+var ErrHashingSaturated = domain.NewError(domain.ErrOverloaded, "Too many password checks in progress")
+
+// Tells the caller when to try again, matches ErrHashingSaturated
+type HashingSaturatedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *HashingSaturatedError) Error() string {
+	return ErrHashingSaturated.Error()
+}
+
+func (e *HashingSaturatedError) Unwrap() error {
+	return ErrHashingSaturated
+}
+
+type HashingStats struct {
+	Concurrency int   `json:"concurrency"`
+	QueueDepth  int   `json:"queue_depth"`
+	Running     int64 `json:"running"`
+	Queued      int64 `json:"queued"`
+	Completed   int64 `json:"completed"`
+	// turned away because the queue was full
+	Rejected int64 `json:"rejected"`
+	// gave up waiting because their context ended
+	Expired int64 `json:"expired"`
+}
+
+// A password hasher that runs at most concurrency hashes at a time,
+// keeps up to queueDepth more waiting and turns the rest away
+type HashingPool interface {
+	domain.PasswordHasher
+	Stats() HashingStats
+}
+
+type hashingPool struct {
+	hasher     domain.PasswordHasher
+	slots      chan struct{}
+	tickets    chan struct{}
+	retryAfter time.Duration
+	queued     atomic.Int64
+	running    atomic.Int64
+	completed  atomic.Int64
+	rejected   atomic.Int64
+	expired    atomic.Int64
+}
+
+func NewHashingPool(hasher domain.PasswordHasher, concurrency, queueDepth int, retryAfter time.Duration) HashingPool {
+	return &hashingPool{
+		hasher:     hasher,
+		slots:      make(chan struct{}, concurrency),
+		tickets:    make(chan struct{}, concurrency+queueDepth),
+		retryAfter: retryAfter,
+	}
+}
+
+func (p *hashingPool) Hash(ctx context.Context, password string) (string, error) {
+	var hash string
+	var err error
+
+	if runErr := p.run(ctx, func() { hash, err = p.hasher.Hash(ctx, password) }); runErr != nil {
+		return "", runErr
+	}
+
+	return hash, err
+}
+
+func (p *hashingPool) Verify(ctx context.Context, hash, password string) (bool, bool, error) {
+	var match, rehash bool
+	var err error
+
+	if runErr := p.run(ctx, func() { match, rehash, err = p.hasher.Verify(ctx, hash, password) }); runErr != nil {
+		return false, false, runErr
+	}
+
+	return match, rehash, err
+}
+
+func (p *hashingPool) Stats() HashingStats {
+	return HashingStats{
+		Concurrency: cap(p.slots),
+		QueueDepth:  cap(p.tickets) - cap(p.slots),
+		Running:     p.running.Load(),
+		Queued:      p.queued.Load(),
+		Completed:   p.completed.Load(),
+		Rejected:    p.rejected.Load(),
+		Expired:     p.expired.Load(),
+	}
+}
+
+// Helper function to run the hash once a slot is free, as long as the caller still waits for it
+func (p *hashingPool) run(ctx context.Context, hash func()) error {
+	// every running or waiting hash holds a ticket
+	select {
+	case p.tickets <- struct{}{}:
+	default:
+		p.rejected.Add(1)
+		return &HashingSaturatedError{p.retryAfter}
+	}
+	defer func() { <-p.tickets }()
+
+	p.queued.Add(1)
+	select {
+	case p.slots <- struct{}{}:
+		p.queued.Add(-1)
+	case <-ctx.Done():
+		p.queued.Add(-1)
+		p.expired.Add(1)
+		return &HashingSaturatedError{p.retryAfter}
+	}
+	defer func() { <-p.slots }()
+
+	p.running.Add(1)
+	defer p.running.Add(-1)
+
+	hash()
+	p.completed.Add(1)
+
+	return nil
+}
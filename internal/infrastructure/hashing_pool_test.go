@@ -0,0 +1,95 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Helper function to keep the only slot of the pool busy until release is closed
+func blockHashingPool(t *testing.T, hasher *mocks.PasswordHasher, pool HashingPool) chan struct{} {
+	started, release := make(chan struct{}), make(chan struct{})
+	hasher.On("Hash", mock.Anything, "slow").Run(func(mock.Arguments) {
+		close(started)
+		<-release
+	}).Return("hash", nil).Once()
+
+	go pool.Hash(context.Background(), "slow")
+	<-started
+
+	return release
+}
+
+func TestHashingPool_OK(t *testing.T) {
+	hasher := mocks.NewPasswordHasher(t)
+	hasher.On("Hash", mock.Anything, "password").Return("hash", nil)
+	hasher.On("Verify", mock.Anything, "hash", "password").Return(true, false, nil)
+	pool := NewHashingPool(hasher, 2, 2, time.Second)
+
+	hash, err := pool.Hash(context.Background(), "password")
+	assert.NoError(t, err)
+	assert.Equal(t, "hash", hash)
+
+	match, rehash, err := pool.Verify(context.Background(), "hash", "password")
+	assert.NoError(t, err)
+	assert.True(t, match)
+	assert.False(t, rehash)
+
+	assert.Equal(t, HashingStats{Concurrency: 2, QueueDepth: 2, Completed: 2}, pool.Stats())
+}
+
+func TestHashingPool_HasherError(t *testing.T) {
+	hasher := mocks.NewPasswordHasher(t)
+	hasher.On("Verify", mock.Anything, "hash", "password").Return(false, false, assert.AnError)
+	pool := NewHashingPool(hasher, 1, 1, time.Second)
+
+	_, _, err := pool.Verify(context.Background(), "hash", "password")
+
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestHashingPool_QueueFull(t *testing.T) {
+	hasher := mocks.NewPasswordHasher(t)
+	hasher.On("Hash", mock.Anything, "queued").Return("hash", nil).Once()
+	pool := NewHashingPool(hasher, 1, 1, 3*time.Second)
+	release := blockHashingPool(t, hasher, pool)
+
+	queued := make(chan error)
+	go func() {
+		_, err := pool.Hash(context.Background(), "queued")
+		queued <- err
+	}()
+	assert.Eventually(t, func() bool { return pool.Stats().Queued == 1 }, time.Second, time.Millisecond)
+
+	_, err := pool.Hash(context.Background(), "rejected")
+
+	assert.ErrorIs(t, err, ErrHashingSaturated)
+	assert.ErrorIs(t, err, domain.ErrOverloaded)
+	assert.Equal(t, &HashingSaturatedError{RetryAfter: 3 * time.Second}, err)
+	assert.Equal(t, HashingStats{Concurrency: 1, QueueDepth: 1, Running: 1, Queued: 1, Rejected: 1}, pool.Stats())
+
+	// the waiting hash runs once the slot is free
+	close(release)
+	assert.NoError(t, <-queued)
+}
+
+func TestHashingPool_ContextDone(t *testing.T) {
+	hasher := mocks.NewPasswordHasher(t)
+	pool := NewHashingPool(hasher, 1, 1, time.Second)
+	release := blockHashingPool(t, hasher, pool)
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, _, err := pool.Verify(ctx, "hash", "password")
+
+	assert.ErrorIs(t, err, ErrHashingSaturated)
+	assert.Equal(t, int64(1), pool.Stats().Expired)
+	assert.Equal(t, int64(0), pool.Stats().Queued)
+}
@@ -0,0 +1,21 @@
+package infrastructure
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type jwksHandler struct {
+	keys *JWKSet
+}
+
+func NewJWKSHandler(keys *JWTKeySet) *jwksHandler {
+	return &jwksHandler{keys.JWKS()}
+}
+
+func (h *jwksHandler) Get(c echo.Context) error {
+	c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=300")
+
+	return c.JSON(http.StatusOK, h.keys)
+}
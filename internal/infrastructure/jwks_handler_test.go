@@ -0,0 +1,39 @@
+package infrastructure
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWKSHandler_Get(t *testing.T) {
+	_, private, _ := ed25519.GenerateKey(rand.Reader)
+	privateFile, _ := writeKeyPair(t, private)
+	keys, _ := LoadJWTKeySet(privateFile, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(req, rec)
+
+	err := NewJWKSHandler(keys).Get(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"keys":[{"kty":"OKP","kid":"`+keys.Signing.ID+`","use":"sig","alg":"EdDSA","crv":"Ed25519","x":"`+keys.JWKS().Keys[0].X+`"}]}`, rec.Body.String())
+}
+
+func TestJWKSHandler_GetEmpty(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(req, rec)
+
+	err := NewJWKSHandler(NewHMACKeySet([]byte("secret"))).Get(ctx)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"keys":[]}`, rec.Body.String())
+}
@@ -0,0 +1,268 @@
+package infrastructure
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type JWTKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	// private key, only needed to sign
+	Private crypto.PrivateKey
+	// public key or shared secret for HMAC
+	Public interface{}
+}
+
+type JWTKeySet struct {
+	Signing      *JWTKey
+	Verification []*JWTKey
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []*JWK `json:"keys"`
+}
+
+// Keeps the legacy shared secret behaviour, nothing is published in the JWKS
+func NewHMACKeySet(secret []byte) *JWTKeySet {
+	key := &JWTKey{Method: jwt.SigningMethodHS256, Private: secret, Public: secret}
+
+	return &JWTKeySet{Signing: key, Verification: []*JWTKey{key}}
+}
+
+// Loads the PEM private key used to sign and the PEM public keys still accepted,
+// the signing public key is always accepted
+func LoadJWTKeySet(signingKeyFile string, verificationKeyFiles []string) (*JWTKeySet, error) {
+	signing, err := loadPrivateKey(signingKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := &JWTKeySet{Signing: signing, Verification: []*JWTKey{signing}}
+
+	for _, file := range verificationKeyFiles {
+		key, err := loadPublicKey(file)
+		if err != nil {
+			return nil, err
+		}
+
+		if key.ID != signing.ID {
+			keys.Verification = append(keys.Verification, key)
+		}
+	}
+
+	return keys, nil
+}
+
+func (s *JWTKeySet) JWKS() *JWKSet {
+	set := &JWKSet{Keys: []*JWK{}}
+
+	for _, key := range s.Verification {
+		if jwk := newJWK(key); jwk != nil {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+
+	return set
+}
+
+func loadPrivateKey(file string) (*JWTKey, error) {
+	block, err := readPEM(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var private crypto.PrivateKey
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		private, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := private.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("Unsupported private key")
+	}
+
+	key, err := newJWTKey(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	key.Private = private
+
+	return key, nil
+}
+
+func loadPublicKey(file string) (*JWTKey, error) {
+	block, err := readPEM(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var public interface{}
+
+	switch block.Type {
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			public = cert.PublicKey
+		}
+	case "RSA PUBLIC KEY":
+		public, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		public, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newJWTKey(public)
+}
+
+func readPEM(file string) (*pem.Block, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("Invalid PEM file " + file)
+	}
+
+	return block, nil
+}
+
+func newJWTKey(public interface{}) (*JWTKey, error) {
+	key := &JWTKey{Public: public}
+
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		key.Method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.Method = jwt.SigningMethodEdDSA
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			key.Method = jwt.SigningMethodES256
+		case elliptic.P384():
+			key.Method = jwt.SigningMethodES384
+		case elliptic.P521():
+			key.Method = jwt.SigningMethodES512
+		default:
+			return nil, errors.New("Unsupported elliptic curve")
+		}
+	default:
+		return nil, errors.New("Unsupported public key")
+	}
+
+	jwk := newJWK(key)
+	if jwk == nil {
+		return nil, errors.New("Unsupported public key")
+	}
+
+	key.ID = jwk.Thumbprint()
+
+	return key, nil
+}
+
+func newJWK(key *JWTKey) *JWK {
+	jwk := &JWK{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+
+	switch pub := key.Public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	case *ecdsa.PublicKey:
+		ecdhKey, err := pub.ECDH()
+		if err != nil {
+			return nil
+		}
+
+		// uncompressed point: 0x04 || X || Y
+		point := ecdhKey.Bytes()[1:]
+		size := len(point) / 2
+
+		jwk.Kty = "EC"
+		jwk.Crv = pub.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(point[:size])
+		jwk.Y = base64.RawURLEncoding.EncodeToString(point[size:])
+	default:
+		// shared secrets are never published
+		return nil
+	}
+
+	return jwk
+}
+
+// RFC 7638 thumbprint, used as kid so the same key always gets the same id
+func (k *JWK) Thumbprint() string {
+	var members interface{}
+
+	switch k.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{k.E, k.Kty, k.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{k.Crv, k.Kty, k.X, k.Y}
+	default:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{k.Crv, k.Kty, k.X}
+	}
+
+	data, _ := json.Marshal(members)
+	sum := sha256.Sum256(data)
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,139 @@
+package infrastructure
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// Helper function to write a PEM key pair, returns the private and public key paths
+func writeKeyPair(t *testing.T, private crypto.Signer) (string, string) {
+	dir := t.TempDir()
+
+	privateBytes, _ := x509.MarshalPKCS8PrivateKey(private)
+	publicBytes, _ := x509.MarshalPKIXPublicKey(private.Public())
+
+	privateFile := filepath.Join(dir, "private.pem")
+	publicFile := filepath.Join(dir, "public.pem")
+
+	_ = os.WriteFile(privateFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateBytes}), 0600)
+	_ = os.WriteFile(publicFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicBytes}), 0600)
+
+	return privateFile, publicFile
+}
+
+func TestLoadJWTKeySet_RSA(t *testing.T) {
+	private, _ := rsa.GenerateKey(rand.Reader, 2048)
+	privateFile, _ := writeKeyPair(t, private)
+
+	keys, err := LoadJWTKeySet(privateFile, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, jwt.SigningMethodRS256, keys.Signing.Method)
+	assert.NotEmpty(t, keys.Signing.ID)
+	assert.Len(t, keys.Verification, 1)
+}
+
+func TestLoadJWTKeySet_ECDSA(t *testing.T) {
+	private, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	privateFile, _ := writeKeyPair(t, private)
+
+	keys, err := LoadJWTKeySet(privateFile, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, jwt.SigningMethodES256, keys.Signing.Method)
+}
+
+func TestLoadJWTKeySet_EdDSA(t *testing.T) {
+	_, private, _ := ed25519.GenerateKey(rand.Reader)
+	privateFile, _ := writeKeyPair(t, private)
+
+	keys, err := LoadJWTKeySet(privateFile, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, jwt.SigningMethodEdDSA, keys.Signing.Method)
+}
+
+func TestLoadJWTKeySet_VerificationKeys(t *testing.T) {
+	current, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	previous, _ := rsa.GenerateKey(rand.Reader, 2048)
+	privateFile, publicFile := writeKeyPair(t, current)
+	_, previousFile := writeKeyPair(t, previous)
+
+	keys, err := LoadJWTKeySet(privateFile, []string{publicFile, previousFile})
+
+	assert.NoError(t, err)
+	assert.Len(t, keys.Verification, 2)
+	assert.Equal(t, keys.Signing.ID, keys.Verification[0].ID)
+	assert.Equal(t, jwt.SigningMethodRS256, keys.Verification[1].Method)
+}
+
+func TestLoadJWTKeySet_MissingFile(t *testing.T) {
+	keys, err := LoadJWTKeySet(filepath.Join(t.TempDir(), "missing.pem"), nil)
+
+	assert.Error(t, err)
+	assert.Nil(t, keys)
+}
+
+func TestLoadJWTKeySet_InvalidPEM(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "invalid.pem")
+	_ = os.WriteFile(file, []byte("invalid"), 0600)
+
+	keys, err := LoadJWTKeySet(file, nil)
+
+	assert.Error(t, err)
+	assert.Nil(t, keys)
+}
+
+func TestLoadJWTKeySet_InvalidVerificationKey(t *testing.T) {
+	private, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	privateFile, _ := writeKeyPair(t, private)
+
+	keys, err := LoadJWTKeySet(privateFile, []string{privateFile})
+
+	assert.Error(t, err)
+	assert.Nil(t, keys)
+}
+
+func TestJWKS_HMACNotPublished(t *testing.T) {
+	jwks := NewHMACKeySet([]byte("secret")).JWKS()
+
+	assert.Empty(t, jwks.Keys)
+}
+
+func TestJWKS_PublicKeys(t *testing.T) {
+	private, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	privateFile, _ := writeKeyPair(t, private)
+	keys, _ := LoadJWTKeySet(privateFile, nil)
+
+	jwks := keys.JWKS()
+
+	assert.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "EC", jwks.Keys[0].Kty)
+	assert.Equal(t, "P-256", jwks.Keys[0].Crv)
+	assert.Equal(t, "ES256", jwks.Keys[0].Alg)
+	assert.Equal(t, keys.Signing.ID, jwks.Keys[0].Kid)
+	assert.Equal(t, jwks.Keys[0].Kid, jwks.Keys[0].Thumbprint())
+}
+
+func TestThumbprint_RFC7638(t *testing.T) {
+	// example from RFC 7638 section 3.1
+	jwk := &JWK{
+		Kty: "RSA",
+		E:   "AQAB",
+		N:   "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+	}
+
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", jwk.Thumbprint())
+}
@@ -0,0 +1,130 @@
+package infrastructure
+
+import (
+	"errors"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+type JWTManager interface {
+	Sign(token *domain.AccessToken) (string, error)
+	ParseToken(c echo.Context, auth string) (interface{}, error)
+}
+
+type jwtManager struct {
+	signing      *JWTKey
+	verification map[string]*JWTKey
+	issuer       string
+	audience     string
+	parser       *jwt.Parser
+}
+
+type AccessClaims struct {
+	jwt.RegisteredClaims
+	Restricted bool              `json:"restricted,omitempty"`
+	Roles      []domain.Role     `json:"roles,omitempty"`
+	AuthMethod domain.AuthMethod `json:"auth_method,omitempty"`
+	SessionID  string            `json:"sid,omitempty"`
+	Generation int64             `json:"gen,omitempty"`
+}
+
+func (c *AccessClaims) AccessToken() *domain.AccessToken {
+	token := &domain.AccessToken{
+		ID:         c.ID,
+		UserID:     c.Subject,
+		Restricted: c.Restricted,
+		Roles:      c.Roles,
+		AuthMethod: c.AuthMethod,
+		SessionID:  c.SessionID,
+		Generation: c.Generation,
+	}
+
+	if c.IssuedAt != nil {
+		token.IssuedAt = c.IssuedAt.Time
+	}
+
+	if c.ExpiresAt != nil {
+		token.ExpiresAt = c.ExpiresAt.Time
+	}
+
+	return token
+}
+
+func NewJWTManager(keys *JWTKeySet, issuer, audience string) JWTManager {
+	verification := make(map[string]*JWTKey, len(keys.Verification))
+	methods := make([]string, 0, len(keys.Verification))
+
+	for _, key := range keys.Verification {
+		verification[key.ID] = key
+		methods = append(methods, key.Method.Alg())
+	}
+
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(methods),
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+
+	return &jwtManager{keys.Signing, verification, issuer, audience, parser}
+}
+
+func (m *jwtManager) Sign(token *domain.AccessToken) (string, error) {
+	claims := &AccessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        token.ID,
+			Subject:   token.UserID,
+			Issuer:    m.issuer,
+			Audience:  jwt.ClaimStrings{m.audience},
+			IssuedAt:  jwt.NewNumericDate(token.IssuedAt),
+			NotBefore: jwt.NewNumericDate(token.IssuedAt),
+			ExpiresAt: jwt.NewNumericDate(token.ExpiresAt),
+		},
+		Restricted: token.Restricted,
+		Roles:      token.Roles,
+		AuthMethod: token.AuthMethod,
+		SessionID:  token.SessionID,
+		Generation: token.Generation,
+	}
+
+	jwtToken := jwt.NewWithClaims(m.signing.Method, claims)
+	if m.signing.ID != "" {
+		jwtToken.Header["kid"] = m.signing.ID
+	}
+
+	return jwtToken.SignedString(m.signing.Private)
+}
+
+// ParseToken matches echojwt.Config.ParseTokenFunc
+func (m *jwtManager) ParseToken(c echo.Context, auth string) (interface{}, error) {
+	token, err := m.parser.ParseWithClaims(auth, new(AccessClaims), m.keyFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.Claims.(*AccessClaims).Subject == "" {
+		return nil, errors.New("Missing subject claim")
+	}
+
+	return token, nil
+}
+
+func (m *jwtManager) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := m.verification[kid]
+	if !ok {
+		return nil, errors.New("Unknown signing key")
+	}
+
+	// a key is only valid for its own algorithm, this stops tokens signed
+	// with HS256 using a public key as secret
+	if key.Method.Alg() != token.Method.Alg() {
+		return nil, errors.New("Unexpected signing method")
+	}
+
+	return key.Public, nil
+}
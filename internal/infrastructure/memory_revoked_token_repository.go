@@ -0,0 +1,96 @@
+package infrastructure
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+)
+
+type memoryRevokedTokenRepository struct {
+	mu     sync.Mutex
+	tokens map[string]time.Time
+	// token generation of each user, kept for good
+	users    map[string]int64
+	sessions map[string]time.Time
+}
+
+func NewMemoryRevokedTokenRepository() domain.RevokedTokenRepository {
+	return &memoryRevokedTokenRepository{
+		tokens:   make(map[string]time.Time),
+		users:    make(map[string]int64),
+		sessions: make(map[string]time.Time),
+	}
+}
+
+func (r *memoryRevokedTokenRepository) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prune()
+	r.tokens[tokenID] = expiresAt
+
+	return nil
+}
+
+func (r *memoryRevokedTokenRepository) RevokeUserTokens(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prune()
+	r.users[userID]++
+
+	return nil
+}
+
+func (r *memoryRevokedTokenRepository) GetTokenGeneration(ctx context.Context, userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.users[userID], nil
+}
+
+func (r *memoryRevokedTokenRepository) RevokeSession(ctx context.Context, sessionID string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prune()
+	r.sessions[sessionID] = expiresAt
+
+	return nil
+}
+
+func (r *memoryRevokedTokenRepository) IsTokenRevoked(ctx context.Context, token *domain.AccessToken) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+
+	if expiresAt, ok := r.tokens[token.ID]; ok && now.Before(expiresAt) {
+		return true, nil
+	}
+
+	if expiresAt, ok := r.sessions[token.SessionID]; ok && now.Before(expiresAt) {
+		return true, nil
+	}
+
+	return token.Generation < r.users[token.UserID], nil
+}
+
+// drops entries whose tokens would be rejected as expired anyway
+func (r *memoryRevokedTokenRepository) prune() {
+	now := time.Now()
+
+	for tokenID, expiresAt := range r.tokens {
+		if !now.Before(expiresAt) {
+			delete(r.tokens, tokenID)
+		}
+	}
+
+	for sessionID, expiresAt := range r.sessions {
+		if !now.Before(expiresAt) {
+			delete(r.sessions, sessionID)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryRevokedTokenRepository_Revoked(t *testing.T) {
+	repo := NewMemoryRevokedTokenRepository()
+
+	err := repo.RevokeToken(context.TODO(), "jti", time.Now().Add(time.Minute))
+	revoked, _ := repo.IsTokenRevoked(context.TODO(), &domain.AccessToken{ID: "jti"})
+
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestMemoryRevokedTokenRepository_NotRevoked(t *testing.T) {
+	repo := NewMemoryRevokedTokenRepository()
+
+	revoked, err := repo.IsTokenRevoked(context.TODO(), &domain.AccessToken{ID: "jti"})
+
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestMemoryRevokedTokenRepository_UserTokens(t *testing.T) {
+	now := time.Now()
+	repo := NewMemoryRevokedTokenRepository()
+
+	// issued, revoked and issued again within the same second
+	oldGeneration, _ := repo.GetTokenGeneration(context.TODO(), "1")
+	err := repo.RevokeUserTokens(context.TODO(), "1")
+	newGeneration, _ := repo.GetTokenGeneration(context.TODO(), "1")
+
+	before, _ := repo.IsTokenRevoked(context.TODO(), &domain.AccessToken{ID: "old", UserID: "1", IssuedAt: now, Generation: oldGeneration})
+	after, _ := repo.IsTokenRevoked(context.TODO(), &domain.AccessToken{ID: "new", UserID: "1", IssuedAt: now, Generation: newGeneration})
+	other, _ := repo.IsTokenRevoked(context.TODO(), &domain.AccessToken{ID: "other", UserID: "2", IssuedAt: now})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), newGeneration)
+	assert.True(t, before)
+	assert.False(t, after)
+	assert.False(t, other)
+}
+
+func TestMemoryRevokedTokenRepository_Session(t *testing.T) {
+	repo := NewMemoryRevokedTokenRepository()
+
+	err := repo.RevokeSession(context.TODO(), "family", time.Now().Add(time.Minute))
+	revoked, _ := repo.IsTokenRevoked(context.TODO(), &domain.AccessToken{ID: "jti", UserID: "1", SessionID: "family"})
+	other, _ := repo.IsTokenRevoked(context.TODO(), &domain.AccessToken{ID: "jti", UserID: "1", SessionID: "other"})
+
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+	assert.False(t, other)
+}
+
+func TestMemoryRevokedTokenRepository_PrunesExpired(t *testing.T) {
+	repo := NewMemoryRevokedTokenRepository()
+
+	_ = repo.RevokeToken(context.TODO(), "expired", time.Now().Add(-time.Minute))
+	_ = repo.RevokeSession(context.TODO(), "family", time.Now().Add(-time.Minute))
+	_ = repo.RevokeToken(context.TODO(), "jti", time.Now().Add(time.Minute))
+	revoked, _ := repo.IsTokenRevoked(context.TODO(), &domain.AccessToken{ID: "expired"})
+
+	assert.False(t, revoked)
+	assert.Len(t, repo.(*memoryRevokedTokenRepository).tokens, 1)
+	assert.Empty(t, repo.(*memoryRevokedTokenRepository).sessions)
+}
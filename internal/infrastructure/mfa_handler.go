@@ -0,0 +1,103 @@
+package infrastructure
+
+import (
+	"net/http"
+
+	"github.com/christhianjesus/crabi-challenge/internal/application"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type mfaHandler struct {
+	srv application.MFAService
+}
+
+func NewMFAHandler(srv application.MFAService) *mfaHandler {
+	return &mfaHandler{srv}
+}
+
+type TOTPEnrollmentResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+func (h *mfaHandler) Enroll(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	principal, err := principalFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	enrollment, err := h.srv.EnrollTOTP(ctx, principal.UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &TOTPEnrollmentResponse{
+		Secret: enrollment.Secret,
+		URI:    enrollment.URI,
+	})
+}
+
+func (h *mfaHandler) Confirm(c echo.Context) error {
+	ctx := c.Request().Context()
+	request := new(MFACodeRequest)
+
+	principal, err := principalFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Bind(request); err != nil {
+		return err
+	}
+
+	if validate, ok := c.Get(ValidatorCtxKey).(*validator.Validate); ok {
+		if err := validate.Struct(request); err != nil {
+			return err
+		}
+	}
+
+	codes, err := h.srv.ConfirmTOTP(ctx, principal.UserID, request.Code)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (h *mfaHandler) Disable(c echo.Context) error {
+	ctx := c.Request().Context()
+	request := new(MFACodeRequest)
+
+	principal, err := principalFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Bind(request); err != nil {
+		return err
+	}
+
+	if validate, ok := c.Get(ValidatorCtxKey).(*validator.Validate); ok {
+		if err := validate.Struct(request); err != nil {
+			return err
+		}
+	}
+
+	err = h.srv.DisableTOTP(ctx, principal.UserID, request.Code)
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,192 @@
+package infrastructure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/christhianjesus/crabi-challenge/internal/application"
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mfaHandlerMock struct {
+	service *mocks.MFAService
+	handler *mfaHandler
+}
+
+func setupMFAHandler(t *testing.T) *mfaHandlerMock {
+	mockMFAService := mocks.NewMFAService(t)
+
+	return &mfaHandlerMock{
+		service: mockMFAService,
+		handler: NewMFAHandler(mockMFAService),
+	}
+}
+
+// Helper function to build a request context for an authenticated user
+func newUserJSONContext(method, path, body string) (echo.Context, *httptest.ResponseRecorder) {
+	ctx, rec := newJSONContext(method, path, body)
+	req := ctx.Request()
+	ctx.SetRequest(req.WithContext(domain.WithPrincipal(req.Context(), &domain.Principal{UserID: "1", Roles: []domain.Role{domain.RoleUser}})))
+
+	return ctx, rec
+}
+
+func TestEnroll_OK(t *testing.T) {
+	ctx, rec := newUserJSONContext(http.MethodPost, "/v1/mfa/totp", "")
+
+	mh := setupMFAHandler(t)
+	mh.service.On("EnrollTOTP", mock.Anything, "1").Return(&domain.TOTPEnrollment{Secret: "SECRET", URI: "otpauth://totp/x"}, nil)
+
+	err := mh.handler.Enroll(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"secret":"SECRET", "otpauth_uri":"otpauth://totp/x"}`, rec.Body.String())
+}
+
+func TestEnroll_AlreadyEnabled(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodPost, "/v1/mfa/totp", "")
+
+	mh := setupMFAHandler(t)
+	mh.service.On("EnrollTOTP", mock.Anything, "1").Return(nil, application.ErrMFAAlreadyEnabled)
+
+	err := mh.handler.Enroll(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusConflict, problem.Status)
+}
+
+func TestEnroll_EnrollTOTPError(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodPost, "/v1/mfa/totp", "")
+
+	mh := setupMFAHandler(t)
+	mh.service.On("EnrollTOTP", mock.Anything, "1").Return(nil, assert.AnError)
+
+	err := mh.handler.Enroll(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, problem.Status)
+}
+
+func TestConfirm_OK(t *testing.T) {
+	ctx, rec := newUserJSONContext(http.MethodPost, "/v1/mfa/totp/confirm", `{"code": "123456"}`)
+
+	mh := setupMFAHandler(t)
+	mh.service.On("ConfirmTOTP", mock.Anything, "1", "123456").Return([]string{"ABCD-EFGH"}, nil)
+
+	err := SetValidator(mh.handler.Confirm)(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"recovery_codes":["ABCD-EFGH"]}`, rec.Body.String())
+}
+
+func TestConfirm_BindError(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodPost, "/v1/mfa/totp/confirm", `{`)
+
+	mh := setupMFAHandler(t)
+
+	err := mh.handler.Confirm(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+}
+
+func TestConfirm_ValidateError(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodPost, "/v1/mfa/totp/confirm", `{}`)
+
+	mh := setupMFAHandler(t)
+
+	err := SetValidator(mh.handler.Confirm)(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "code", problem.Errors[0].Field)
+	assert.Equal(t, "required", problem.Errors[0].Tag)
+}
+
+func TestConfirm_InvalidCode(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodPost, "/v1/mfa/totp/confirm", `{"code": "123456"}`)
+
+	mh := setupMFAHandler(t)
+	mh.service.On("ConfirmTOTP", mock.Anything, "1", "123456").Return(nil, application.ErrInvalidMFACode)
+
+	err := mh.handler.Confirm(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, application.ErrInvalidMFACode.Error(), problem.Detail)
+}
+
+func TestConfirm_NotEnrolled(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodPost, "/v1/mfa/totp/confirm", `{"code": "123456"}`)
+
+	mh := setupMFAHandler(t)
+	mh.service.On("ConfirmTOTP", mock.Anything, "1", "123456").Return(nil, application.ErrMFANotEnrolled)
+
+	err := mh.handler.Confirm(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusConflict, problem.Status)
+}
+
+func TestDisable_OK(t *testing.T) {
+	ctx, rec := newUserJSONContext(http.MethodDelete, "/v1/mfa/totp", `{"code": "ABCD-EFGH"}`)
+
+	mh := setupMFAHandler(t)
+	mh.service.On("DisableTOTP", mock.Anything, "1", "ABCD-EFGH").Return(nil)
+
+	err := SetValidator(mh.handler.Disable)(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestDisable_BindError(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodDelete, "/v1/mfa/totp", `{`)
+
+	mh := setupMFAHandler(t)
+
+	err := mh.handler.Disable(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+}
+
+func TestDisable_ValidateError(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodDelete, "/v1/mfa/totp", `{}`)
+
+	mh := setupMFAHandler(t)
+
+	err := SetValidator(mh.handler.Disable)(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+}
+
+func TestDisable_DisableTOTPError(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodDelete, "/v1/mfa/totp", `{"code": "123456"}`)
+
+	mh := setupMFAHandler(t)
+	mh.service.On("DisableTOTP", mock.Anything, "1", "123456").Return(assert.AnError)
+
+	err := mh.handler.Disable(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, problem.Status)
+	assert.Equal(t, "/problems/internal", problem.Type)
+}
@@ -1,9 +1,13 @@
 package infrastructure
 
 import (
+	"net/http"
 	"reflect"
+	"slices"
 	"strings"
 
+	"github.com/christhianjesus/crabi-challenge/internal/application"
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
 	"github.com/go-playground/validator/v10"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
@@ -11,19 +15,161 @@ import (
 
 const ValidatorCtxKey = "validator"
 
-// Helper function to set user_id context variable
-func SetUserID(c echo.Context) {
-	// by default token is stored under `user` key
-	claims := c.Get("user").(*jwt.Token).Claims.(jwt.MapClaims)
-	c.Set("user_id", claims["user_id"])
+// Helper function to read the access token validated by the jwt middleware
+func accessTokenFromContext(c echo.Context) (*domain.AccessToken, bool) {
+	token, ok := c.Get("user").(*jwt.Token)
+	if !ok {
+		return nil, false
+	}
+
+	claims, ok := token.Claims.(*AccessClaims)
+	if !ok {
+		return nil, false
+	}
+
+	return claims.AccessToken(), true
+}
+
+// Must run after the jwt middleware, makes the caller available to the services
+// through the request context
+func SetPrincipal(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token, ok := accessTokenFromContext(c)
+		if !ok || token.UserID == "" {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Invalid access token")
+		}
+
+		// tokens issued before auth methods were tracked
+		method := token.AuthMethod
+		if method == "" {
+			method = domain.PasswordAuth
+		}
+
+		principal := &domain.Principal{
+			UserID:     token.UserID,
+			Roles:      token.Roles,
+			TokenID:    token.ID,
+			SessionID:  token.SessionID,
+			AuthMethod: method,
+			MFALevel:   method.MFALevel(),
+		}
+
+		req := c.Request()
+		c.SetRequest(req.WithContext(domain.WithPrincipal(req.Context(), principal)))
+
+		return next(c)
+	}
+}
+
+// Helper function to read the caller set by SetPrincipal
+func principalFromContext(c echo.Context) (*domain.Principal, error) {
+	principal, ok := domain.PrincipalFromContext(c.Request().Context())
+	if !ok {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "Missing access token")
+	}
+
+	return principal, nil
+}
+
+// Must run after SetPrincipal, only lets in callers with one of the roles
+func RequireRole(roles ...domain.Role) echo.MiddlewareFunc {
+	return requireAccess(func(principal *domain.Principal) bool {
+		return slices.ContainsFunc(principal.Roles, func(role domain.Role) bool {
+			return slices.Contains(roles, role)
+		})
+	})
+}
+
+// Must run after SetPrincipal, only lets in callers with a role granting the permission
+func RequirePermission(permission domain.Permission) echo.MiddlewareFunc {
+	return requireAccess(func(principal *domain.Principal) bool {
+		return principal.HasPermission(permission)
+	})
+}
+
+func requireAccess(allowed func(principal *domain.Principal) bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal, err := principalFromContext(c)
+			if err != nil {
+				return err
+			}
+
+			if !allowed(principal) {
+				return echo.NewHTTPError(http.StatusForbidden, "Insufficient permissions")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// Must run after the jwt middleware
+func RejectRevokedTokens(srv application.TokenService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, ok := accessTokenFromContext(c)
+			if !ok {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Missing access token")
+			}
+
+			revoked, err := srv.IsRevoked(c.Request().Context(), token)
+			if err != nil {
+				return err
+			}
+
+			if revoked {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Token has been revoked")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// Must run after the jwt middleware, restricted tokens only reach the given "METHOD /path" routes
+func AllowRestrictedTokens(routes ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, ok := accessTokenFromContext(c)
+			if !ok {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Missing access token")
+			}
+
+			if token.Restricted && !slices.Contains(routes, c.Request().Method+" "+c.Path()) {
+				return echo.NewHTTPError(http.StatusForbidden, "Email not verified")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// Makes the client IP and user agent available to the services through the request context
+func SetClientInfo(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		ctx := domain.WithClientIP(req.Context(), c.RealIP())
+		c.SetRequest(req.WithContext(domain.WithUserAgent(ctx, req.UserAgent())))
+
+		return next(c)
+	}
 }
 
 func SetValidator(next echo.HandlerFunc) echo.HandlerFunc {
 	validate := validator.New()
 
-	// register function to get tag name from json tags.
+	// register function to get tag name from json tags, or query and path param tags.
 	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
-		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		var tag string
+		for _, key := range []string{"json", "query", "param"} {
+			if value, ok := fld.Tag.Lookup(key); ok {
+				tag = value
+				break
+			}
+		}
+
+		name := strings.SplitN(tag, ",", 2)[0]
 		if name == "-" {
 			return ""
 		}
@@ -0,0 +1,46 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type mongoAuditRepository struct {
+	coll mongoCollection
+}
+
+type mongoAuditEvent struct {
+	ID        bson.ObjectID      `bson:"_id"`
+	Action    domain.AuditAction `bson:"action"`
+	UserID    string             `bson:"user_id"`
+	ActorID   string             `bson:"actor_id,omitempty"`
+	Details   map[string]string  `bson:"details,omitempty"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
+
+func NewMongoAuditRepository(db mongoDatabase) domain.AuditRepository {
+	return &mongoAuditRepository{coll: db.Collection("audit_log")}
+}
+
+func (r *mongoAuditRepository) RecordEvent(ctx context.Context, event *domain.AuditEvent) error {
+	currentTime := time.Now()
+	mongoEvent := &mongoAuditEvent{
+		ID:        bson.NewObjectIDFromTimestamp(currentTime),
+		Action:    event.Action,
+		UserID:    event.UserID,
+		ActorID:   event.ActorID,
+		Details:   event.Details,
+		CreatedAt: currentTime,
+	}
+
+	if _, err := r.coll.InsertOne(ctx, mongoEvent); err != nil {
+		return mongoError(err)
+	}
+
+	event.CreatedAt = currentTime
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type mongoAuditRepositoryMock struct {
+	collection *mocks.MongoCollection
+	repo       domain.AuditRepository
+}
+
+func setupMongoAuditRepository(t *testing.T) *mongoAuditRepositoryMock {
+	mockMongoCollection := mocks.NewMongoCollection(t)
+
+	return &mongoAuditRepositoryMock{
+		collection: mockMongoCollection,
+		repo:       &mongoAuditRepository{coll: mockMongoCollection},
+	}
+}
+
+func TestNewMongoAuditRepository_OK(t *testing.T) {
+	mongoColl := &mongo.Collection{}
+
+	md := mocks.NewMongoDatabase(t)
+	md.On("Collection", "audit_log").Return(mongoColl)
+
+	repo := NewMongoAuditRepository(md)
+
+	assert.NotNil(t, repo)
+	assert.Equal(t, mongoColl, repo.(*mongoAuditRepository).coll)
+}
+
+func TestRecordEvent_OK(t *testing.T) {
+	recorded := func(event *mongoAuditEvent) bool {
+		return event.Action == domain.AuditScreeningDeferred && event.UserID == "1" && event.Details["error"] == "down"
+	}
+
+	marm := setupMongoAuditRepository(t)
+	marm.collection.On("InsertOne", mock.IsType(nil), mock.MatchedBy(recorded)).Return(nil, nil)
+
+	event := &domain.AuditEvent{Action: domain.AuditScreeningDeferred, UserID: "1", Details: map[string]string{"error": "down"}}
+	err := marm.repo.RecordEvent(context.Context(nil), event)
+
+	assert.NoError(t, err)
+	assert.False(t, event.CreatedAt.IsZero())
+}
+
+func TestRecordEvent_Actor(t *testing.T) {
+	recorded := func(event *mongoAuditEvent) bool {
+		return event.Action == domain.AuditReviewApproved && event.UserID == "1" && event.ActorID == "2"
+	}
+
+	marm := setupMongoAuditRepository(t)
+	marm.collection.On("InsertOne", mock.IsType(nil), mock.MatchedBy(recorded)).Return(nil, nil)
+
+	err := marm.repo.RecordEvent(context.Context(nil), &domain.AuditEvent{Action: domain.AuditReviewApproved, UserID: "1", ActorID: "2"})
+
+	assert.NoError(t, err)
+}
+
+func TestRecordEvent_InsertOneError(t *testing.T) {
+	marm := setupMongoAuditRepository(t)
+	marm.collection.On("InsertOne", mock.IsType(nil), mock.AnythingOfType("*infrastructure.mongoAuditEvent")).Return(nil, assert.AnError)
+
+	err := marm.repo.RecordEvent(context.Context(nil), &domain.AuditEvent{})
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,75 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type mongoLoginAttemptRepository struct {
+	coll mongoCollection
+}
+
+type mongoLoginAttempts struct {
+	Key         string    `bson:"_id"`
+	Failures    int       `bson:"failures"`
+	LockedUntil time.Time `bson:"locked_until,omitempty"`
+	ExpiresAt   time.Time `bson:"expires_at"`
+}
+
+func NewMongoLoginAttemptRepository(db mongoDatabase) domain.LoginAttemptRepository {
+	return &mongoLoginAttemptRepository{coll: db.Collection("login_attempt")}
+}
+
+func (r *mongoLoginAttemptRepository) GetLoginAttempts(ctx context.Context, key string) (*domain.LoginAttempts, error) {
+	var attempts mongoLoginAttempts
+
+	// the TTL index is not immediate, expired counters are skipped here
+	err := r.coll.FindOne(ctx, bson.M{"_id": key, "expires_at": bson.M{"$gt": time.Now()}}).Decode(&attempts)
+	if err != nil {
+		return nil, mongoError(err)
+	}
+
+	return &domain.LoginAttempts{
+		Key:         attempts.Key,
+		Failures:    attempts.Failures,
+		LockedUntil: attempts.LockedUntil,
+		ExpiresAt:   attempts.ExpiresAt,
+	}, nil
+}
+
+// Atomic increment, the counter starts over once it has expired
+func (r *mongoLoginAttemptRepository) AddLoginFailure(ctx context.Context, key string, expiresAt time.Time) (int, error) {
+	var attempts mongoLoginAttempts
+
+	alive := bson.M{"$gt": bson.A{"$expires_at", time.Now()}}
+	update := bson.A{bson.M{"$set": bson.M{
+		"failures":   bson.M{"$cond": bson.A{alive, bson.M{"$add": bson.A{"$failures", 1}}, 1}},
+		"expires_at": bson.M{"$max": bson.A{"$expires_at", expiresAt}},
+	}}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	err := r.coll.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&attempts)
+	if err != nil {
+		return 0, mongoError(err)
+	}
+
+	return attempts.Failures, nil
+}
+
+func (r *mongoLoginAttemptRepository) LockLogin(ctx context.Context, key string, lockedUntil, expiresAt time.Time) error {
+	update := bson.M{"$set": bson.M{"locked_until": lockedUntil, "expires_at": expiresAt}}
+
+	_, err := r.coll.UpdateOne(ctx, bson.M{"_id": key}, update)
+
+	return mongoError(err)
+}
+
+func (r *mongoLoginAttemptRepository) ResetLoginAttempts(ctx context.Context, key string) error {
+	_, err := r.coll.DeleteOne(ctx, bson.M{"_id": key})
+
+	return mongoError(err)
+}
@@ -0,0 +1,140 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type mongoLoginAttemptRepositoryMock struct {
+	collection *mocks.MongoCollection
+	repo       domain.LoginAttemptRepository
+}
+
+func setupMongoLoginAttemptRepository(t *testing.T) *mongoLoginAttemptRepositoryMock {
+	mockMongoCollection := mocks.NewMongoCollection(t)
+
+	return &mongoLoginAttemptRepositoryMock{
+		collection: mockMongoCollection,
+		repo:       &mongoLoginAttemptRepository{coll: mockMongoCollection},
+	}
+}
+
+func TestNewMongoLoginAttemptRepository_OK(t *testing.T) {
+	mongoColl := &mongo.Collection{}
+
+	md := mocks.NewMongoDatabase(t)
+	md.On("Collection", "login_attempt").Return(mongoColl)
+
+	repo := NewMongoLoginAttemptRepository(md)
+
+	assert.NotNil(t, repo)
+	assert.Equal(t, mongoColl, repo.(*mongoLoginAttemptRepository).coll)
+}
+
+func TestGetLoginAttempts_OK(t *testing.T) {
+	now := time.Now()
+	res := mongo.NewSingleResultFromDocument(bson.M{"_id": "email:an@email.com", "failures": 3, "locked_until": now, "expires_at": now}, nil, nil)
+
+	mlm := setupMongoLoginAttemptRepository(t)
+	mlm.collection.On("FindOne", mock.IsType(nil), mock.AnythingOfType("bson.M")).Return(res)
+
+	attempts, err := mlm.repo.GetLoginAttempts(context.Context(nil), "email:an@email.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "email:an@email.com", attempts.Key)
+	assert.Equal(t, 3, attempts.Failures)
+	assert.Equal(t, now.UTC().Truncate(time.Millisecond), attempts.LockedUntil)
+}
+
+func TestGetLoginAttempts_FindOneErrorNoDocuments(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(bson.M{}, mongo.ErrNoDocuments, nil)
+
+	mlm := setupMongoLoginAttemptRepository(t)
+	mlm.collection.On("FindOne", mock.IsType(nil), mock.AnythingOfType("bson.M")).Return(res)
+
+	attempts, err := mlm.repo.GetLoginAttempts(context.Context(nil), "email:an@email.com")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Nil(t, attempts)
+}
+
+func TestGetLoginAttempts_FindOneError(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(nil, nil, nil)
+
+	mlm := setupMongoLoginAttemptRepository(t)
+	mlm.collection.On("FindOne", mock.IsType(nil), mock.AnythingOfType("bson.M")).Return(res)
+
+	attempts, err := mlm.repo.GetLoginAttempts(context.Context(nil), "email:an@email.com")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, mongo.ErrNilDocument.Error())
+	assert.Nil(t, attempts)
+}
+
+func TestAddLoginFailure_OK(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(bson.M{"_id": "ip:10.0.0.1", "failures": 2}, nil, nil)
+
+	mlm := setupMongoLoginAttemptRepository(t)
+	mlm.collection.On("FindOneAndUpdate", mock.IsType(nil), bson.M{"_id": "ip:10.0.0.1"}, mock.AnythingOfType("bson.A"), mock.AnythingOfType("*options.FindOneAndUpdateOptionsBuilder")).Return(res)
+
+	failures, err := mlm.repo.AddLoginFailure(context.Context(nil), "ip:10.0.0.1", time.Now())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, failures)
+}
+
+func TestAddLoginFailure_FindOneAndUpdateError(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(nil, nil, nil)
+
+	mlm := setupMongoLoginAttemptRepository(t)
+	mlm.collection.On("FindOneAndUpdate", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.A"), mock.AnythingOfType("*options.FindOneAndUpdateOptionsBuilder")).Return(res)
+
+	failures, err := mlm.repo.AddLoginFailure(context.Context(nil), "ip:10.0.0.1", time.Now())
+
+	assert.Error(t, err)
+	assert.Zero(t, failures)
+}
+
+func TestLockLogin_OK(t *testing.T) {
+	mlm := setupMongoLoginAttemptRepository(t)
+	mlm.collection.On("UpdateOne", mock.IsType(nil), bson.M{"_id": "email:an@email.com"}, mock.AnythingOfType("bson.M")).Return(&mongo.UpdateResult{MatchedCount: 1}, nil)
+
+	err := mlm.repo.LockLogin(context.Context(nil), "email:an@email.com", time.Now(), time.Now())
+
+	assert.NoError(t, err)
+}
+
+func TestLockLogin_UpdateOneError(t *testing.T) {
+	mlm := setupMongoLoginAttemptRepository(t)
+	mlm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(nil, assert.AnError)
+
+	err := mlm.repo.LockLogin(context.Context(nil), "email:an@email.com", time.Now(), time.Now())
+
+	assert.Error(t, err)
+}
+
+func TestResetLoginAttempts_OK(t *testing.T) {
+	mlm := setupMongoLoginAttemptRepository(t)
+	mlm.collection.On("DeleteOne", mock.IsType(nil), bson.M{"_id": "email:an@email.com"}).Return(&mongo.DeleteResult{DeletedCount: 1}, nil)
+
+	err := mlm.repo.ResetLoginAttempts(context.Context(nil), "email:an@email.com")
+
+	assert.NoError(t, err)
+}
+
+func TestResetLoginAttempts_DeleteOneError(t *testing.T) {
+	mlm := setupMongoLoginAttemptRepository(t)
+	mlm.collection.On("DeleteOne", mock.IsType(nil), mock.AnythingOfType("bson.M")).Return(nil, assert.AnError)
+
+	err := mlm.repo.ResetLoginAttempts(context.Context(nil), "email:an@email.com")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type mongoMFARepository struct {
+	coll mongoCollection
+}
+
+type mongoMFA struct {
+	UserID        string    `bson:"_id"`
+	Secret        string    `bson:"secret"`
+	Enabled       bool      `bson:"enabled"`
+	RecoveryCodes []string  `bson:"recovery_codes"`
+	LastStep      int64     `bson:"last_step"`
+	CreatedAt     time.Time `bson:"created_at"`
+	UpdatedAt     time.Time `bson:"updated_at"`
+}
+
+func NewMongoMFARepository(db mongoDatabase) domain.MFARepository {
+	return &mongoMFARepository{coll: db.Collection("mfa")}
+}
+
+func (r *mongoMFARepository) SaveMFA(ctx context.Context, mfa *domain.MFA) error {
+	opts := options.UpdateOne().SetUpsert(true)
+	update := bson.M{"$set": bson.M{
+		"secret":         mfa.Secret,
+		"enabled":        mfa.Enabled,
+		"recovery_codes": mfa.RecoveryCodes,
+		"last_step":      0,
+		"created_at":     mfa.CreatedAt,
+		"updated_at":     mfa.UpdatedAt,
+	}}
+
+	_, err := r.coll.UpdateOne(ctx, bson.M{"_id": mfa.UserID}, update, opts)
+
+	return mongoError(err)
+}
+
+func (r *mongoMFARepository) GetMFA(ctx context.Context, userID string) (*domain.MFA, error) {
+	var mfa mongoMFA
+
+	err := r.coll.FindOne(ctx, bson.M{"_id": userID}).Decode(&mfa)
+	if err != nil {
+		return nil, mongoError(err)
+	}
+
+	return &domain.MFA{
+		UserID:        mfa.UserID,
+		Secret:        mfa.Secret,
+		Enabled:       mfa.Enabled,
+		RecoveryCodes: mfa.RecoveryCodes,
+		CreatedAt:     mfa.CreatedAt,
+		UpdatedAt:     mfa.UpdatedAt,
+	}, nil
+}
+
+func (r *mongoMFARepository) EnableMFA(ctx context.Context, userID string, recoveryCodes []string) error {
+	filter := bson.M{"_id": userID, "enabled": false}
+	update := bson.M{"$set": bson.M{"enabled": true, "recovery_codes": recoveryCodes, "updated_at": time.Now()}}
+
+	res, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return mongoError(err)
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// Only moves forward, so a code cannot be used twice
+func (r *mongoMFARepository) UseTOTPStep(ctx context.Context, userID string, step int64) (bool, error) {
+	filter := bson.M{"_id": userID, "last_step": bson.M{"$lt": step}}
+	update := bson.M{"$set": bson.M{"last_step": step}}
+
+	res, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, mongoError(err)
+	}
+
+	return res.ModifiedCount == 1, nil
+}
+
+func (r *mongoMFARepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	filter := bson.M{"_id": userID, "enabled": true, "recovery_codes": codeHash}
+	update := bson.M{"$pull": bson.M{"recovery_codes": codeHash}, "$set": bson.M{"updated_at": time.Now()}}
+
+	res, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, mongoError(err)
+	}
+
+	return res.ModifiedCount == 1, nil
+}
+
+func (r *mongoMFARepository) DeleteMFA(ctx context.Context, userID string) error {
+	_, err := r.coll.DeleteOne(ctx, bson.M{"_id": userID})
+
+	return mongoError(err)
+}
@@ -0,0 +1,202 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type mongoMFARepositoryMock struct {
+	collection *mocks.MongoCollection
+	repo       domain.MFARepository
+}
+
+func setupMongoMFARepository(t *testing.T) *mongoMFARepositoryMock {
+	mockMongoCollection := mocks.NewMongoCollection(t)
+
+	return &mongoMFARepositoryMock{
+		collection: mockMongoCollection,
+		repo:       &mongoMFARepository{coll: mockMongoCollection},
+	}
+}
+
+func TestNewMongoMFARepository_OK(t *testing.T) {
+	mongoColl := &mongo.Collection{}
+
+	md := mocks.NewMongoDatabase(t)
+	md.On("Collection", "mfa").Return(mongoColl)
+
+	repo := NewMongoMFARepository(md)
+
+	assert.NotNil(t, repo)
+	assert.Equal(t, mongoColl, repo.(*mongoMFARepository).coll)
+}
+
+func TestSaveMFA_OK(t *testing.T) {
+	mmm := setupMongoMFARepository(t)
+	mmm.collection.On("UpdateOne", mock.IsType(nil), bson.M{"_id": "1"}, mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.UpdateOneOptionsBuilder")).Return(&mongo.UpdateResult{UpsertedCount: 1}, nil)
+
+	err := mmm.repo.SaveMFA(context.Context(nil), &domain.MFA{UserID: "1", Secret: "secret"})
+
+	assert.NoError(t, err)
+}
+
+func TestSaveMFA_UpdateOneError(t *testing.T) {
+	mmm := setupMongoMFARepository(t)
+	mmm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.UpdateOneOptionsBuilder")).Return(nil, assert.AnError)
+
+	err := mmm.repo.SaveMFA(context.Context(nil), &domain.MFA{UserID: "1"})
+
+	assert.Error(t, err)
+}
+
+func TestGetMFA_OK(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(bson.M{"_id": "1", "secret": "secret", "enabled": true, "recovery_codes": bson.A{"hash"}}, nil, nil)
+
+	mmm := setupMongoMFARepository(t)
+	mmm.collection.On("FindOne", mock.IsType(nil), bson.M{"_id": "1"}).Return(res)
+
+	mfa, err := mmm.repo.GetMFA(context.Context(nil), "1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1", mfa.UserID)
+	assert.Equal(t, "secret", mfa.Secret)
+	assert.True(t, mfa.Enabled)
+	assert.Equal(t, []string{"hash"}, mfa.RecoveryCodes)
+}
+
+func TestGetMFA_FindOneErrorNoDocuments(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(bson.M{}, mongo.ErrNoDocuments, nil)
+
+	mmm := setupMongoMFARepository(t)
+	mmm.collection.On("FindOne", mock.IsType(nil), mock.AnythingOfType("bson.M")).Return(res)
+
+	mfa, err := mmm.repo.GetMFA(context.Context(nil), "1")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Nil(t, mfa)
+}
+
+func TestGetMFA_FindOneError(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(nil, nil, nil)
+
+	mmm := setupMongoMFARepository(t)
+	mmm.collection.On("FindOne", mock.IsType(nil), mock.AnythingOfType("bson.M")).Return(res)
+
+	mfa, err := mmm.repo.GetMFA(context.Context(nil), "1")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, mongo.ErrNilDocument.Error())
+	assert.Nil(t, mfa)
+}
+
+func TestEnableMFA_OK(t *testing.T) {
+	mmm := setupMongoMFARepository(t)
+	mmm.collection.On("UpdateOne", mock.IsType(nil), bson.M{"_id": "1", "enabled": false}, mock.AnythingOfType("bson.M")).Return(&mongo.UpdateResult{MatchedCount: 1}, nil)
+
+	err := mmm.repo.EnableMFA(context.Context(nil), "1", []string{"hash"})
+
+	assert.NoError(t, err)
+}
+
+func TestEnableMFA_NotFound(t *testing.T) {
+	mmm := setupMongoMFARepository(t)
+	mmm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(&mongo.UpdateResult{}, nil)
+
+	err := mmm.repo.EnableMFA(context.Context(nil), "1", []string{"hash"})
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestEnableMFA_UpdateOneError(t *testing.T) {
+	mmm := setupMongoMFARepository(t)
+	mmm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(nil, assert.AnError)
+
+	err := mmm.repo.EnableMFA(context.Context(nil), "1", []string{"hash"})
+
+	assert.Error(t, err)
+}
+
+func TestUseTOTPStep_OK(t *testing.T) {
+	mmm := setupMongoMFARepository(t)
+	mmm.collection.On("UpdateOne", mock.IsType(nil), bson.M{"_id": "1", "last_step": bson.M{"$lt": int64(10)}}, bson.M{"$set": bson.M{"last_step": int64(10)}}).Return(&mongo.UpdateResult{ModifiedCount: 1}, nil)
+
+	used, err := mmm.repo.UseTOTPStep(context.Context(nil), "1", 10)
+
+	assert.NoError(t, err)
+	assert.True(t, used)
+}
+
+func TestUseTOTPStep_Replayed(t *testing.T) {
+	mmm := setupMongoMFARepository(t)
+	mmm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(&mongo.UpdateResult{}, nil)
+
+	used, err := mmm.repo.UseTOTPStep(context.Context(nil), "1", 10)
+
+	assert.NoError(t, err)
+	assert.False(t, used)
+}
+
+func TestUseTOTPStep_UpdateOneError(t *testing.T) {
+	mmm := setupMongoMFARepository(t)
+	mmm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(nil, assert.AnError)
+
+	used, err := mmm.repo.UseTOTPStep(context.Context(nil), "1", 10)
+
+	assert.Error(t, err)
+	assert.False(t, used)
+}
+
+func TestUseRecoveryCode_OK(t *testing.T) {
+	mmm := setupMongoMFARepository(t)
+	mmm.collection.On("UpdateOne", mock.IsType(nil), bson.M{"_id": "1", "enabled": true, "recovery_codes": "hash"}, mock.AnythingOfType("bson.M")).Return(&mongo.UpdateResult{ModifiedCount: 1}, nil)
+
+	used, err := mmm.repo.UseRecoveryCode(context.Context(nil), "1", "hash")
+
+	assert.NoError(t, err)
+	assert.True(t, used)
+}
+
+func TestUseRecoveryCode_Unknown(t *testing.T) {
+	mmm := setupMongoMFARepository(t)
+	mmm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(&mongo.UpdateResult{}, nil)
+
+	used, err := mmm.repo.UseRecoveryCode(context.Context(nil), "1", "hash")
+
+	assert.NoError(t, err)
+	assert.False(t, used)
+}
+
+func TestUseRecoveryCode_UpdateOneError(t *testing.T) {
+	mmm := setupMongoMFARepository(t)
+	mmm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(nil, assert.AnError)
+
+	used, err := mmm.repo.UseRecoveryCode(context.Context(nil), "1", "hash")
+
+	assert.Error(t, err)
+	assert.False(t, used)
+}
+
+func TestDeleteMFA_OK(t *testing.T) {
+	mmm := setupMongoMFARepository(t)
+	mmm.collection.On("DeleteOne", mock.IsType(nil), bson.M{"_id": "1"}).Return(&mongo.DeleteResult{DeletedCount: 1}, nil)
+
+	err := mmm.repo.DeleteMFA(context.Context(nil), "1")
+
+	assert.NoError(t, err)
+}
+
+func TestDeleteMFA_DeleteOneError(t *testing.T) {
+	mmm := setupMongoMFARepository(t)
+	mmm.collection.On("DeleteOne", mock.IsType(nil), mock.AnythingOfType("bson.M")).Return(nil, assert.AnError)
+
+	err := mmm.repo.DeleteMFA(context.Context(nil), "1")
+
+	assert.Error(t, err)
+}
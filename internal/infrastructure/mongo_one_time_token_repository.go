@@ -0,0 +1,98 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type mongoOneTimeTokenRepository struct {
+	coll mongoCollection
+}
+
+type mongoOneTimeToken struct {
+	Hash      string    `bson:"_id"`
+	UserID    string    `bson:"user_id"`
+	Purpose   string    `bson:"purpose"`
+	CreatedAt time.Time `bson:"created_at"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+func NewMongoOneTimeTokenRepository(db mongoDatabase) domain.OneTimeTokenRepository {
+	return &mongoOneTimeTokenRepository{coll: db.Collection("one_time_token")}
+}
+
+func (r *mongoOneTimeTokenRepository) CreateOneTimeToken(ctx context.Context, token *domain.OneTimeToken) error {
+	_, err := r.coll.InsertOne(ctx, &mongoOneTimeToken{
+		Hash:      token.Hash,
+		UserID:    token.UserID,
+		Purpose:   token.Purpose,
+		CreatedAt: token.CreatedAt,
+		ExpiresAt: token.ExpiresAt,
+	})
+
+	return mongoError(err)
+}
+
+func (r *mongoOneTimeTokenRepository) GetOneTimeToken(ctx context.Context, tokenHash, purpose string) (*domain.OneTimeToken, error) {
+	var token mongoOneTimeToken
+
+	err := r.coll.FindOne(ctx, bson.M{"_id": tokenHash, "purpose": purpose}).Decode(&token)
+	if err != nil {
+		return nil, mongoError(err)
+	}
+
+	return &domain.OneTimeToken{
+		Hash:      token.Hash,
+		UserID:    token.UserID,
+		Purpose:   token.Purpose,
+		CreatedAt: token.CreatedAt,
+		ExpiresAt: token.ExpiresAt,
+	}, nil
+}
+
+// Deleting on read is what makes the token single-use
+func (r *mongoOneTimeTokenRepository) ConsumeOneTimeToken(ctx context.Context, tokenHash, purpose string) (*domain.OneTimeToken, error) {
+	var token mongoOneTimeToken
+
+	err := r.coll.FindOneAndDelete(ctx, bson.M{"_id": tokenHash, "purpose": purpose}).Decode(&token)
+	if err != nil {
+		return nil, mongoError(err)
+	}
+
+	return &domain.OneTimeToken{
+		Hash:      token.Hash,
+		UserID:    token.UserID,
+		Purpose:   token.Purpose,
+		CreatedAt: token.CreatedAt,
+		ExpiresAt: token.ExpiresAt,
+	}, nil
+}
+
+func (r *mongoOneTimeTokenRepository) GetLatestOneTimeToken(ctx context.Context, userID, purpose string) (*domain.OneTimeToken, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	var token mongoOneTimeToken
+
+	err := r.coll.FindOne(ctx, bson.M{"user_id": userID, "purpose": purpose}, opts).Decode(&token)
+	if err != nil {
+		return nil, mongoError(err)
+	}
+
+	return &domain.OneTimeToken{
+		Hash:      token.Hash,
+		UserID:    token.UserID,
+		Purpose:   token.Purpose,
+		CreatedAt: token.CreatedAt,
+		ExpiresAt: token.ExpiresAt,
+	}, nil
+}
+
+func (r *mongoOneTimeTokenRepository) DeleteOneTimeTokens(ctx context.Context, userID, purpose string) error {
+	_, err := r.coll.DeleteMany(ctx, bson.M{"user_id": userID, "purpose": purpose})
+
+	return mongoError(err)
+}
@@ -0,0 +1,178 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type mongoOneTimeTokenRepositoryMock struct {
+	collection *mocks.MongoCollection
+	repo       domain.OneTimeTokenRepository
+}
+
+func setupMongoOneTimeTokenRepository(t *testing.T) *mongoOneTimeTokenRepositoryMock {
+	mockMongoCollection := mocks.NewMongoCollection(t)
+
+	return &mongoOneTimeTokenRepositoryMock{
+		collection: mockMongoCollection,
+		repo:       &mongoOneTimeTokenRepository{coll: mockMongoCollection},
+	}
+}
+
+func TestNewMongoOneTimeTokenRepository_OK(t *testing.T) {
+	mongoColl := &mongo.Collection{}
+
+	md := mocks.NewMongoDatabase(t)
+	md.On("Collection", "one_time_token").Return(mongoColl)
+
+	repo := NewMongoOneTimeTokenRepository(md)
+
+	assert.NotNil(t, repo)
+	assert.Equal(t, mongoColl, repo.(*mongoOneTimeTokenRepository).coll)
+}
+
+func TestCreateOneTimeToken_OK(t *testing.T) {
+	motm := setupMongoOneTimeTokenRepository(t)
+	motm.collection.On("InsertOne", mock.IsType(nil), mock.AnythingOfType("*infrastructure.mongoOneTimeToken")).Return(nil, nil)
+
+	err := motm.repo.CreateOneTimeToken(context.Context(nil), &domain.OneTimeToken{})
+
+	assert.NoError(t, err)
+}
+
+func TestCreateOneTimeToken_InsertOneError(t *testing.T) {
+	motm := setupMongoOneTimeTokenRepository(t)
+	motm.collection.On("InsertOne", mock.IsType(nil), mock.AnythingOfType("*infrastructure.mongoOneTimeToken")).Return(nil, assert.AnError)
+
+	err := motm.repo.CreateOneTimeToken(context.Context(nil), &domain.OneTimeToken{})
+
+	assert.Error(t, err)
+}
+
+func TestGetOneTimeToken_OK(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(bson.M{"_id": "hash", "user_id": "1", "purpose": domain.PasswordResetPurpose}, nil, nil)
+
+	motm := setupMongoOneTimeTokenRepository(t)
+	motm.collection.On("FindOne", mock.IsType(nil), bson.M{"_id": "hash", "purpose": domain.PasswordResetPurpose}).Return(res)
+
+	token, err := motm.repo.GetOneTimeToken(context.Context(nil), "hash", domain.PasswordResetPurpose)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "hash", token.Hash)
+	assert.Equal(t, "1", token.UserID)
+}
+
+func TestGetOneTimeToken_FindOneErrorNoDocuments(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(bson.M{}, mongo.ErrNoDocuments, nil)
+
+	motm := setupMongoOneTimeTokenRepository(t)
+	motm.collection.On("FindOne", mock.IsType(nil), mock.AnythingOfType("bson.M")).Return(res)
+
+	token, err := motm.repo.GetOneTimeToken(context.Context(nil), "hash", domain.PasswordResetPurpose)
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Nil(t, token)
+}
+
+func TestConsumeOneTimeToken_OK(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(bson.M{"_id": "hash", "user_id": "1", "purpose": domain.PasswordResetPurpose}, nil, nil)
+
+	motm := setupMongoOneTimeTokenRepository(t)
+	motm.collection.On("FindOneAndDelete", mock.IsType(nil), bson.M{"_id": "hash", "purpose": domain.PasswordResetPurpose}).Return(res)
+
+	token, err := motm.repo.ConsumeOneTimeToken(context.Context(nil), "hash", domain.PasswordResetPurpose)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1", token.UserID)
+	assert.Equal(t, domain.PasswordResetPurpose, token.Purpose)
+}
+
+func TestConsumeOneTimeToken_FindOneAndDeleteErrorNoDocuments(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(bson.M{}, mongo.ErrNoDocuments, nil)
+
+	motm := setupMongoOneTimeTokenRepository(t)
+	motm.collection.On("FindOneAndDelete", mock.IsType(nil), mock.AnythingOfType("bson.M")).Return(res)
+
+	token, err := motm.repo.ConsumeOneTimeToken(context.Context(nil), "hash", domain.PasswordResetPurpose)
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Nil(t, token)
+}
+
+func TestConsumeOneTimeToken_FindOneAndDeleteError(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(nil, nil, nil)
+
+	motm := setupMongoOneTimeTokenRepository(t)
+	motm.collection.On("FindOneAndDelete", mock.IsType(nil), mock.AnythingOfType("bson.M")).Return(res)
+
+	token, err := motm.repo.ConsumeOneTimeToken(context.Context(nil), "hash", domain.PasswordResetPurpose)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, mongo.ErrNilDocument.Error())
+	assert.Nil(t, token)
+}
+
+func TestGetLatestOneTimeToken_OK(t *testing.T) {
+	now := time.Now()
+	res := mongo.NewSingleResultFromDocument(bson.M{"_id": "hash", "user_id": "1", "purpose": domain.EmailVerificationPurpose, "created_at": now}, nil, nil)
+
+	motm := setupMongoOneTimeTokenRepository(t)
+	motm.collection.On("FindOne", mock.IsType(nil), bson.M{"user_id": "1", "purpose": domain.EmailVerificationPurpose}, mock.AnythingOfType("*options.FindOneOptionsBuilder")).Return(res)
+
+	token, err := motm.repo.GetLatestOneTimeToken(context.Context(nil), "1", domain.EmailVerificationPurpose)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "hash", token.Hash)
+	assert.Equal(t, now.UTC().Truncate(time.Millisecond), token.CreatedAt)
+}
+
+func TestGetLatestOneTimeToken_FindOneErrorNoDocuments(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(bson.M{}, mongo.ErrNoDocuments, nil)
+
+	motm := setupMongoOneTimeTokenRepository(t)
+	motm.collection.On("FindOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.FindOneOptionsBuilder")).Return(res)
+
+	token, err := motm.repo.GetLatestOneTimeToken(context.Context(nil), "1", domain.EmailVerificationPurpose)
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Nil(t, token)
+}
+
+func TestGetLatestOneTimeToken_FindOneError(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(nil, nil, nil)
+
+	motm := setupMongoOneTimeTokenRepository(t)
+	motm.collection.On("FindOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.FindOneOptionsBuilder")).Return(res)
+
+	token, err := motm.repo.GetLatestOneTimeToken(context.Context(nil), "1", domain.EmailVerificationPurpose)
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, mongo.ErrNilDocument.Error())
+	assert.Nil(t, token)
+}
+
+func TestDeleteOneTimeTokens_OK(t *testing.T) {
+	motm := setupMongoOneTimeTokenRepository(t)
+	motm.collection.On("DeleteMany", mock.IsType(nil), bson.M{"user_id": "1", "purpose": domain.PasswordResetPurpose}).Return(&mongo.DeleteResult{DeletedCount: 1}, nil)
+
+	err := motm.repo.DeleteOneTimeTokens(context.Context(nil), "1", domain.PasswordResetPurpose)
+
+	assert.NoError(t, err)
+}
+
+func TestDeleteOneTimeTokens_DeleteManyError(t *testing.T) {
+	motm := setupMongoOneTimeTokenRepository(t)
+	motm.collection.On("DeleteMany", mock.IsType(nil), mock.AnythingOfType("bson.M")).Return(nil, assert.AnError)
+
+	err := motm.repo.DeleteOneTimeTokens(context.Context(nil), "1", domain.PasswordResetPurpose)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,108 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type mongoRefreshTokenRepository struct {
+	coll mongoCollection
+}
+
+type mongoRefreshToken struct {
+	Hash       string            `bson:"_id"`
+	UserID     string            `bson:"user_id"`
+	FamilyID   string            `bson:"family_id"`
+	AuthMethod domain.AuthMethod `bson:"auth_method,omitempty"`
+	CreatedAt  time.Time         `bson:"created_at"`
+	ExpiresAt  time.Time         `bson:"expires_at"`
+	UsedAt     *time.Time        `bson:"used_at,omitempty"`
+	RevokedAt  *time.Time        `bson:"revoked_at,omitempty"`
+}
+
+func NewMongoRefreshTokenRepository(db mongoDatabase) domain.RefreshTokenRepository {
+	return &mongoRefreshTokenRepository{coll: db.Collection("refresh_token")}
+}
+
+func (r *mongoRefreshTokenRepository) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	_, err := r.coll.InsertOne(ctx, &mongoRefreshToken{
+		Hash:       token.Hash,
+		UserID:     token.UserID,
+		FamilyID:   token.FamilyID,
+		AuthMethod: token.AuthMethod,
+		CreatedAt:  token.CreatedAt,
+		ExpiresAt:  token.ExpiresAt,
+	})
+
+	return mongoError(err)
+}
+
+func (r *mongoRefreshTokenRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	var token mongoRefreshToken
+
+	err := r.coll.FindOne(ctx, bson.M{"_id": tokenHash}).Decode(&token)
+	if err != nil {
+		return nil, mongoError(err)
+	}
+
+	result := &domain.RefreshToken{
+		Hash:       token.Hash,
+		UserID:     token.UserID,
+		FamilyID:   token.FamilyID,
+		AuthMethod: token.AuthMethod,
+		CreatedAt:  token.CreatedAt,
+		ExpiresAt:  token.ExpiresAt,
+	}
+
+	if token.UsedAt != nil {
+		result.UsedAt = *token.UsedAt
+	}
+
+	if token.RevokedAt != nil {
+		result.RevokedAt = *token.RevokedAt
+	}
+
+	return result, nil
+}
+
+func (r *mongoRefreshTokenRepository) UseRefreshToken(ctx context.Context, tokenHash string) (bool, error) {
+	filter := bson.M{"_id": tokenHash, "used_at": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"used_at": time.Now()}}
+
+	res, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, mongoError(err)
+	}
+
+	return res.ModifiedCount == 1, nil
+}
+
+func (r *mongoRefreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string) error {
+	filter := bson.M{"family_id": familyID, "revoked_at": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"revoked_at": time.Now()}}
+
+	_, err := r.coll.UpdateMany(ctx, filter, update)
+
+	return mongoError(err)
+}
+
+func (r *mongoRefreshTokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID string) error {
+	filter := bson.M{"user_id": userID, "revoked_at": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"revoked_at": time.Now()}}
+
+	_, err := r.coll.UpdateMany(ctx, filter, update)
+
+	return mongoError(err)
+}
+
+func (r *mongoRefreshTokenRepository) RevokeOtherRefreshTokens(ctx context.Context, userID, familyID string) error {
+	filter := bson.M{"user_id": userID, "family_id": bson.M{"$ne": familyID}, "revoked_at": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"revoked_at": time.Now()}}
+
+	_, err := r.coll.UpdateMany(ctx, filter, update)
+
+	return mongoError(err)
+}
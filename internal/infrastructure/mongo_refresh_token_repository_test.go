@@ -0,0 +1,184 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type mongoRefreshTokenRepositoryMock struct {
+	collection *mocks.MongoCollection
+	repo       domain.RefreshTokenRepository
+}
+
+func setupMongoRefreshTokenRepository(t *testing.T) *mongoRefreshTokenRepositoryMock {
+	mockMongoCollection := mocks.NewMongoCollection(t)
+
+	return &mongoRefreshTokenRepositoryMock{
+		collection: mockMongoCollection,
+		repo:       &mongoRefreshTokenRepository{coll: mockMongoCollection},
+	}
+}
+
+func TestNewMongoRefreshTokenRepository_OK(t *testing.T) {
+	mongoColl := &mongo.Collection{}
+
+	md := mocks.NewMongoDatabase(t)
+	md.On("Collection", "refresh_token").Return(mongoColl)
+
+	repo := NewMongoRefreshTokenRepository(md)
+
+	assert.NotNil(t, repo)
+	assert.Equal(t, mongoColl, repo.(*mongoRefreshTokenRepository).coll)
+}
+
+func TestCreateRefreshToken_OK(t *testing.T) {
+	mrm := setupMongoRefreshTokenRepository(t)
+	mrm.collection.On("InsertOne", mock.IsType(nil), mock.AnythingOfType("*infrastructure.mongoRefreshToken")).Return(nil, nil)
+
+	err := mrm.repo.CreateRefreshToken(context.Context(nil), &domain.RefreshToken{})
+
+	assert.NoError(t, err)
+}
+
+func TestCreateRefreshToken_InsertOneError(t *testing.T) {
+	mrm := setupMongoRefreshTokenRepository(t)
+	mrm.collection.On("InsertOne", mock.IsType(nil), mock.AnythingOfType("*infrastructure.mongoRefreshToken")).Return(nil, assert.AnError)
+
+	err := mrm.repo.CreateRefreshToken(context.Context(nil), &domain.RefreshToken{})
+
+	assert.Error(t, err)
+}
+
+func TestGetRefreshToken_OK(t *testing.T) {
+	now := time.Now()
+	res := mongo.NewSingleResultFromDocument(bson.M{"_id": "hash", "user_id": "1", "family_id": "family", "auth_method": "totp", "used_at": now}, nil, nil)
+
+	mrm := setupMongoRefreshTokenRepository(t)
+	mrm.collection.On("FindOne", mock.IsType(nil), bson.M{"_id": "hash"}).Return(res)
+
+	token, err := mrm.repo.GetRefreshToken(context.Context(nil), "hash")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1", token.UserID)
+	assert.Equal(t, "family", token.FamilyID)
+	assert.Equal(t, domain.TOTPAuth, token.AuthMethod)
+	assert.Equal(t, now.UTC().Truncate(time.Millisecond), token.UsedAt)
+	assert.True(t, token.RevokedAt.IsZero())
+}
+
+func TestGetRefreshToken_FindOneErrorNoDocuments(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(bson.M{}, mongo.ErrNoDocuments, nil)
+
+	mrm := setupMongoRefreshTokenRepository(t)
+	mrm.collection.On("FindOne", mock.IsType(nil), mock.AnythingOfType("bson.M")).Return(res)
+
+	token, err := mrm.repo.GetRefreshToken(context.Context(nil), "hash")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Nil(t, token)
+}
+
+func TestGetRefreshToken_FindOneError(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(nil, nil, nil)
+
+	mrm := setupMongoRefreshTokenRepository(t)
+	mrm.collection.On("FindOne", mock.IsType(nil), mock.AnythingOfType("bson.M")).Return(res)
+
+	token, err := mrm.repo.GetRefreshToken(context.Context(nil), "hash")
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, mongo.ErrNilDocument.Error())
+	assert.Nil(t, token)
+}
+
+func TestUseRefreshToken_OK(t *testing.T) {
+	mrm := setupMongoRefreshTokenRepository(t)
+	mrm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(&mongo.UpdateResult{ModifiedCount: 1}, nil)
+
+	used, err := mrm.repo.UseRefreshToken(context.Context(nil), "hash")
+
+	assert.NoError(t, err)
+	assert.True(t, used)
+}
+
+func TestUseRefreshToken_AlreadyUsed(t *testing.T) {
+	mrm := setupMongoRefreshTokenRepository(t)
+	mrm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(&mongo.UpdateResult{}, nil)
+
+	used, err := mrm.repo.UseRefreshToken(context.Context(nil), "hash")
+
+	assert.NoError(t, err)
+	assert.False(t, used)
+}
+
+func TestUseRefreshToken_UpdateOneError(t *testing.T) {
+	mrm := setupMongoRefreshTokenRepository(t)
+	mrm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(nil, assert.AnError)
+
+	used, err := mrm.repo.UseRefreshToken(context.Context(nil), "hash")
+
+	assert.Error(t, err)
+	assert.False(t, used)
+}
+
+func TestRevokeRefreshTokenFamily_OK(t *testing.T) {
+	mrm := setupMongoRefreshTokenRepository(t)
+	mrm.collection.On("UpdateMany", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(&mongo.UpdateResult{ModifiedCount: 2}, nil)
+
+	err := mrm.repo.RevokeRefreshTokenFamily(context.Context(nil), "family")
+
+	assert.NoError(t, err)
+}
+
+func TestRevokeRefreshTokenFamily_UpdateManyError(t *testing.T) {
+	mrm := setupMongoRefreshTokenRepository(t)
+	mrm.collection.On("UpdateMany", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(nil, assert.AnError)
+
+	err := mrm.repo.RevokeRefreshTokenFamily(context.Context(nil), "family")
+
+	assert.Error(t, err)
+}
+
+func TestRevokeUserRefreshTokens_OK(t *testing.T) {
+	mrm := setupMongoRefreshTokenRepository(t)
+	mrm.collection.On("UpdateMany", mock.IsType(nil), bson.M{"user_id": "1", "revoked_at": bson.M{"$exists": false}}, mock.AnythingOfType("bson.M")).Return(&mongo.UpdateResult{ModifiedCount: 2}, nil)
+
+	err := mrm.repo.RevokeUserRefreshTokens(context.Context(nil), "1")
+
+	assert.NoError(t, err)
+}
+
+func TestRevokeOtherRefreshTokens_OK(t *testing.T) {
+	mrm := setupMongoRefreshTokenRepository(t)
+	mrm.collection.On("UpdateMany", mock.IsType(nil), bson.M{"user_id": "1", "family_id": bson.M{"$ne": "family"}, "revoked_at": bson.M{"$exists": false}}, mock.AnythingOfType("bson.M")).Return(&mongo.UpdateResult{ModifiedCount: 2}, nil)
+
+	err := mrm.repo.RevokeOtherRefreshTokens(context.Context(nil), "1", "family")
+
+	assert.NoError(t, err)
+}
+
+func TestRevokeOtherRefreshTokens_UpdateManyError(t *testing.T) {
+	mrm := setupMongoRefreshTokenRepository(t)
+	mrm.collection.On("UpdateMany", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(nil, assert.AnError)
+
+	err := mrm.repo.RevokeOtherRefreshTokens(context.Context(nil), "1", "family")
+
+	assert.Error(t, err)
+}
+
+func TestRevokeUserRefreshTokens_UpdateManyError(t *testing.T) {
+	mrm := setupMongoRefreshTokenRepository(t)
+	mrm.collection.On("UpdateMany", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(nil, assert.AnError)
+
+	err := mrm.repo.RevokeUserRefreshTokens(context.Context(nil), "1")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,105 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type mongoRevokedTokenRepository struct {
+	coll mongoCollection
+}
+
+type mongoRevokedToken struct {
+	ID         string    `bson:"_id"`
+	Generation int64     `bson:"generation,omitempty"`
+	ExpiresAt  time.Time `bson:"expires_at,omitempty"`
+}
+
+func NewMongoRevokedTokenRepository(db mongoDatabase) domain.RevokedTokenRepository {
+	return &mongoRevokedTokenRepository{coll: db.Collection("revoked_token")}
+}
+
+func (r *mongoRevokedTokenRepository) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	opts := options.UpdateOne().SetUpsert(true)
+	update := bson.M{"$set": bson.M{"expires_at": expiresAt}}
+
+	_, err := r.coll.UpdateOne(ctx, bson.M{"_id": tokenID}, update, opts)
+
+	return mongoError(err)
+}
+
+// The generation never expires, tokens carry it for as long as they live
+func (r *mongoRevokedTokenRepository) RevokeUserTokens(ctx context.Context, userID string) error {
+	opts := options.UpdateOne().SetUpsert(true)
+	update := bson.M{"$inc": bson.M{"generation": 1}, "$unset": bson.M{"issued_before": "", "expires_at": ""}}
+
+	_, err := r.coll.UpdateOne(ctx, bson.M{"_id": userRevocationID(userID)}, update, opts)
+
+	return mongoError(err)
+}
+
+func (r *mongoRevokedTokenRepository) GetTokenGeneration(ctx context.Context, userID string) (int64, error) {
+	var revoked mongoRevokedToken
+
+	err := r.coll.FindOne(ctx, bson.M{"_id": userRevocationID(userID)}).Decode(&revoked)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, nil
+		}
+
+		return 0, mongoError(err)
+	}
+
+	return revoked.Generation, nil
+}
+
+func (r *mongoRevokedTokenRepository) RevokeSession(ctx context.Context, sessionID string, expiresAt time.Time) error {
+	opts := options.UpdateOne().SetUpsert(true)
+	update := bson.M{"$set": bson.M{"expires_at": expiresAt}}
+
+	_, err := r.coll.UpdateOne(ctx, bson.M{"_id": sessionRevocationID(sessionID)}, update, opts)
+
+	return mongoError(err)
+}
+
+func (r *mongoRevokedTokenRepository) IsTokenRevoked(ctx context.Context, token *domain.AccessToken) (bool, error) {
+	// the TTL monitor only runs once a minute
+	now := time.Now()
+	revocations := bson.A{
+		bson.M{"_id": token.ID, "expires_at": bson.M{"$gt": now}},
+		bson.M{"_id": userRevocationID(token.UserID), "generation": bson.M{"$gt": token.Generation}},
+	}
+
+	if token.SessionID != "" {
+		revocations = append(revocations, bson.M{"_id": sessionRevocationID(token.SessionID), "expires_at": bson.M{"$gt": now}})
+	}
+
+	filter := bson.M{"$or": revocations}
+
+	var revoked mongoRevokedToken
+
+	err := r.coll.FindOne(ctx, filter).Decode(&revoked)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return false, nil
+		}
+
+		return false, mongoError(err)
+	}
+
+	return true, nil
+}
+
+// user wide entries share the collection with token ids
+func userRevocationID(userID string) string {
+	return "user:" + userID
+}
+
+func sessionRevocationID(sessionID string) string {
+	return "session:" + sessionID
+}
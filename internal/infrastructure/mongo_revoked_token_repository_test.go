@@ -0,0 +1,197 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type mongoRevokedTokenRepositoryMock struct {
+	collection *mocks.MongoCollection
+	repo       domain.RevokedTokenRepository
+}
+
+func setupMongoRevokedTokenRepository(t *testing.T) *mongoRevokedTokenRepositoryMock {
+	mockMongoCollection := mocks.NewMongoCollection(t)
+
+	return &mongoRevokedTokenRepositoryMock{
+		collection: mockMongoCollection,
+		repo:       &mongoRevokedTokenRepository{coll: mockMongoCollection},
+	}
+}
+
+func TestNewMongoRevokedTokenRepository_OK(t *testing.T) {
+	mongoColl := &mongo.Collection{}
+
+	md := mocks.NewMongoDatabase(t)
+	md.On("Collection", "revoked_token").Return(mongoColl)
+
+	repo := NewMongoRevokedTokenRepository(md)
+
+	assert.NotNil(t, repo)
+	assert.Equal(t, mongoColl, repo.(*mongoRevokedTokenRepository).coll)
+}
+
+func TestRevokeToken_OK(t *testing.T) {
+	mrm := setupMongoRevokedTokenRepository(t)
+	mrm.collection.On("UpdateOne", mock.IsType(nil), bson.M{"_id": "jti"}, mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.UpdateOneOptionsBuilder")).Return(&mongo.UpdateResult{UpsertedCount: 1}, nil)
+
+	err := mrm.repo.RevokeToken(context.Context(nil), "jti", time.Now())
+
+	assert.NoError(t, err)
+}
+
+func TestRevokeToken_UpdateOneError(t *testing.T) {
+	mrm := setupMongoRevokedTokenRepository(t)
+	mrm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.UpdateOneOptionsBuilder")).Return(nil, assert.AnError)
+
+	err := mrm.repo.RevokeToken(context.Context(nil), "jti", time.Now())
+
+	assert.Error(t, err)
+}
+
+func TestRevokeUserTokens_OK(t *testing.T) {
+	update := bson.M{"$inc": bson.M{"generation": 1}, "$unset": bson.M{"issued_before": "", "expires_at": ""}}
+
+	mrm := setupMongoRevokedTokenRepository(t)
+	mrm.collection.On("UpdateOne", mock.IsType(nil), bson.M{"_id": "user:1"}, update, mock.AnythingOfType("*options.UpdateOneOptionsBuilder")).Return(&mongo.UpdateResult{UpsertedCount: 1}, nil)
+
+	err := mrm.repo.RevokeUserTokens(context.Context(nil), "1")
+
+	assert.NoError(t, err)
+}
+
+func TestRevokeUserTokens_UpdateOneError(t *testing.T) {
+	mrm := setupMongoRevokedTokenRepository(t)
+	mrm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.UpdateOneOptionsBuilder")).Return(nil, assert.AnError)
+
+	err := mrm.repo.RevokeUserTokens(context.Context(nil), "1")
+
+	assert.Error(t, err)
+}
+
+func TestGetTokenGeneration_OK(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(bson.M{"_id": "user:1", "generation": int64(3)}, nil, nil)
+
+	mrm := setupMongoRevokedTokenRepository(t)
+	mrm.collection.On("FindOne", mock.IsType(nil), bson.M{"_id": "user:1"}).Return(res)
+
+	generation, err := mrm.repo.GetTokenGeneration(context.Context(nil), "1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), generation)
+}
+
+func TestGetTokenGeneration_NeverRevoked(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(bson.M{}, mongo.ErrNoDocuments, nil)
+
+	mrm := setupMongoRevokedTokenRepository(t)
+	mrm.collection.On("FindOne", mock.IsType(nil), bson.M{"_id": "user:1"}).Return(res)
+
+	generation, err := mrm.repo.GetTokenGeneration(context.Context(nil), "1")
+
+	assert.NoError(t, err)
+	assert.Zero(t, generation)
+}
+
+func TestGetTokenGeneration_FindOneError(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(bson.M{}, assert.AnError, nil)
+
+	mrm := setupMongoRevokedTokenRepository(t)
+	mrm.collection.On("FindOne", mock.IsType(nil), mock.AnythingOfType("bson.M")).Return(res)
+
+	_, err := mrm.repo.GetTokenGeneration(context.Context(nil), "1")
+
+	assert.Error(t, err)
+}
+
+func TestRevokeSessionToken_OK(t *testing.T) {
+	mrm := setupMongoRevokedTokenRepository(t)
+	mrm.collection.On("UpdateOne", mock.IsType(nil), bson.M{"_id": "session:family"}, mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.UpdateOneOptionsBuilder")).Return(&mongo.UpdateResult{UpsertedCount: 1}, nil)
+
+	err := mrm.repo.RevokeSession(context.Context(nil), "family", time.Now())
+
+	assert.NoError(t, err)
+}
+
+func TestRevokeSessionToken_UpdateOneError(t *testing.T) {
+	mrm := setupMongoRevokedTokenRepository(t)
+	mrm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.UpdateOneOptionsBuilder")).Return(nil, assert.AnError)
+
+	err := mrm.repo.RevokeSession(context.Context(nil), "family", time.Now())
+
+	assert.Error(t, err)
+}
+
+func TestIsTokenRevoked_OK(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(bson.M{"_id": "jti", "expires_at": time.Now().Add(time.Minute)}, nil, nil)
+
+	mrm := setupMongoRevokedTokenRepository(t)
+	mrm.collection.On("FindOne", mock.IsType(nil), mock.AnythingOfType("bson.M")).Return(res)
+
+	revoked, err := mrm.repo.IsTokenRevoked(context.Context(nil), &domain.AccessToken{ID: "jti", UserID: "1"})
+
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestIsTokenRevoked_FindOneErrorNoDocuments(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(bson.M{}, mongo.ErrNoDocuments, nil)
+
+	mrm := setupMongoRevokedTokenRepository(t)
+	mrm.collection.On("FindOne", mock.IsType(nil), mock.AnythingOfType("bson.M")).Return(res)
+
+	revoked, err := mrm.repo.IsTokenRevoked(context.Context(nil), &domain.AccessToken{ID: "jti"})
+
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestIsTokenRevoked_FindOneError(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(nil, nil, nil)
+
+	mrm := setupMongoRevokedTokenRepository(t)
+	mrm.collection.On("FindOne", mock.IsType(nil), mock.AnythingOfType("bson.M")).Return(res)
+
+	revoked, err := mrm.repo.IsTokenRevoked(context.Context(nil), &domain.AccessToken{ID: "jti"})
+
+	assert.Error(t, err)
+	assert.False(t, revoked)
+}
+
+func TestIsTokenRevoked_UserGeneration(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(bson.M{"_id": "user:1", "generation": int64(3)}, nil, nil)
+	olderGeneration := func(filter bson.M) bool {
+		return assert.ObjectsAreEqual(bson.M{"_id": "user:1", "generation": bson.M{"$gt": int64(2)}}, filter["$or"].(bson.A)[1])
+	}
+
+	mrm := setupMongoRevokedTokenRepository(t)
+	mrm.collection.On("FindOne", mock.IsType(nil), mock.MatchedBy(olderGeneration)).Return(res)
+
+	revoked, err := mrm.repo.IsTokenRevoked(context.Context(nil), &domain.AccessToken{ID: "jti", UserID: "1", Generation: 2})
+
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestIsTokenRevoked_Session(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(bson.M{"_id": "session:family", "expires_at": time.Now().Add(time.Minute)}, nil, nil)
+	withSession := func(filter bson.M) bool {
+		return len(filter["$or"].(bson.A)) == 3
+	}
+
+	mrm := setupMongoRevokedTokenRepository(t)
+	mrm.collection.On("FindOne", mock.IsType(nil), mock.MatchedBy(withSession)).Return(res)
+
+	revoked, err := mrm.repo.IsTokenRevoked(context.Context(nil), &domain.AccessToken{ID: "jti", UserID: "1", SessionID: "family"})
+
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
@@ -0,0 +1,146 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type mongoScreeningRepository struct {
+	coll mongoCollection
+}
+
+type mongoScreening struct {
+	ID          bson.ObjectID `bson:"_id"`
+	UserID      string        `bson:"user_id,omitempty"`
+	NameHash    string        `bson:"name_hash"`
+	EmailHash   string        `bson:"email_hash"`
+	Provider    string        `bson:"provider"`
+	InBlacklist *bool         `bson:"in_blacklist,omitempty"`
+	Score       float64       `bson:"score,omitempty"`
+	Lists       []string      `bson:"matched_lists,omitempty"`
+	EntityID    string        `bson:"entity_id,omitempty"`
+	ReasonCodes []string      `bson:"reason_codes,omitempty"`
+	LatencyMS   int64         `bson:"latency_ms"`
+	Error       string        `bson:"error,omitempty"`
+	CreatedAt   time.Time     `bson:"created_at"`
+}
+
+func NewMongoScreeningRepository(db mongoDatabase) domain.ScreeningRepository {
+	return &mongoScreeningRepository{coll: db.Collection("pld_screening")}
+}
+
+func (r *mongoScreeningRepository) SaveScreening(ctx context.Context, record *domain.ScreeningRecord) error {
+	currentTime := time.Now()
+	mongoScreening := &mongoScreening{
+		ID:          bson.NewObjectIDFromTimestamp(currentTime),
+		UserID:      record.UserID,
+		NameHash:    record.NameHash,
+		EmailHash:   record.EmailHash,
+		Provider:    record.Provider,
+		InBlacklist: record.InBlacklist,
+		Score:       record.Score,
+		Lists:       record.MatchedLists,
+		EntityID:    record.EntityID,
+		ReasonCodes: record.ReasonCodes,
+		LatencyMS:   record.LatencyMS,
+		Error:       record.Error,
+		CreatedAt:   currentTime,
+	}
+
+	if _, err := r.coll.InsertOne(ctx, mongoScreening); err != nil {
+		return mongoError(err)
+	}
+
+	record.ID = mongoScreening.ID.Hex()
+	record.CreatedAt = currentTime
+
+	return nil
+}
+
+// Pages are fetched with one extra record, to know if there is a next page
+func (r *mongoScreeningRepository) ListScreenings(ctx context.Context, query *domain.ScreeningQuery) (*domain.ScreeningPage, error) {
+	filter := screeningFilter(&query.Filter)
+
+	// the cursor is the id of the last record of the previous page
+	if query.Cursor != "" {
+		cursor, err := bson.ObjectIDFromHex(query.Cursor)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+
+		filter["_id"] = bson.M{"$lt": cursor}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetLimit(int64(query.Limit + 1))
+
+	res, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, mongoError(err)
+	}
+
+	var screenings []mongoScreening
+	if err = res.All(ctx, &screenings); err != nil {
+		return nil, mongoError(err)
+	}
+
+	page := &domain.ScreeningPage{Screenings: make([]*domain.ScreeningRecord, 0, len(screenings))}
+
+	if len(screenings) > query.Limit {
+		screenings = screenings[:query.Limit]
+		page.NextCursor = screenings[len(screenings)-1].ID.Hex()
+	}
+
+	for _, screening := range screenings {
+		page.Screenings = append(page.Screenings, &domain.ScreeningRecord{
+			ID:           screening.ID.Hex(),
+			UserID:       screening.UserID,
+			NameHash:     screening.NameHash,
+			EmailHash:    screening.EmailHash,
+			Provider:     screening.Provider,
+			InBlacklist:  screening.InBlacklist,
+			Score:        screening.Score,
+			MatchedLists: screening.Lists,
+			EntityID:     screening.EntityID,
+			ReasonCodes:  screening.ReasonCodes,
+			LatencyMS:    screening.LatencyMS,
+			Error:        screening.Error,
+			CreatedAt:    screening.CreatedAt,
+		})
+	}
+
+	return page, nil
+}
+
+// Helper function to translate a screening filter into a mongo query
+func screeningFilter(f *domain.ScreeningFilter) bson.M {
+	filter := bson.M{}
+
+	if f.UserID != "" {
+		filter["user_id"] = f.UserID
+	}
+
+	if f.EmailHash != "" {
+		filter["email_hash"] = f.EmailHash
+	}
+
+	created := bson.M{}
+	if !f.CreatedAfter.IsZero() {
+		created["$gte"] = f.CreatedAfter
+	}
+
+	if !f.CreatedBefore.IsZero() {
+		created["$lt"] = f.CreatedBefore
+	}
+
+	if len(created) > 0 {
+		filter["created_at"] = created
+	}
+
+	return filter
+}
@@ -0,0 +1,147 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type mongoScreeningRepositoryMock struct {
+	collection *mocks.MongoCollection
+	repo       domain.ScreeningRepository
+}
+
+func setupMongoScreeningRepository(t *testing.T) *mongoScreeningRepositoryMock {
+	mockMongoCollection := mocks.NewMongoCollection(t)
+
+	return &mongoScreeningRepositoryMock{
+		collection: mockMongoCollection,
+		repo:       &mongoScreeningRepository{coll: mockMongoCollection},
+	}
+}
+
+func TestNewMongoScreeningRepository_OK(t *testing.T) {
+	mongoColl := &mongo.Collection{}
+
+	md := mocks.NewMongoDatabase(t)
+	md.On("Collection", "pld_screening").Return(mongoColl)
+
+	repo := NewMongoScreeningRepository(md)
+
+	assert.NotNil(t, repo)
+	assert.Equal(t, mongoColl, repo.(*mongoScreeningRepository).coll)
+}
+
+func TestSaveScreening_OK(t *testing.T) {
+	inBlacklist := false
+	saved := func(screening *mongoScreening) bool {
+		return screening.UserID == "1" && screening.EmailHash == "hash" && *screening.InBlacklist == inBlacklist && screening.LatencyMS == 12
+	}
+
+	msrm := setupMongoScreeningRepository(t)
+	msrm.collection.On("InsertOne", mock.IsType(nil), mock.MatchedBy(saved)).Return(nil, nil)
+
+	record := &domain.ScreeningRecord{UserID: "1", EmailHash: "hash", InBlacklist: &inBlacklist, LatencyMS: 12}
+	err := msrm.repo.SaveScreening(context.Context(nil), record)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, record.ID)
+	assert.False(t, record.CreatedAt.IsZero())
+}
+
+func TestSaveScreening_InsertOneError(t *testing.T) {
+	msrm := setupMongoScreeningRepository(t)
+	msrm.collection.On("InsertOne", mock.IsType(nil), mock.AnythingOfType("*infrastructure.mongoScreening")).Return(nil, assert.AnError)
+
+	err := msrm.repo.SaveScreening(context.Context(nil), &domain.ScreeningRecord{})
+
+	assert.Error(t, err)
+}
+
+func TestListScreenings_FindOK(t *testing.T) {
+	first, second, third := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+	res, _ := mongo.NewCursorFromDocuments([]interface{}{
+		bson.M{"_id": third, "user_id": "1", "in_blacklist": true},
+		bson.M{"_id": second, "error": "PLD answered 500"},
+		bson.M{"_id": first},
+	}, nil, nil)
+
+	msrm := setupMongoScreeningRepository(t)
+	msrm.collection.On("Find", mock.IsType(nil), bson.M{}, mock.AnythingOfType("*options.FindOptionsBuilder")).Return(res, nil)
+
+	page, err := msrm.repo.ListScreenings(context.Context(nil), &domain.ScreeningQuery{Limit: 2})
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Screenings, 2)
+	assert.Equal(t, "1", page.Screenings[0].UserID)
+	assert.True(t, *page.Screenings[0].InBlacklist)
+	assert.Nil(t, page.Screenings[1].InBlacklist)
+	assert.Equal(t, "PLD answered 500", page.Screenings[1].Error)
+	assert.Equal(t, second.Hex(), page.NextCursor)
+}
+
+func TestListScreenings_Match(t *testing.T) {
+	res, _ := mongo.NewCursorFromDocuments([]interface{}{
+		bson.M{"_id": bson.NewObjectID(), "in_blacklist": true, "score": 0.8, "matched_lists": bson.A{"OFAC"}, "entity_id": "E-1", "reason_codes": bson.A{"NAME_MATCH"}},
+	}, nil, nil)
+
+	msrm := setupMongoScreeningRepository(t)
+	msrm.collection.On("Find", mock.IsType(nil), bson.M{}, mock.AnythingOfType("*options.FindOptionsBuilder")).Return(res, nil)
+
+	page, err := msrm.repo.ListScreenings(context.Context(nil), &domain.ScreeningQuery{Limit: 2})
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Screenings, 1)
+	assert.Equal(t, 0.8, page.Screenings[0].Score)
+	assert.Equal(t, []string{"OFAC"}, page.Screenings[0].MatchedLists)
+	assert.Equal(t, "E-1", page.Screenings[0].EntityID)
+	assert.Equal(t, []string{"NAME_MATCH"}, page.Screenings[0].ReasonCodes)
+}
+
+func TestListScreenings_Cursor(t *testing.T) {
+	cursor := bson.NewObjectID()
+	res, _ := mongo.NewCursorFromDocuments([]interface{}{}, nil, nil)
+
+	msrm := setupMongoScreeningRepository(t)
+	msrm.collection.On("Find", mock.IsType(nil), bson.M{"_id": bson.M{"$lt": cursor}}, mock.AnythingOfType("*options.FindOptionsBuilder")).Return(res, nil)
+
+	page, err := msrm.repo.ListScreenings(context.Context(nil), &domain.ScreeningQuery{Cursor: cursor.Hex(), Limit: 2})
+
+	assert.NoError(t, err)
+	assert.Empty(t, page.Screenings)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestListScreenings_InvalidCursor(t *testing.T) {
+	msrm := setupMongoScreeningRepository(t)
+
+	page, err := msrm.repo.ListScreenings(context.Context(nil), &domain.ScreeningQuery{Cursor: "abc", Limit: 2})
+
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	assert.Nil(t, page)
+}
+
+func TestListScreenings_FindError(t *testing.T) {
+	msrm := setupMongoScreeningRepository(t)
+	msrm.collection.On("Find", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.FindOptionsBuilder")).Return(nil, assert.AnError)
+
+	page, err := msrm.repo.ListScreenings(context.Context(nil), &domain.ScreeningQuery{Limit: 2})
+
+	assert.Error(t, err)
+	assert.Nil(t, page)
+}
+
+func TestScreeningFilter(t *testing.T) {
+	after := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	filter := screeningFilter(&domain.ScreeningFilter{UserID: "1", EmailHash: "hash", CreatedAfter: after})
+
+	assert.Equal(t, bson.M{"user_id": "1", "email_hash": "hash", "created_at": bson.M{"$gte": after}}, filter)
+}
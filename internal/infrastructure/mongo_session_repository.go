@@ -0,0 +1,115 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type mongoSessionRepository struct {
+	coll mongoCollection
+}
+
+type mongoSession struct {
+	ID         string            `bson:"_id"`
+	UserID     string            `bson:"user_id"`
+	UserAgent  string            `bson:"user_agent"`
+	IP         string            `bson:"ip"`
+	AuthMethod domain.AuthMethod `bson:"auth_method"`
+	CreatedAt  time.Time         `bson:"created_at"`
+	LastSeenAt time.Time         `bson:"last_seen_at"`
+	ExpiresAt  time.Time         `bson:"expires_at"`
+	RevokedAt  *time.Time        `bson:"revoked_at,omitempty"`
+}
+
+func NewMongoSessionRepository(db mongoDatabase) domain.SessionRepository {
+	return &mongoSessionRepository{coll: db.Collection("session")}
+}
+
+// Upsert, so refresh token families created before sessions get one too
+func (r *mongoSessionRepository) SaveSession(ctx context.Context, session *domain.Session) error {
+	opts := options.UpdateOne().SetUpsert(true)
+	update := bson.M{
+		"$set": bson.M{
+			"user_agent":   session.UserAgent,
+			"ip":           session.IP,
+			"last_seen_at": session.LastSeenAt,
+			"expires_at":   session.ExpiresAt,
+		},
+		"$setOnInsert": bson.M{
+			"user_id":     session.UserID,
+			"auth_method": session.AuthMethod,
+			"created_at":  session.CreatedAt,
+		},
+	}
+
+	_, err := r.coll.UpdateOne(ctx, bson.M{"_id": session.ID}, update, opts)
+
+	return mongoError(err)
+}
+
+func (r *mongoSessionRepository) ListSessions(ctx context.Context, userID string) ([]*domain.Session, error) {
+	filter := bson.M{"user_id": userID, "revoked_at": bson.M{"$exists": false}, "expires_at": bson.M{"$gt": time.Now()}}
+	opts := options.Find().SetSort(bson.D{{Key: "last_seen_at", Value: -1}})
+
+	res, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, mongoError(err)
+	}
+
+	var sessions []mongoSession
+	if err = res.All(ctx, &sessions); err != nil {
+		return nil, mongoError(err)
+	}
+
+	result := make([]*domain.Session, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, &domain.Session{
+			ID:         session.ID,
+			UserID:     session.UserID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			AuthMethod: session.AuthMethod,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+		})
+	}
+
+	return result, nil
+}
+
+// The user id is part of the filter, a user cannot revoke the sessions of another
+func (r *mongoSessionRepository) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	filter := bson.M{"_id": sessionID, "user_id": userID, "revoked_at": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"revoked_at": time.Now()}}
+
+	res, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return mongoError(err)
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *mongoSessionRepository) RevokeUserSessions(ctx context.Context, userID string) error {
+	filter := bson.M{"user_id": userID, "revoked_at": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"revoked_at": time.Now()}}
+
+	_, err := r.coll.UpdateMany(ctx, filter, update)
+
+	return mongoError(err)
+}
+
+func (r *mongoSessionRepository) DeleteUserSessions(ctx context.Context, userID string) error {
+	_, err := r.coll.DeleteMany(ctx, bson.M{"user_id": userID})
+
+	return mongoError(err)
+}
@@ -0,0 +1,164 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type mongoSessionRepositoryMock struct {
+	collection *mocks.MongoCollection
+	repo       domain.SessionRepository
+}
+
+func setupMongoSessionRepository(t *testing.T) *mongoSessionRepositoryMock {
+	mockMongoCollection := mocks.NewMongoCollection(t)
+
+	return &mongoSessionRepositoryMock{
+		collection: mockMongoCollection,
+		repo:       &mongoSessionRepository{coll: mockMongoCollection},
+	}
+}
+
+func TestNewMongoSessionRepository_OK(t *testing.T) {
+	mongoColl := &mongo.Collection{}
+
+	md := mocks.NewMongoDatabase(t)
+	md.On("Collection", "session").Return(mongoColl)
+
+	repo := NewMongoSessionRepository(md)
+
+	assert.NotNil(t, repo)
+	assert.Equal(t, mongoColl, repo.(*mongoSessionRepository).coll)
+}
+
+func TestSaveSession_OK(t *testing.T) {
+	msm := setupMongoSessionRepository(t)
+	msm.collection.On("UpdateOne", mock.IsType(nil), bson.M{"_id": "family"}, mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.UpdateOneOptionsBuilder")).Return(&mongo.UpdateResult{UpsertedCount: 1}, nil)
+
+	err := msm.repo.SaveSession(context.Context(nil), &domain.Session{ID: "family", UserID: "1"})
+
+	assert.NoError(t, err)
+}
+
+func TestSaveSession_UpdateOneError(t *testing.T) {
+	msm := setupMongoSessionRepository(t)
+	msm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.UpdateOneOptionsBuilder")).Return(nil, assert.AnError)
+
+	err := msm.repo.SaveSession(context.Context(nil), &domain.Session{ID: "family", UserID: "1"})
+
+	assert.Error(t, err)
+}
+
+func TestListSessions_OK(t *testing.T) {
+	now := time.Now()
+	res, _ := mongo.NewCursorFromDocuments([]interface{}{
+		bson.M{"_id": "family", "user_id": "1", "user_agent": "curl/8.0", "ip": "192.0.2.1", "auth_method": domain.PasswordAuth, "last_seen_at": now},
+	}, nil, nil)
+
+	msm := setupMongoSessionRepository(t)
+	msm.collection.On("Find", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.FindOptionsBuilder")).Return(res, nil)
+
+	sessions, err := msm.repo.ListSessions(context.Context(nil), "1")
+
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 1)
+	assert.Equal(t, "family", sessions[0].ID)
+	assert.Equal(t, "curl/8.0", sessions[0].UserAgent)
+	assert.Equal(t, "192.0.2.1", sessions[0].IP)
+	assert.Equal(t, domain.PasswordAuth, sessions[0].AuthMethod)
+	assert.Equal(t, now.UTC().Truncate(time.Millisecond), sessions[0].LastSeenAt)
+}
+
+func TestListSessions_Empty(t *testing.T) {
+	res, _ := mongo.NewCursorFromDocuments(nil, nil, nil)
+
+	msm := setupMongoSessionRepository(t)
+	msm.collection.On("Find", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.FindOptionsBuilder")).Return(res, nil)
+
+	sessions, err := msm.repo.ListSessions(context.Context(nil), "1")
+
+	assert.NoError(t, err)
+	assert.NotNil(t, sessions)
+	assert.Empty(t, sessions)
+}
+
+func TestListSessions_FindError(t *testing.T) {
+	msm := setupMongoSessionRepository(t)
+	msm.collection.On("Find", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.FindOptionsBuilder")).Return(nil, assert.AnError)
+
+	sessions, err := msm.repo.ListSessions(context.Context(nil), "1")
+
+	assert.Error(t, err)
+	assert.Nil(t, sessions)
+}
+
+func TestRevokeSession_OK(t *testing.T) {
+	msm := setupMongoSessionRepository(t)
+	msm.collection.On("UpdateOne", mock.IsType(nil), bson.M{"_id": "family", "user_id": "1", "revoked_at": bson.M{"$exists": false}}, mock.AnythingOfType("bson.M")).Return(&mongo.UpdateResult{MatchedCount: 1}, nil)
+
+	err := msm.repo.RevokeSession(context.Context(nil), "1", "family")
+
+	assert.NoError(t, err)
+}
+
+func TestRevokeSession_NotFound(t *testing.T) {
+	msm := setupMongoSessionRepository(t)
+	msm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(&mongo.UpdateResult{}, nil)
+
+	err := msm.repo.RevokeSession(context.Context(nil), "1", "family")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestRevokeSession_UpdateOneError(t *testing.T) {
+	msm := setupMongoSessionRepository(t)
+	msm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(nil, assert.AnError)
+
+	err := msm.repo.RevokeSession(context.Context(nil), "1", "family")
+
+	assert.Error(t, err)
+}
+
+func TestRevokeUserSessions_OK(t *testing.T) {
+	msm := setupMongoSessionRepository(t)
+	msm.collection.On("UpdateMany", mock.IsType(nil), bson.M{"user_id": "1", "revoked_at": bson.M{"$exists": false}}, mock.AnythingOfType("bson.M")).Return(&mongo.UpdateResult{ModifiedCount: 2}, nil)
+
+	err := msm.repo.RevokeUserSessions(context.Context(nil), "1")
+
+	assert.NoError(t, err)
+}
+
+func TestRevokeUserSessions_UpdateManyError(t *testing.T) {
+	msm := setupMongoSessionRepository(t)
+	msm.collection.On("UpdateMany", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(nil, assert.AnError)
+
+	err := msm.repo.RevokeUserSessions(context.Context(nil), "1")
+
+	assert.Error(t, err)
+}
+
+func TestDeleteUserSessions_OK(t *testing.T) {
+	msm := setupMongoSessionRepository(t)
+	msm.collection.On("DeleteMany", mock.IsType(nil), bson.M{"user_id": "1"}).Return(&mongo.DeleteResult{DeletedCount: 2}, nil)
+
+	err := msm.repo.DeleteUserSessions(context.Context(nil), "1")
+
+	assert.NoError(t, err)
+}
+
+func TestDeleteUserSessions_DeleteManyError(t *testing.T) {
+	msm := setupMongoSessionRepository(t)
+	msm.collection.On("DeleteMany", mock.IsType(nil), mock.AnythingOfType("bson.M")).Return(nil, assert.AnError)
+
+	err := msm.repo.DeleteUserSessions(context.Context(nil), "1")
+
+	assert.Error(t, err)
+}
@@ -2,7 +2,12 @@ package infrastructure
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/christhianjesus/crabi-challenge/internal/domain"
@@ -14,9 +19,28 @@ import (
 type MongoUserRepository interface {
 	CreateUser(ctx context.Context, user *domain.User) error
 	GetIdAndHash(ctx context.Context, email string) (string, string, error)
+	GetPasswordHashes(ctx context.Context, userID string) ([]string, error)
 	GetUser(ctx context.Context, userID string) (*domain.User, error)
+	UpdateUser(ctx context.Context, user *domain.User) error
+	UpdatePassword(ctx context.Context, userID, hash string, historySize int) error
+	RehashPassword(ctx context.Context, userID, oldHash, newHash string) error
+	MarkEmailVerified(ctx context.Context, userID string) error
+	DeleteUser(ctx context.Context, userID string, releaseEmail bool) error
+	ListPurgeableUsers(ctx context.Context, deletedBefore time.Time, limit int) ([]*domain.User, error)
+	PurgeUser(ctx context.Context, userID string) error
+	AddRole(ctx context.Context, userID string, role domain.Role) error
+	RemoveRole(ctx context.Context, userID string, role domain.Role) error
+	SetScreeningStatus(ctx context.Context, user *domain.User, status domain.ScreeningStatus) error
+	ListUnscreenedUsers(ctx context.Context, limit int) ([]*domain.User, error)
+	ListUsers(ctx context.Context, query *domain.UserQuery) (*domain.UserPage, error)
+	CountUsers(ctx context.Context, filter *domain.UserFilter) (int64, error)
 }
 
+// matches the users that are not soft deleted
+var notDeleted = bson.M{"$exists": false}
+
+var ErrInvalidCursor = domain.NewError(domain.ErrInvalidInput, "Invalid cursor")
+
 type mongoUserRepository struct {
 	coll mongoCollection
 }
@@ -27,8 +51,27 @@ type mongoUser struct {
 	Password  string        `bson:"password"`
 	FirstName string        `bson:"first_name,omitempty"`
 	LastName  string        `bson:"last_name,omitempty"`
-	CreatedAt time.Time     `bson:"created_at"`
-	UpdatedAt time.Time     `bson:"updated_at"`
+	// lowercase copies, searched by prefix so their indexes can be used
+	FirstNameLower string        `bson:"first_name_lower,omitempty"`
+	LastNameLower  string        `bson:"last_name_lower,omitempty"`
+	EmailVerified  bool          `bson:"email_verified"`
+	Roles          []domain.Role `bson:"roles,omitempty"`
+	CreatedAt      time.Time     `bson:"created_at"`
+	UpdatedAt      time.Time     `bson:"updated_at"`
+	Version        int64         `bson:"version"`
+	DeletedAt      *time.Time    `bson:"deleted_at,omitempty"`
+	// the email of a deleted user once released, kept until the purge
+	DeletedEmail string `bson:"deleted_email,omitempty"`
+	// missing for users created before screening statuses
+	ScreeningStatus domain.ScreeningStatus `bson:"screening_status,omitempty"`
+	// previous hashes, newest first
+	PasswordHistory []string `bson:"password_history,omitempty"`
+}
+
+// Position of the last user of a page, in the sort order
+type userCursor struct {
+	ID    bson.ObjectID `json:"id"`
+	Email string        `json:"email,omitempty"`
 }
 
 // interface added for testing purposes
@@ -38,8 +81,32 @@ type mongoDatabase interface {
 
 // interface added for testing purposes
 type mongoCollection interface {
+	Find(ctx context.Context, filter interface{}, opts ...options.Lister[options.FindOptions]) (*mongo.Cursor, error)
 	FindOne(ctx context.Context, filter interface{}, opts ...options.Lister[options.FindOneOptions]) *mongo.SingleResult
+	FindOneAndDelete(ctx context.Context, filter interface{}, opts ...options.Lister[options.FindOneAndDeleteOptions]) *mongo.SingleResult
+	FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{}, opts ...options.Lister[options.FindOneAndUpdateOptions]) *mongo.SingleResult
 	InsertOne(ctx context.Context, document interface{}, opts ...options.Lister[options.InsertOneOptions]) (*mongo.InsertOneResult, error)
+	UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error)
+	UpdateMany(ctx context.Context, filter interface{}, update interface{}, opts ...options.Lister[options.UpdateManyOptions]) (*mongo.UpdateResult, error)
+	DeleteOne(ctx context.Context, filter interface{}, opts ...options.Lister[options.DeleteOneOptions]) (*mongo.DeleteResult, error)
+	DeleteMany(ctx context.Context, filter interface{}, opts ...options.Lister[options.DeleteManyOptions]) (*mongo.DeleteResult, error)
+	CountDocuments(ctx context.Context, filter interface{}, opts ...options.Lister[options.CountOptions]) (int64, error)
+}
+
+// Helper function to translate driver errors into domain errors
+func mongoError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return domain.ErrNotFound
+	case mongo.IsDuplicateKeyError(err):
+		return domain.ErrConflict
+	case mongo.IsTimeout(err), mongo.IsNetworkError(err):
+		return fmt.Errorf("%w: %w", domain.ErrUpstreamUnavailable, err)
+	}
+
+	return err
 }
 
 func NewMongoUserRepository(db mongoDatabase) MongoUserRepository {
@@ -49,18 +116,28 @@ func NewMongoUserRepository(db mongoDatabase) MongoUserRepository {
 func (r *mongoUserRepository) CreateUser(ctx context.Context, user *domain.User) error {
 	currentTime := time.Now()
 	mongoUser := &mongoUser{
-		ID:        bson.NewObjectIDFromTimestamp(currentTime),
-		Email:     user.Email,
-		Password:  user.Password,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		CreatedAt: currentTime,
-		UpdatedAt: currentTime,
+		ID:              bson.NewObjectIDFromTimestamp(currentTime),
+		Email:           user.Email,
+		Password:        user.Password,
+		FirstName:       user.FirstName,
+		LastName:        user.LastName,
+		FirstNameLower:  strings.ToLower(user.FirstName),
+		LastNameLower:   strings.ToLower(user.LastName),
+		Roles:           user.Roles,
+		CreatedAt:       currentTime,
+		ScreeningStatus: user.ScreeningStatus,
+		UpdatedAt:       currentTime,
+		Version:         1,
+	}
+
+	if _, err := r.coll.InsertOne(ctx, mongoUser); err != nil {
+		return mongoError(err)
 	}
 
-	_, err := r.coll.InsertOne(ctx, mongoUser)
+	user.ID = mongoUser.ID.Hex()
+	user.Version = mongoUser.Version
 
-	return err
+	return nil
 }
 
 func (r *mongoUserRepository) GetIdAndHash(ctx context.Context, email string) (string, string, error) {
@@ -68,39 +145,485 @@ func (r *mongoUserRepository) GetIdAndHash(ctx context.Context, email string) (s
 
 	var user mongoUser
 
-	err := r.coll.FindOne(ctx, bson.M{"email": email}, opts).Decode(&user)
+	err := r.coll.FindOne(ctx, bson.M{"email": email, "deleted_at": notDeleted}, opts).Decode(&user)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return "", "", errors.New("Not found")
-		}
-
-		return "", "", err
+		return "", "", mongoError(err)
 	}
 
 	return user.ID.Hex(), user.Password, nil
 }
 
+func (r *mongoUserRepository) GetPasswordHashes(ctx context.Context, userID string) ([]string, error) {
+	opts := options.FindOne().SetProjection(bson.M{"password": 1, "password_history": 1})
+	mongoID, _ := bson.ObjectIDFromHex(userID)
+
+	var user mongoUser
+
+	err := r.coll.FindOne(ctx, bson.M{"_id": mongoID, "deleted_at": notDeleted}, opts).Decode(&user)
+	if err != nil {
+		return nil, mongoError(err)
+	}
+
+	return append([]string{user.Password}, user.PasswordHistory...), nil
+}
+
 func (r *mongoUserRepository) GetUser(ctx context.Context, userID string) (*domain.User, error) {
-	opts := options.FindOne().SetProjection(bson.M{"password": 0})
+	opts := options.FindOne().SetProjection(bson.M{"password": 0, "password_history": 0})
 	mongoID, _ := bson.ObjectIDFromHex(userID)
 
 	var user mongoUser
 
-	err := r.coll.FindOne(ctx, bson.M{"_id": mongoID}, opts).Decode(&user)
+	err := r.coll.FindOne(ctx, bson.M{"_id": mongoID, "deleted_at": notDeleted}, opts).Decode(&user)
+	if err != nil {
+		return nil, mongoError(err)
+	}
+
+	return user.toDomain(), nil
+}
+
+func (r *mongoUserRepository) UpdateUser(ctx context.Context, user *domain.User) error {
+	mongoID, _ := bson.ObjectIDFromHex(user.ID)
+	filter := bson.M{"_id": mongoID, "deleted_at": notDeleted, "version": versionFilter(user.Version)}
+	updatedAt := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"first_name":       user.FirstName,
+			"last_name":        user.LastName,
+			"first_name_lower": strings.ToLower(user.FirstName),
+			"last_name_lower":  strings.ToLower(user.LastName),
+			"updated_at":       updatedAt,
+		},
+		"$inc": bson.M{"version": 1},
+	}
+
+	res, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return mongoError(err)
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+
+	user.UpdatedAt = updatedAt
+	user.Version++
+
+	return nil
+}
+
+// Pipeline update, so the hash being replaced is pushed to the history in the same write
+func (r *mongoUserRepository) UpdatePassword(ctx context.Context, userID, hash string, historySize int) error {
+	mongoID, _ := bson.ObjectIDFromHex(userID)
+
+	history := interface{}(bson.A{})
+	if historySize > 0 {
+		previous := bson.M{"$concatArrays": bson.A{bson.A{"$password"}, bson.M{"$ifNull": bson.A{"$password_history", bson.A{}}}}}
+		history = bson.M{"$slice": bson.A{previous, historySize}}
+	}
+
+	// bcrypt hashes start with "$", which a pipeline would read as a field path
+	update := bson.A{bson.M{"$set": bson.M{
+		"password":         bson.M{"$literal": hash},
+		"password_history": history,
+		"updated_at":       time.Now(),
+		"version":          bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$version", 0}}, 1}},
+	}}}
+
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": mongoID, "deleted_at": notDeleted}, update)
+	if err != nil {
+		return mongoError(err)
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// Neither the history nor the version change, the password is the same
+func (r *mongoUserRepository) RehashPassword(ctx context.Context, userID, oldHash, newHash string) error {
+	mongoID, _ := bson.ObjectIDFromHex(userID)
+	filter := bson.M{"_id": mongoID, "password": oldHash, "deleted_at": notDeleted}
+
+	res, err := r.coll.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"password": newHash}})
+	if err != nil {
+		return mongoError(err)
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *mongoUserRepository) MarkEmailVerified(ctx context.Context, userID string) error {
+	mongoID, _ := bson.ObjectIDFromHex(userID)
+	update := bson.M{"$set": bson.M{"email_verified": true, "updated_at": time.Now()}, "$inc": bson.M{"version": 1}}
+
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": mongoID, "deleted_at": notDeleted}, update)
+	if err != nil {
+		return mongoError(err)
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// The email is moved out of the unique index when released, the purge removes it anyway
+func (r *mongoUserRepository) DeleteUser(ctx context.Context, userID string, releaseEmail bool) error {
+	mongoID, _ := bson.ObjectIDFromHex(userID)
+	now := time.Now()
+	update := bson.A{bson.M{"$set": bson.M{
+		"deleted_at": now,
+		"updated_at": now,
+		"version":    bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$version", 0}}, 1}},
+	}}}
+
+	if releaseEmail {
+		update = append(update, bson.M{"$set": bson.M{"deleted_email": "$email"}}, bson.M{"$unset": "email"})
+	}
+
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": mongoID, "deleted_at": notDeleted}, update)
+	if err != nil {
+		return mongoError(err)
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// The released email is returned as the email, the purge has to find what it was used for
+func (r *mongoUserRepository) ListPurgeableUsers(ctx context.Context, deletedBefore time.Time, limit int) ([]*domain.User, error) {
+	filter := bson.M{"deleted_at": bson.M{"$lte": deletedBefore}, "purged_at": bson.M{"$exists": false}}
+	opts := options.Find().
+		SetProjection(bson.M{"password": 0, "password_history": 0}).
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	res, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, mongoError(err)
+	}
+
+	var users []mongoUser
+	if err = res.All(ctx, &users); err != nil {
+		return nil, mongoError(err)
+	}
+
+	result := make([]*domain.User, 0, len(users))
+	for _, user := range users {
+		if user.Email == "" {
+			user.Email = user.DeletedEmail
+		}
+
+		result = append(result, user.toDomain())
+	}
+
+	return result, nil
+}
+
+// Only the id and the dates are kept, enough to prove the account existed
+func (r *mongoUserRepository) PurgeUser(ctx context.Context, userID string) error {
+	mongoID, _ := bson.ObjectIDFromHex(userID)
+	filter := bson.M{"_id": mongoID, "deleted_at": bson.M{"$exists": true}, "purged_at": bson.M{"$exists": false}}
+	update := bson.M{
+		"$set":   bson.M{"purged_at": time.Now()},
+		"$unset": bson.M{"email": "", "deleted_email": "", "password": "", "password_history": "", "first_name": "", "last_name": "", "first_name_lower": "", "last_name_lower": ""},
+	}
+
+	res, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return mongoError(err)
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *mongoUserRepository) SetScreeningStatus(ctx context.Context, user *domain.User, status domain.ScreeningStatus) error {
+	mongoID, _ := bson.ObjectIDFromHex(user.ID)
+	filter := bson.M{"_id": mongoID, "deleted_at": notDeleted, "version": versionFilter(user.Version)}
+	updatedAt := time.Now()
+	update := bson.M{"$set": bson.M{"screening_status": status, "updated_at": updatedAt}, "$inc": bson.M{"version": 1}}
+
+	res, err := r.coll.UpdateOne(ctx, filter, update)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, errors.New("Not found")
+		return mongoError(err)
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+
+	user.UpdatedAt = updatedAt
+	user.Version++
+
+	return nil
+}
+
+func (r *mongoUserRepository) ListUnscreenedUsers(ctx context.Context, limit int) ([]*domain.User, error) {
+	filter := bson.M{
+		"screening_status": bson.M{"$in": bson.A{domain.ScreeningFlagged, domain.ScreeningPending}},
+		"deleted_at":       notDeleted,
+	}
+	opts := options.Find().
+		SetProjection(bson.M{"password": 0, "password_history": 0}).
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	res, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, mongoError(err)
+	}
+
+	var users []mongoUser
+	if err = res.All(ctx, &users); err != nil {
+		return nil, mongoError(err)
+	}
+
+	result := make([]*domain.User, 0, len(users))
+	for _, user := range users {
+		result = append(result, user.toDomain())
+	}
+
+	return result, nil
+}
+
+func (r *mongoUserRepository) AddRole(ctx context.Context, userID string, role domain.Role) error {
+	return r.updateRoles(ctx, userID, bson.M{"$addToSet": bson.M{"roles": role}})
+}
+
+func (r *mongoUserRepository) RemoveRole(ctx context.Context, userID string, role domain.Role) error {
+	return r.updateRoles(ctx, userID, bson.M{"$pull": bson.M{"roles": role}})
+}
+
+func (r *mongoUserRepository) updateRoles(ctx context.Context, userID string, update bson.M) error {
+	mongoID, _ := bson.ObjectIDFromHex(userID)
+	update["$set"] = bson.M{"updated_at": time.Now()}
+	update["$inc"] = bson.M{"version": 1}
+
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": mongoID, "deleted_at": notDeleted}, update)
+	if err != nil {
+		return mongoError(err)
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// Pages are fetched with one extra user, to know if there is a next page
+func (r *mongoUserRepository) ListUsers(ctx context.Context, query *domain.UserQuery) (*domain.UserPage, error) {
+	filter := userFilter(&query.Filter)
+
+	sortField := "_id"
+	if query.Sort == domain.SortByEmail {
+		sortField = "email"
+		filter["email"] = withEmail(filter["email"])
+	}
+
+	direction, op := 1, "$gt"
+	if query.Descending {
+		direction, op = -1, "$lt"
+	}
+
+	if query.Cursor != "" {
+		cursor, err := decodeCursor(query.Cursor)
+		if err != nil {
+			return nil, err
 		}
 
-		return nil, err
+		after := bson.M{"_id": bson.M{op: cursor.ID}}
+		if sortField == "email" {
+			after = bson.M{"$or": bson.A{
+				bson.M{"email": bson.M{op: cursor.Email}},
+				bson.M{"email": cursor.Email, "_id": bson.M{op: cursor.ID}},
+			}}
+		}
+
+		filter["$and"] = bson.A{after}
+	}
+
+	sort := bson.D{{Key: "_id", Value: direction}}
+	if sortField != "_id" {
+		sort = bson.D{{Key: sortField, Value: direction}, {Key: "_id", Value: direction}}
+	}
+
+	opts := options.Find().
+		SetProjection(bson.M{"password": 0, "password_history": 0}).
+		SetSort(sort).
+		SetLimit(int64(query.Limit + 1))
+
+	res, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, mongoError(err)
+	}
+
+	var users []mongoUser
+	if err = res.All(ctx, &users); err != nil {
+		return nil, mongoError(err)
+	}
+
+	page := &domain.UserPage{Users: make([]*domain.User, 0, len(users))}
+
+	if len(users) > query.Limit {
+		users = users[:query.Limit]
+		last := users[len(users)-1]
+		page.NextCursor = encodeCursor(&userCursor{ID: last.ID, Email: last.Email})
+	}
+
+	for _, user := range users {
+		page.Users = append(page.Users, user.toDomain())
+	}
+
+	return page, nil
+}
+
+func (r *mongoUserRepository) CountUsers(ctx context.Context, filter *domain.UserFilter) (int64, error) {
+	total, err := r.coll.CountDocuments(ctx, userFilter(filter))
+	if err != nil {
+		return 0, mongoError(err)
+	}
+
+	return total, nil
+}
+
+func (u *mongoUser) toDomain() *domain.User {
+	// users created before roles existed
+	roles := u.Roles
+	if len(roles) == 0 {
+		roles = []domain.Role{domain.RoleUser}
+	}
+
+	// users created before screening statuses were always screened first
+	screening := u.ScreeningStatus
+	if screening == "" {
+		screening = domain.ScreeningPassed
 	}
 
 	return &domain.User{
-		ID:        user.ID.Hex(),
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-	}, nil
+		ID:              u.ID.Hex(),
+		Email:           u.Email,
+		FirstName:       u.FirstName,
+		LastName:        u.LastName,
+		EmailVerified:   u.EmailVerified,
+		Roles:           roles,
+		CreatedAt:       u.CreatedAt,
+		UpdatedAt:       u.UpdatedAt,
+		DeletedAt:       u.DeletedAt,
+		ScreeningStatus: screening,
+		Version:         u.Version,
+	}
+}
+
+// Helper function to translate a directory filter into a mongo query
+func userFilter(f *domain.UserFilter) bson.M {
+	filter := bson.M{}
+
+	// anchored and case sensitive, so the email index can be used
+	if f.EmailPrefix != "" {
+		filter["email"] = withEmail(bson.M{"$regex": "^" + regexp.QuoteMeta(f.EmailPrefix)})
+	}
+
+	// case insensitive through the lowercase copies, anchored so their indexes can be used
+	if f.Name != "" {
+		name := bson.M{"$regex": "^" + regexp.QuoteMeta(strings.ToLower(f.Name))}
+		filter["$or"] = bson.A{bson.M{"first_name_lower": name}, bson.M{"last_name_lower": name}}
+	}
+
+	switch f.Status {
+	case domain.ActiveUser:
+		filter["email_verified"] = true
+		filter["deleted_at"] = notDeleted
+	case domain.UnverifiedUser:
+		filter["email_verified"] = bson.M{"$ne": true}
+		filter["deleted_at"] = notDeleted
+	case domain.DeletedUser:
+		filter["deleted_at"] = bson.M{"$exists": true}
+	default:
+		filter["deleted_at"] = notDeleted
+	}
+
+	if len(f.ScreeningStatuses) > 0 {
+		statuses := bson.A{}
+		for _, status := range f.ScreeningStatuses {
+			statuses = append(statuses, status)
+
+			// users created before the screening status existed have passed
+			if status == domain.ScreeningPassed {
+				statuses = append(statuses, nil)
+			}
+		}
+
+		filter["screening_status"] = bson.M{"$in": statuses}
+	}
+
+	created := bson.M{}
+	if !f.CreatedAfter.IsZero() {
+		created["$gte"] = f.CreatedAfter
+	}
+
+	if !f.CreatedBefore.IsZero() {
+		created["$lt"] = f.CreatedBefore
+	}
+
+	if len(created) > 0 {
+		filter["created_at"] = created
+	}
+
+	return filter
+}
+
+// The email index only holds users with an email, queries must say so to use it
+func withEmail(condition interface{}) bson.M {
+	email, _ := condition.(bson.M)
+	if email == nil {
+		email = bson.M{}
+	}
+
+	email["$exists"] = true
+
+	return email
+}
+
+func encodeCursor(cursor *userCursor) string {
+	data, _ := json.Marshal(cursor)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(value string) (*userCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor userCursor
+	if err = json.Unmarshal(data, &cursor); err != nil || cursor.ID.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+// Users created before versioning have no version field
+func versionFilter(version int64) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+
+	return version
 }
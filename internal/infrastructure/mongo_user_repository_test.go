@@ -39,6 +39,19 @@ func TestNewMongoUserRepository_OK(t *testing.T) {
 	assert.Equal(t, mongoColl, mur.(*mongoUserRepository).coll)
 }
 
+func TestCreate_LowercaseNames(t *testing.T) {
+	lowercase := func(user *mongoUser) bool {
+		return user.FirstNameLower == "chris" && user.LastNameLower == "ávila"
+	}
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("InsertOne", mock.IsType(nil), mock.MatchedBy(lowercase)).Return(&mongo.InsertOneResult{}, nil)
+
+	err := murm.repo.CreateUser(context.Context(nil), &domain.User{FirstName: "Chris", LastName: "Ávila"})
+
+	assert.NoError(t, err)
+}
+
 func TestCreate_InsertOneError(t *testing.T) {
 	murm := setupMongoUserRepository(t)
 	murm.collection.On("InsertOne", mock.IsType(nil), mock.AnythingOfType("*infrastructure.mongoUser")).Return(nil, assert.AnError)
@@ -48,13 +61,37 @@ func TestCreate_InsertOneError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestCreate_InsertOneDuplicateKey(t *testing.T) {
+	duplicate := mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000, Message: "E11000 duplicate key error"}}}
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("InsertOne", mock.IsType(nil), mock.AnythingOfType("*infrastructure.mongoUser")).Return(nil, duplicate)
+
+	err := murm.repo.CreateUser(context.Context(nil), &domain.User{})
+
+	assert.ErrorIs(t, err, domain.ErrConflict)
+}
+
+func TestMongoError(t *testing.T) {
+	timeout := mongo.CommandError{Labels: []string{"NetworkTimeoutError"}}
+
+	assert.NoError(t, mongoError(nil))
+	assert.ErrorIs(t, mongoError(mongo.ErrNoDocuments), domain.ErrNotFound)
+	assert.ErrorIs(t, mongoError(context.DeadlineExceeded), domain.ErrUpstreamUnavailable)
+	assert.ErrorIs(t, mongoError(timeout), domain.ErrUpstreamUnavailable)
+	assert.Equal(t, assert.AnError, mongoError(assert.AnError))
+}
+
 func TestCreate_InsertOneOK(t *testing.T) {
 	murm := setupMongoUserRepository(t)
 	murm.collection.On("InsertOne", mock.IsType(nil), mock.AnythingOfType("*infrastructure.mongoUser")).Return(nil, nil)
 
-	err := murm.repo.CreateUser(context.Context(nil), &domain.User{})
+	user := &domain.User{}
+	err := murm.repo.CreateUser(context.Context(nil), user)
 
 	assert.NoError(t, err)
+	assert.NotEmpty(t, user.ID)
+	assert.Equal(t, int64(1), user.Version)
 }
 
 func TestGetIdAndHash_FindOneError(t *testing.T) {
@@ -100,6 +137,42 @@ func TestGetIdAndHash_FindOneOK(t *testing.T) {
 	assert.Equal(t, password, hash)
 }
 
+func TestGetPasswordHashes_OK(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(bson.M{"_id": bson.NewObjectID(), "password": "current", "password_history": bson.A{"previous", "oldest"}}, nil, nil)
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("FindOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.FindOneOptionsBuilder")).Return(res)
+
+	hashes, err := murm.repo.GetPasswordHashes(context.Context(nil), "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"current", "previous", "oldest"}, hashes)
+}
+
+func TestGetPasswordHashes_NoHistory(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(bson.M{"_id": bson.NewObjectID(), "password": "current"}, nil, nil)
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("FindOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.FindOneOptionsBuilder")).Return(res)
+
+	hashes, err := murm.repo.GetPasswordHashes(context.Context(nil), "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"current"}, hashes)
+}
+
+func TestGetPasswordHashes_FindOneErrorNoDocuments(t *testing.T) {
+	res := mongo.NewSingleResultFromDocument(bson.M{}, mongo.ErrNoDocuments, nil)
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("FindOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.FindOneOptionsBuilder")).Return(res)
+
+	hashes, err := murm.repo.GetPasswordHashes(context.Context(nil), "")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Nil(t, hashes)
+}
+
 func TestGet_FindOneError(t *testing.T) {
 	res := mongo.NewSingleResultFromDocument(nil, nil, nil)
 
@@ -129,7 +202,7 @@ func TestGet_FindOneErrorNoDocuments(t *testing.T) {
 func TestGet_FindOneOK(t *testing.T) {
 	now := time.Now()
 	mongoID := bson.NewObjectIDFromTimestamp(now)
-	res := mongo.NewSingleResultFromDocument(bson.M{"_id": mongoID, "password": "XXXXXXXXXXXXX", "created_at": now}, nil, nil)
+	res := mongo.NewSingleResultFromDocument(bson.M{"_id": mongoID, "password": "XXXXXXXXXXXXX", "email_verified": true, "created_at": now, "version": int64(2)}, nil, nil)
 
 	murm := setupMongoUserRepository(t)
 	murm.collection.On("FindOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.FindOneOptionsBuilder")).Return(res)
@@ -140,5 +213,547 @@ func TestGet_FindOneOK(t *testing.T) {
 	assert.NotNil(t, user)
 	assert.Equal(t, mongoID.Hex(), user.ID)
 	assert.Equal(t, now.UTC().Truncate(time.Millisecond), user.CreatedAt)
+	assert.True(t, user.EmailVerified)
+	assert.Equal(t, int64(2), user.Version)
+	assert.Equal(t, []domain.Role{domain.RoleUser}, user.Roles)
+	assert.Equal(t, domain.ScreeningPassed, user.ScreeningStatus)
 	assert.Empty(t, user.Password)
 }
+
+func TestUpdatePassword_OK(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.A")).Return(&mongo.UpdateResult{MatchedCount: 1}, nil)
+
+	err := murm.repo.UpdatePassword(context.Context(nil), bson.NewObjectID().Hex(), "hash", 4)
+
+	assert.NoError(t, err)
+}
+
+func TestUpdatePassword_KeepsHistory(t *testing.T) {
+	checkUpdate := func(update bson.A) bool {
+		set := update[0].(bson.M)["$set"].(bson.M)
+		history := set["password_history"].(bson.M)["$slice"].(bson.A)
+		return set["password"].(bson.M)["$literal"] == "$2a$10$hash" && history[1] == 4
+	}
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.MatchedBy(checkUpdate)).Return(&mongo.UpdateResult{MatchedCount: 1}, nil)
+
+	err := murm.repo.UpdatePassword(context.Context(nil), bson.NewObjectID().Hex(), "$2a$10$hash", 4)
+
+	assert.NoError(t, err)
+}
+
+func TestUpdatePassword_NoHistory(t *testing.T) {
+	checkUpdate := func(update bson.A) bool {
+		return len(update[0].(bson.M)["$set"].(bson.M)["password_history"].(bson.A)) == 0
+	}
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.MatchedBy(checkUpdate)).Return(&mongo.UpdateResult{MatchedCount: 1}, nil)
+
+	err := murm.repo.UpdatePassword(context.Context(nil), bson.NewObjectID().Hex(), "hash", 0)
+
+	assert.NoError(t, err)
+}
+
+func TestUpdatePassword_NotFound(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.A")).Return(&mongo.UpdateResult{}, nil)
+
+	err := murm.repo.UpdatePassword(context.Context(nil), "", "hash", 4)
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestUpdatePassword_UpdateOneError(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.A")).Return(nil, assert.AnError)
+
+	err := murm.repo.UpdatePassword(context.Context(nil), "", "hash", 4)
+
+	assert.Error(t, err)
+}
+
+func TestRehashPassword_OK(t *testing.T) {
+	checkFilter := func(filter bson.M) bool {
+		return filter["password"] == "$2a$10$hash"
+	}
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.MatchedBy(checkFilter), bson.M{"$set": bson.M{"password": "$argon2id$hash"}}).Return(&mongo.UpdateResult{MatchedCount: 1}, nil)
+
+	err := murm.repo.RehashPassword(context.Context(nil), bson.NewObjectID().Hex(), "$2a$10$hash", "$argon2id$hash")
+
+	assert.NoError(t, err)
+}
+
+func TestRehashPassword_NotFound(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(&mongo.UpdateResult{}, nil)
+
+	err := murm.repo.RehashPassword(context.Context(nil), "", "old", "new")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestRehashPassword_UpdateOneError(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(nil, assert.AnError)
+
+	err := murm.repo.RehashPassword(context.Context(nil), "", "old", "new")
+
+	assert.Error(t, err)
+}
+
+func TestMarkEmailVerified_OK(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(&mongo.UpdateResult{MatchedCount: 1}, nil)
+
+	err := murm.repo.MarkEmailVerified(context.Context(nil), bson.NewObjectID().Hex())
+
+	assert.NoError(t, err)
+}
+
+func TestMarkEmailVerified_NotFound(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(&mongo.UpdateResult{}, nil)
+
+	err := murm.repo.MarkEmailVerified(context.Context(nil), "")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestMarkEmailVerified_UpdateOneError(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(nil, assert.AnError)
+
+	err := murm.repo.MarkEmailVerified(context.Context(nil), "")
+
+	assert.Error(t, err)
+}
+
+func TestSetScreeningStatus_OK(t *testing.T) {
+	mongoID := bson.NewObjectID()
+	sets := func(update bson.M) bool {
+		return update["$set"].(bson.M)["screening_status"] == domain.ScreeningPassed
+	}
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), bson.M{"_id": mongoID, "deleted_at": bson.M{"$exists": false}, "version": int64(3)}, mock.MatchedBy(sets)).Return(&mongo.UpdateResult{MatchedCount: 1}, nil)
+
+	user := &domain.User{ID: mongoID.Hex(), Version: 3}
+	err := murm.repo.SetScreeningStatus(context.Context(nil), user, domain.ScreeningPassed)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), user.Version)
+	assert.False(t, user.UpdatedAt.IsZero())
+}
+
+func TestSetScreeningStatus_NotFound(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(&mongo.UpdateResult{}, nil)
+
+	err := murm.repo.SetScreeningStatus(context.Context(nil), &domain.User{}, domain.ScreeningPassed)
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestSetScreeningStatus_UpdateOneError(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(nil, assert.AnError)
+
+	err := murm.repo.SetScreeningStatus(context.Context(nil), &domain.User{}, domain.ScreeningPassed)
+
+	assert.Error(t, err)
+}
+
+func TestListUnscreenedUsers_OK(t *testing.T) {
+	mongoID := bson.NewObjectID()
+	res, _ := mongo.NewCursorFromDocuments([]interface{}{
+		bson.M{"_id": mongoID, "email": "a@email.com", "screening_status": domain.ScreeningPending},
+	}, nil, nil)
+	filter := bson.M{
+		"screening_status": bson.M{"$in": bson.A{domain.ScreeningFlagged, domain.ScreeningPending}},
+		"deleted_at":       bson.M{"$exists": false},
+	}
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("Find", mock.IsType(nil), filter, mock.AnythingOfType("*options.FindOptionsBuilder")).Return(res, nil)
+
+	users, err := murm.repo.ListUnscreenedUsers(context.Context(nil), 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, mongoID.Hex(), users[0].ID)
+	assert.Equal(t, domain.ScreeningPending, users[0].ScreeningStatus)
+}
+
+func TestListUnscreenedUsers_FindError(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("Find", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.FindOptionsBuilder")).Return(nil, assert.AnError)
+
+	users, err := murm.repo.ListUnscreenedUsers(context.Context(nil), 10)
+
+	assert.Error(t, err)
+	assert.Nil(t, users)
+}
+
+func TestUpdate_UpdateOneOK(t *testing.T) {
+	mongoID := bson.NewObjectID()
+	filter := bson.M{"_id": mongoID, "deleted_at": bson.M{"$exists": false}, "version": int64(3)}
+
+	lowercase := func(update bson.M) bool {
+		return update["$set"].(bson.M)["first_name_lower"] == "newname"
+	}
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), filter, mock.MatchedBy(lowercase)).Return(&mongo.UpdateResult{MatchedCount: 1}, nil)
+
+	user := &domain.User{ID: mongoID.Hex(), FirstName: "Newname", Version: 3}
+	err := murm.repo.UpdateUser(context.Context(nil), user)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), user.Version)
+	assert.False(t, user.UpdatedAt.IsZero())
+}
+
+func TestUpdate_Unversioned(t *testing.T) {
+	mongoID := bson.NewObjectID()
+	filter := bson.M{"_id": mongoID, "deleted_at": bson.M{"$exists": false}, "version": bson.M{"$in": bson.A{0, nil}}}
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), filter, mock.AnythingOfType("bson.M")).Return(&mongo.UpdateResult{MatchedCount: 1}, nil)
+
+	err := murm.repo.UpdateUser(context.Context(nil), &domain.User{ID: mongoID.Hex()})
+
+	assert.NoError(t, err)
+}
+
+func TestUpdate_VersionMismatch(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(&mongo.UpdateResult{}, nil)
+
+	user := &domain.User{Version: 3}
+	err := murm.repo.UpdateUser(context.Context(nil), user)
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Equal(t, int64(3), user.Version)
+}
+
+func TestUpdate_UpdateOneError(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(nil, assert.AnError)
+
+	err := murm.repo.UpdateUser(context.Context(nil), &domain.User{})
+
+	assert.Error(t, err)
+}
+
+func TestDelete_ReleaseEmail(t *testing.T) {
+	mongoID := bson.NewObjectID()
+	filter := bson.M{"_id": mongoID, "deleted_at": bson.M{"$exists": false}}
+	releases := func(update bson.A) bool {
+		return len(update) == 3 && update[2].(bson.M)["$unset"] == "email"
+	}
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), filter, mock.MatchedBy(releases)).Return(&mongo.UpdateResult{MatchedCount: 1}, nil)
+
+	err := murm.repo.DeleteUser(context.Context(nil), mongoID.Hex(), true)
+
+	assert.NoError(t, err)
+}
+
+func TestDelete_RetainEmail(t *testing.T) {
+	retains := func(update bson.A) bool {
+		return len(update) == 1
+	}
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.MatchedBy(retains)).Return(&mongo.UpdateResult{MatchedCount: 1}, nil)
+
+	err := murm.repo.DeleteUser(context.Context(nil), bson.NewObjectID().Hex(), false)
+
+	assert.NoError(t, err)
+}
+
+func TestDelete_NotFound(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.A")).Return(&mongo.UpdateResult{}, nil)
+
+	err := murm.repo.DeleteUser(context.Context(nil), "", true)
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestDelete_UpdateOneError(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.A")).Return(nil, assert.AnError)
+
+	err := murm.repo.DeleteUser(context.Context(nil), "", true)
+
+	assert.Error(t, err)
+}
+
+func TestListPurgeableUsers_OK(t *testing.T) {
+	deletedBefore := time.Now()
+	filter := bson.M{"deleted_at": bson.M{"$lte": deletedBefore}, "purged_at": bson.M{"$exists": false}}
+	first, second := bson.NewObjectID(), bson.NewObjectID()
+	res, _ := mongo.NewCursorFromDocuments([]interface{}{
+		bson.M{"_id": first, "email": "retained@email.com"},
+		bson.M{"_id": second, "deleted_email": "released@email.com"},
+	}, nil, nil)
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("Find", mock.IsType(nil), filter, mock.AnythingOfType("*options.FindOptionsBuilder")).Return(res, nil)
+
+	users, err := murm.repo.ListPurgeableUsers(context.Context(nil), deletedBefore, 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, "retained@email.com", users[0].Email)
+	assert.Equal(t, second.Hex(), users[1].ID)
+	assert.Equal(t, "released@email.com", users[1].Email)
+}
+
+func TestListPurgeableUsers_FindError(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("Find", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.FindOptionsBuilder")).Return(nil, assert.AnError)
+
+	users, err := murm.repo.ListPurgeableUsers(context.Context(nil), time.Now(), 10)
+
+	assert.Error(t, err)
+	assert.Nil(t, users)
+}
+
+func TestPurgeUser_OK(t *testing.T) {
+	mongoID := bson.NewObjectID()
+	filter := bson.M{"_id": mongoID, "deleted_at": bson.M{"$exists": true}, "purged_at": bson.M{"$exists": false}}
+	unset := bson.M{"email": "", "deleted_email": "", "password": "", "password_history": "", "first_name": "", "last_name": "", "first_name_lower": "", "last_name_lower": ""}
+	update := mock.MatchedBy(func(update bson.M) bool {
+		return assert.ObjectsAreEqual(unset, update["$unset"])
+	})
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), filter, update).Return(&mongo.UpdateResult{MatchedCount: 1}, nil)
+
+	err := murm.repo.PurgeUser(context.Context(nil), mongoID.Hex())
+
+	assert.NoError(t, err)
+}
+
+func TestPurgeUser_NotFound(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(&mongo.UpdateResult{}, nil)
+
+	err := murm.repo.PurgeUser(context.Context(nil), bson.NewObjectID().Hex())
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestPurgeUser_UpdateOneError(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(nil, assert.AnError)
+
+	err := murm.repo.PurgeUser(context.Context(nil), bson.NewObjectID().Hex())
+
+	assert.Error(t, err)
+}
+
+func TestListUsers_FindOK(t *testing.T) {
+	first, second, third := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+	res, _ := mongo.NewCursorFromDocuments([]interface{}{
+		bson.M{"_id": first, "email": "a@email.com"},
+		bson.M{"_id": second, "email": "b@email.com"},
+		bson.M{"_id": third, "email": "c@email.com"},
+	}, nil, nil)
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("Find", mock.IsType(nil), bson.M{"deleted_at": bson.M{"$exists": false}}, mock.AnythingOfType("*options.FindOptionsBuilder")).Return(res, nil)
+
+	page, err := murm.repo.ListUsers(context.Context(nil), &domain.UserQuery{Limit: 2})
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Users, 2)
+	assert.Equal(t, second.Hex(), page.Users[1].ID)
+
+	cursor, err := decodeCursor(page.NextCursor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, second, cursor.ID)
+	assert.Equal(t, "b@email.com", cursor.Email)
+}
+
+func TestListUsers_LastPage(t *testing.T) {
+	res, _ := mongo.NewCursorFromDocuments([]interface{}{bson.M{"_id": bson.NewObjectID()}}, nil, nil)
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("Find", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.FindOptionsBuilder")).Return(res, nil)
+
+	page, err := murm.repo.ListUsers(context.Context(nil), &domain.UserQuery{Limit: 2})
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Users, 1)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestListUsers_CursorByID(t *testing.T) {
+	mongoID := bson.NewObjectID()
+	filter := bson.M{"deleted_at": bson.M{"$exists": false}, "$and": bson.A{bson.M{"_id": bson.M{"$lt": mongoID}}}}
+	res, _ := mongo.NewCursorFromDocuments(nil, nil, nil)
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("Find", mock.IsType(nil), filter, mock.AnythingOfType("*options.FindOptionsBuilder")).Return(res, nil)
+
+	cursor := encodeCursor(&userCursor{ID: mongoID})
+	page, err := murm.repo.ListUsers(context.Context(nil), &domain.UserQuery{Descending: true, Cursor: cursor, Limit: 2})
+
+	assert.NoError(t, err)
+	assert.Empty(t, page.Users)
+}
+
+func TestListUsers_CursorByEmail(t *testing.T) {
+	mongoID := bson.NewObjectID()
+	filter := bson.M{
+		"email":      bson.M{"$exists": true},
+		"deleted_at": bson.M{"$exists": false},
+		"$and": bson.A{bson.M{"$or": bson.A{
+			bson.M{"email": bson.M{"$gt": "b@email.com"}},
+			bson.M{"email": "b@email.com", "_id": bson.M{"$gt": mongoID}},
+		}}},
+	}
+	res, _ := mongo.NewCursorFromDocuments(nil, nil, nil)
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("Find", mock.IsType(nil), filter, mock.AnythingOfType("*options.FindOptionsBuilder")).Return(res, nil)
+
+	cursor := encodeCursor(&userCursor{ID: mongoID, Email: "b@email.com"})
+	_, err := murm.repo.ListUsers(context.Context(nil), &domain.UserQuery{Sort: domain.SortByEmail, Cursor: cursor, Limit: 2})
+
+	assert.NoError(t, err)
+}
+
+func TestListUsers_InvalidCursor(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+
+	page, err := murm.repo.ListUsers(context.Context(nil), &domain.UserQuery{Cursor: "not a cursor", Limit: 2})
+
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	assert.Nil(t, page)
+}
+
+func TestListUsers_FindError(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("Find", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("*options.FindOptionsBuilder")).Return(nil, assert.AnError)
+
+	page, err := murm.repo.ListUsers(context.Context(nil), &domain.UserQuery{Limit: 2})
+
+	assert.Error(t, err)
+	assert.Nil(t, page)
+}
+
+func TestCountUsers_OK(t *testing.T) {
+	filter := bson.M{"email_verified": true, "deleted_at": bson.M{"$exists": false}}
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("CountDocuments", mock.IsType(nil), filter).Return(int64(3), nil)
+
+	total, err := murm.repo.CountUsers(context.Context(nil), &domain.UserFilter{Status: domain.ActiveUser})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+}
+
+func TestCountUsers_Error(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("CountDocuments", mock.IsType(nil), mock.AnythingOfType("bson.M")).Return(int64(0), assert.AnError)
+
+	total, err := murm.repo.CountUsers(context.Context(nil), &domain.UserFilter{})
+
+	assert.Error(t, err)
+	assert.Zero(t, total)
+}
+
+func TestUserFilter(t *testing.T) {
+	after := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := after.AddDate(0, 1, 0)
+
+	filter := userFilter(&domain.UserFilter{
+		EmailPrefix:   "an.email+",
+		Name:          "Chris.",
+		Status:        domain.DeletedUser,
+		CreatedAfter:  after,
+		CreatedBefore: before,
+	})
+
+	assert.Equal(t, bson.M{
+		"email": bson.M{"$regex": `^an\.email\+`, "$exists": true},
+		"$or": bson.A{
+			bson.M{"first_name_lower": bson.M{"$regex": `^chris\.`}},
+			bson.M{"last_name_lower": bson.M{"$regex": `^chris\.`}},
+		},
+		"deleted_at": bson.M{"$exists": true},
+		"created_at": bson.M{"$gte": after, "$lt": before},
+	}, filter)
+	assert.Equal(t, bson.M{"email_verified": bson.M{"$ne": true}, "deleted_at": bson.M{"$exists": false}}, userFilter(&domain.UserFilter{Status: domain.UnverifiedUser}))
+}
+
+func TestUserFilter_NoStatus(t *testing.T) {
+	// deleted users are only listed when asked for
+	assert.Equal(t, bson.M{"deleted_at": bson.M{"$exists": false}}, userFilter(&domain.UserFilter{}))
+}
+
+func TestUserFilter_ScreeningStatus(t *testing.T) {
+	filter := userFilter(&domain.UserFilter{ScreeningStatuses: []domain.ScreeningStatus{domain.ScreeningPendingReview, domain.ScreeningPossibleMatch}})
+	assert.Equal(t, bson.M{"screening_status": bson.M{"$in": bson.A{domain.ScreeningPendingReview, domain.ScreeningPossibleMatch}}, "deleted_at": bson.M{"$exists": false}}, filter)
+
+	filter = userFilter(&domain.UserFilter{ScreeningStatuses: []domain.ScreeningStatus{domain.ScreeningPassed}, Status: domain.DeletedUser})
+	assert.Equal(t, bson.M{"screening_status": bson.M{"$in": bson.A{domain.ScreeningPassed, nil}}, "deleted_at": bson.M{"$exists": true}}, filter)
+}
+
+func TestAddRole_OK(t *testing.T) {
+	mongoID := bson.NewObjectID()
+	grants := func(update bson.M) bool {
+		return update["$addToSet"].(bson.M)["roles"] == domain.RoleSupport
+	}
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), bson.M{"_id": mongoID, "deleted_at": bson.M{"$exists": false}}, mock.MatchedBy(grants)).Return(&mongo.UpdateResult{MatchedCount: 1}, nil)
+
+	err := murm.repo.AddRole(context.Context(nil), mongoID.Hex(), domain.RoleSupport)
+
+	assert.NoError(t, err)
+}
+
+func TestAddRole_NotFound(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(&mongo.UpdateResult{}, nil)
+
+	err := murm.repo.AddRole(context.Context(nil), "", domain.RoleSupport)
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestRemoveRole_OK(t *testing.T) {
+	revokes := func(update bson.M) bool {
+		return update["$pull"].(bson.M)["roles"] == domain.RoleAdmin
+	}
+
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.MatchedBy(revokes)).Return(&mongo.UpdateResult{MatchedCount: 1}, nil)
+
+	err := murm.repo.RemoveRole(context.Context(nil), bson.NewObjectID().Hex(), domain.RoleAdmin)
+
+	assert.NoError(t, err)
+}
+
+func TestRemoveRole_UpdateOneError(t *testing.T) {
+	murm := setupMongoUserRepository(t)
+	murm.collection.On("UpdateOne", mock.IsType(nil), mock.AnythingOfType("bson.M"), mock.AnythingOfType("bson.M")).Return(nil, assert.AnError)
+
+	err := murm.repo.RemoveRole(context.Context(nil), "", domain.RoleAdmin)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,98 @@
+package infrastructure
+
+import (
+	"net/http"
+
+	"github.com/christhianjesus/crabi-challenge/internal/application"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type passwordHandler struct {
+	srv application.PasswordService
+}
+
+func NewPasswordHandler(srv application.PasswordService) *passwordHandler {
+	return &passwordHandler{srv}
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+}
+
+func (h *passwordHandler) Forgot(c echo.Context) error {
+	ctx := c.Request().Context()
+	request := new(ForgotPasswordRequest)
+
+	if err := c.Bind(request); err != nil {
+		return err
+	}
+
+	if validate, ok := c.Get(ValidatorCtxKey).(*validator.Validate); ok {
+		if err := validate.Struct(request); err != nil {
+			return err
+		}
+	}
+
+	err := h.srv.ForgotPassword(ctx, request.Email)
+	if err != nil {
+		return err
+	}
+
+	// same answer whether the email exists or not
+	return c.NoContent(http.StatusAccepted)
+}
+
+func (h *passwordHandler) Reset(c echo.Context) error {
+	ctx := c.Request().Context()
+	request := new(ResetPasswordRequest)
+
+	if err := c.Bind(request); err != nil {
+		return err
+	}
+
+	if validate, ok := c.Get(ValidatorCtxKey).(*validator.Validate); ok {
+		if err := validate.Struct(request); err != nil {
+			return err
+		}
+	}
+
+	err := h.srv.ResetPassword(ctx, request.Token, request.Password)
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *passwordHandler) Change(c echo.Context) error {
+	ctx := c.Request().Context()
+	request := new(ChangePasswordRequest)
+
+	if err := c.Bind(request); err != nil {
+		return err
+	}
+
+	if validate, ok := c.Get(ValidatorCtxKey).(*validator.Validate); ok {
+		if err := validate.Struct(request); err != nil {
+			return err
+		}
+	}
+
+	err := h.srv.ChangePassword(ctx, request.CurrentPassword, request.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,220 @@
+package infrastructure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/christhianjesus/crabi-challenge/internal/application"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type passwordHandlerMock struct {
+	service *mocks.PasswordService
+	handler *passwordHandler
+}
+
+func setupPasswordHandler(t *testing.T) *passwordHandlerMock {
+	mockPasswordService := mocks.NewPasswordService(t)
+
+	return &passwordHandlerMock{
+		service: mockPasswordService,
+		handler: NewPasswordHandler(mockPasswordService),
+	}
+}
+
+func newJSONContext(method, path, body string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+
+	return echo.New().NewContext(req, rec), rec
+}
+
+func TestForgot_OK(t *testing.T) {
+	ctx, rec := newJSONContext(http.MethodPost, "/password/forgot", `{"email": "an@email.com"}`)
+
+	ph := setupPasswordHandler(t)
+	ph.service.On("ForgotPassword", mock.Anything, "an@email.com").Return(nil)
+
+	err := SetValidator(ph.handler.Forgot)(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+}
+
+func TestForgot_BindError(t *testing.T) {
+	ctx, _ := newJSONContext(http.MethodPost, "/password/forgot", `{`)
+
+	ph := setupPasswordHandler(t)
+
+	err := ph.handler.Forgot(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+}
+
+func TestForgot_ValidateError(t *testing.T) {
+	ctx, _ := newJSONContext(http.MethodPost, "/password/forgot", `{"email": "invalid"}`)
+
+	ph := setupPasswordHandler(t)
+
+	err := SetValidator(ph.handler.Forgot)(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "email", problem.Errors[0].Field)
+	assert.Equal(t, "email", problem.Errors[0].Tag)
+}
+
+func TestForgot_ForgotPasswordError(t *testing.T) {
+	ctx, _ := newJSONContext(http.MethodPost, "/password/forgot", `{"email": "an@email.com"}`)
+
+	ph := setupPasswordHandler(t)
+	ph.service.On("ForgotPassword", mock.Anything, "an@email.com").Return(assert.AnError)
+
+	err := ph.handler.Forgot(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, problem.Status)
+	assert.Equal(t, "/problems/internal", problem.Type)
+}
+
+func TestReset_OK(t *testing.T) {
+	ctx, rec := newJSONContext(http.MethodPost, "/password/reset", `{"token": "token", "password": "password"}`)
+
+	ph := setupPasswordHandler(t)
+	ph.service.On("ResetPassword", mock.Anything, "token", "password").Return(nil)
+
+	err := SetValidator(ph.handler.Reset)(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestReset_BindError(t *testing.T) {
+	ctx, _ := newJSONContext(http.MethodPost, "/password/reset", `{`)
+
+	ph := setupPasswordHandler(t)
+
+	err := ph.handler.Reset(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+}
+
+func TestReset_ValidateError(t *testing.T) {
+	ctx, _ := newJSONContext(http.MethodPost, "/password/reset", `{"token": "token", "password": ""}`)
+
+	ph := setupPasswordHandler(t)
+
+	err := SetValidator(ph.handler.Reset)(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "password", problem.Errors[0].Field)
+	assert.Equal(t, "required", problem.Errors[0].Tag)
+}
+
+func TestReset_InvalidResetToken(t *testing.T) {
+	ctx, _ := newJSONContext(http.MethodPost, "/password/reset", `{"token": "token", "password": "password"}`)
+
+	ph := setupPasswordHandler(t)
+	ph.service.On("ResetPassword", mock.Anything, "token", "password").Return(application.ErrInvalidResetToken)
+
+	err := ph.handler.Reset(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, application.ErrInvalidResetToken.Error(), problem.Detail)
+}
+
+func TestReset_ResetPasswordError(t *testing.T) {
+	ctx, _ := newJSONContext(http.MethodPost, "/password/reset", `{"token": "token", "password": "password"}`)
+
+	ph := setupPasswordHandler(t)
+	ph.service.On("ResetPassword", mock.Anything, "token", "password").Return(assert.AnError)
+
+	err := ph.handler.Reset(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, problem.Status)
+}
+
+func TestChange_OK(t *testing.T) {
+	ctx, rec := newUserJSONContext(http.MethodPut, "/v1/user/password", `{"current_password": "password", "new_password": "new password"}`)
+
+	ph := setupPasswordHandler(t)
+	ph.service.On("ChangePassword", mock.Anything, "password", "new password").Return(nil)
+
+	err := SetValidator(ph.handler.Change)(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestChange_BindError(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodPut, "/v1/user/password", `{`)
+
+	ph := setupPasswordHandler(t)
+
+	err := ph.handler.Change(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+}
+
+func TestChange_ValidateError(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodPut, "/v1/user/password", `{"current_password": "password", "new_password": ""}`)
+
+	ph := setupPasswordHandler(t)
+
+	err := SetValidator(ph.handler.Change)(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "new_password", problem.Errors[0].Field)
+	assert.Equal(t, "required", problem.Errors[0].Tag)
+}
+
+func TestChange_WrongPassword(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodPut, "/v1/user/password", `{"current_password": "wrong", "new_password": "new password"}`)
+
+	ph := setupPasswordHandler(t)
+	ph.service.On("ChangePassword", mock.Anything, "wrong", "new password").Return(application.ErrWrongPassword)
+
+	err := ph.handler.Change(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, problem.Status)
+	assert.Equal(t, application.ErrWrongPassword.Error(), problem.Detail)
+}
+
+func TestChange_PasswordReused(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodPut, "/v1/user/password", `{"current_password": "password", "new_password": "old password"}`)
+
+	ph := setupPasswordHandler(t)
+	ph.service.On("ChangePassword", mock.Anything, "password", "old password").Return(application.ErrPasswordReused)
+
+	err := ph.handler.Change(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, application.ErrPasswordReused.Error(), problem.Detail)
+}
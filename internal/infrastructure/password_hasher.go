@@ -0,0 +1,156 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+var ErrUnsupportedHash = errors.New("unsupported password hash")
+
+// A hashing algorithm that recognizes its own hashes, its Verify asks for a
+// rehash when the hash parameters differ from the configured ones
+type passwordAlgorithm interface {
+	domain.PasswordHasher
+	identifies(hash string) bool
+}
+
+type passwordHasher struct {
+	current    passwordAlgorithm
+	algorithms []passwordAlgorithm
+}
+
+// Hashes with the algorithm of the policy, and verifies hashes of any known algorithm
+func NewPasswordHasher(policy domain.PasswordHashPolicy) domain.PasswordHasher {
+	bcryptAlg := &bcryptHasher{policy.BcryptCost}
+	argon2Alg := &argon2idHasher{policy.Argon2Memory, policy.Argon2Iterations, policy.Argon2Parallelism}
+
+	var current passwordAlgorithm = argon2Alg
+	if policy.Algorithm == domain.BcryptHash {
+		current = bcryptAlg
+	}
+
+	return &passwordHasher{current, []passwordAlgorithm{bcryptAlg, argon2Alg}}
+}
+
+func (h *passwordHasher) Hash(ctx context.Context, password string) (string, error) {
+	return h.current.Hash(ctx, password)
+}
+
+func (h *passwordHasher) Verify(ctx context.Context, hash, password string) (bool, bool, error) {
+	for _, alg := range h.algorithms {
+		if !alg.identifies(hash) {
+			continue
+		}
+
+		match, rehash, err := alg.Verify(ctx, hash, password)
+
+		return match, match && (rehash || alg != h.current), err
+	}
+
+	return false, false, ErrUnsupportedHash
+}
+
+type bcryptHasher struct {
+	cost int
+}
+
+func (h *bcryptHasher) identifies(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func (h *bcryptHasher) Hash(ctx context.Context, password string) (string, error) {
+	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hashedBytes), nil
+}
+
+func (h *bcryptHasher) Verify(ctx context.Context, hash, password string) (bool, bool, error) {
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, false, nil
+		}
+
+		return false, false, err
+	}
+
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		return false, false, err
+	}
+
+	return true, cost != h.cost, nil
+}
+
+type argon2idHasher struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+func (h *argon2idHasher) identifies(hash string) bool {
+	return strings.HasPrefix(hash, "$argon2id$")
+}
+
+// PHC string format: $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>
+func (h *argon2idHasher) Hash(ctx context.Context, password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.iterations, h.memory, h.parallelism, argon2KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, h.memory, h.iterations, h.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (h *argon2idHasher) Verify(ctx context.Context, hash, password string) (bool, bool, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return false, false, ErrUnsupportedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, false, ErrUnsupportedHash
+	}
+
+	var params argon2idHasher
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism); err != nil || params.iterations == 0 || params.parallelism == 0 {
+		return false, false, ErrUnsupportedHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, false, ErrUnsupportedHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return false, false, ErrUnsupportedHash
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.iterations, params.memory, params.parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return false, false, nil
+	}
+
+	return true, params != *h, nil
+}
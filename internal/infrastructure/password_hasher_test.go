@@ -0,0 +1,130 @@
+package infrastructure
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Small parameters to keep the tests fast
+func testHashPolicy(algorithm domain.PasswordHashAlgorithm) domain.PasswordHashPolicy {
+	return domain.PasswordHashPolicy{
+		Algorithm:         algorithm,
+		BcryptCost:        bcrypt.MinCost,
+		Argon2Memory:      64,
+		Argon2Iterations:  1,
+		Argon2Parallelism: 1,
+	}
+}
+
+func TestPasswordHasher_Argon2id(t *testing.T) {
+	hasher := NewPasswordHasher(testHashPolicy(domain.Argon2idHash))
+
+	hash, err := hasher.Hash(context.Context(nil), "password")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"))
+
+	match, rehash, err := hasher.Verify(context.Context(nil), hash, "password")
+	assert.NoError(t, err)
+	assert.True(t, match)
+	assert.False(t, rehash)
+
+	match, _, err = hasher.Verify(context.Context(nil), hash, "wrong")
+	assert.NoError(t, err)
+	assert.False(t, match)
+}
+
+func TestPasswordHasher_Bcrypt(t *testing.T) {
+	hasher := NewPasswordHasher(testHashPolicy(domain.BcryptHash))
+
+	hash, err := hasher.Hash(context.Context(nil), "password")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$2a$04$"))
+
+	match, rehash, err := hasher.Verify(context.Context(nil), hash, "password")
+	assert.NoError(t, err)
+	assert.True(t, match)
+	assert.False(t, rehash)
+
+	match, _, err = hasher.Verify(context.Context(nil), hash, "wrong")
+	assert.NoError(t, err)
+	assert.False(t, match)
+}
+
+func TestPasswordHasher_RehashOtherAlgorithm(t *testing.T) {
+	hash, _ := NewPasswordHasher(testHashPolicy(domain.BcryptHash)).Hash(context.Context(nil), "password")
+
+	match, rehash, err := NewPasswordHasher(testHashPolicy(domain.Argon2idHash)).Verify(context.Context(nil), hash, "password")
+
+	assert.NoError(t, err)
+	assert.True(t, match)
+	assert.True(t, rehash)
+}
+
+func TestPasswordHasher_RehashOtherParameters(t *testing.T) {
+	hash, _ := NewPasswordHasher(testHashPolicy(domain.Argon2idHash)).Hash(context.Context(nil), "password")
+	policy := testHashPolicy(domain.Argon2idHash)
+	policy.Argon2Iterations = 2
+
+	match, rehash, err := NewPasswordHasher(policy).Verify(context.Context(nil), hash, "password")
+
+	assert.NoError(t, err)
+	assert.True(t, match)
+	assert.True(t, rehash)
+}
+
+func TestPasswordHasher_RehashOtherCost(t *testing.T) {
+	hash, _ := NewPasswordHasher(testHashPolicy(domain.BcryptHash)).Hash(context.Context(nil), "password")
+	policy := testHashPolicy(domain.BcryptHash)
+	policy.BcryptCost = 5
+
+	match, rehash, err := NewPasswordHasher(policy).Verify(context.Context(nil), hash, "password")
+
+	assert.NoError(t, err)
+	assert.True(t, match)
+	assert.True(t, rehash)
+}
+
+func TestPasswordHasher_NoRehashOnMismatch(t *testing.T) {
+	hash, _ := NewPasswordHasher(testHashPolicy(domain.BcryptHash)).Hash(context.Context(nil), "password")
+
+	match, rehash, err := NewPasswordHasher(testHashPolicy(domain.Argon2idHash)).Verify(context.Context(nil), hash, "wrong")
+
+	assert.NoError(t, err)
+	assert.False(t, match)
+	assert.False(t, rehash)
+}
+
+func TestPasswordHasher_UnsupportedHash(t *testing.T) {
+	hasher := NewPasswordHasher(testHashPolicy(domain.Argon2idHash))
+
+	tests := []string{
+		"",
+		"$pbkdf2-sha256$29000$salt$key",
+		"$argon2id$v=19$m=64,t=1,p=1$salt",
+		"$argon2id$v=16$m=64,t=1,p=1$c2FsdHNhbHQ$a2V5",
+		"$argon2id$v=19$m=64,t=0,p=1$c2FsdHNhbHQ$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$not base64$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHQ$",
+	}
+
+	for _, hash := range tests {
+		_, _, err := hasher.Verify(context.Context(nil), hash, "password")
+
+		assert.ErrorIs(t, err, ErrUnsupportedHash, hash)
+	}
+}
+
+func TestPasswordHasher_BcryptError(t *testing.T) {
+	hasher := NewPasswordHasher(testHashPolicy(domain.BcryptHash))
+
+	_, err := hasher.Hash(context.Context(nil), string(make([]byte, 100)))
+	assert.ErrorIs(t, err, bcrypt.ErrPasswordTooLong)
+
+	_, _, err = hasher.Verify(context.Context(nil), "$2a$04$short", "password")
+	assert.Error(t, err)
+}
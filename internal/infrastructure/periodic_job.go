@@ -0,0 +1,51 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Work done in the background, returns how many items it handled
+type JobTask func(ctx context.Context) (int64, error)
+
+type periodicJob struct {
+	task     JobTask
+	interval time.Duration
+	// logged with the number of handled items, when there are any
+	message string
+	logger  echo.Logger
+}
+
+func NewPeriodicJob(task JobTask, interval time.Duration, message string, logger echo.Logger) *periodicJob {
+	return &periodicJob{task, interval, message, logger}
+}
+
+// Runs once on start and then every interval, until the context is done
+func (j *periodicJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.run(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Items handled before a failure are logged as well
+func (j *periodicJob) run(ctx context.Context) {
+	handled, err := j.task(ctx)
+	if handled > 0 {
+		j.logger.Infof(j.message, handled)
+	}
+
+	if err != nil {
+		j.logger.Error(err)
+	}
+}
@@ -0,0 +1,66 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// Helper function to get a logger that writes to a buffer
+func newBufferLogger() (echo.Logger, *bytes.Buffer) {
+	output := new(bytes.Buffer)
+	logger := echo.New().Logger
+	logger.SetOutput(output)
+
+	return logger, output
+}
+
+func TestPeriodicJob_RunsOnStart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	runs := 0
+	task := func(context.Context) (int64, error) {
+		runs++
+		return 2, nil
+	}
+
+	NewPeriodicJob(task, time.Hour, "purged %d deleted users", echo.New().Logger).Run(ctx)
+
+	assert.Equal(t, 1, runs)
+}
+
+func TestPeriodicJob_Error(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	logger, output := newBufferLogger()
+	task := func(context.Context) (int64, error) {
+		return 1, assert.AnError
+	}
+
+	NewPeriodicJob(task, time.Hour, "screened %d users", logger).Run(ctx)
+
+	assert.Contains(t, output.String(), assert.AnError.Error())
+}
+
+func TestPeriodicJob_Interval(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	runs := 0
+	task := func(context.Context) (int64, error) {
+		if runs++; runs == 2 {
+			cancel()
+		}
+
+		return 0, nil
+	}
+
+	NewPeriodicJob(task, time.Millisecond, "purged %d deleted users", echo.New().Logger).Run(ctx)
+
+	assert.Equal(t, 2, runs)
+}
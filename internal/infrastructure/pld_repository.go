@@ -5,15 +5,50 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"math/rand/v2"
+	"mime"
 	"net/http"
+	"time"
 
 	"github.com/christhianjesus/crabi-challenge/internal/domain"
 )
 
+// Answers are a single boolean, anything bigger is not from the PLD service
+const maxPLDResponseSize = 64 << 10
+
+var ErrPLDCircuitOpen = domain.NewError(domain.ErrUpstreamUnavailable, "PLD service unavailable")
+
+// Tells the caller when the PLD service will be tried again, matches ErrPLDCircuitOpen
+type CircuitOpenError struct {
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return ErrPLDCircuitOpen.Error()
+}
+
+func (e *CircuitOpenError) Unwrap() error {
+	return ErrPLDCircuitOpen
+}
+
+type PLDPolicy struct {
+	// retries after the first attempt, only when the service is unavailable
+	MaxRetries int
+	// doubled on every retry up to MaxDelay, with jitter
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// failed calls in a row that open the circuit, and how long it stays open
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
 type pldRepository struct {
-	client HTTPClient
-	url    string
+	client  HTTPClient
+	url     string
+	policy  PLDPolicy
+	breaker *circuitBreaker
 }
 
 type HTTPClient interface {
@@ -36,43 +71,157 @@ func (p *pldRequest) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// Only is_in_blacklist is always sent, older versions of the service send nothing else
 type pldResponse struct {
-	IsInBlacklist bool `json:"is_in_blacklist"`
+	IsInBlacklist bool     `json:"is_in_blacklist"`
+	MatchScore    *float64 `json:"match_score"`
+	MatchedLists  []string `json:"matched_lists"`
+	EntityID      string   `json:"entity_id"`
+	ReasonCodes   []string `json:"reason_codes"`
+}
+
+// Without a score, a hit is taken as a full match
+func (p *pldResponse) toDomain() (*domain.ScreeningResult, error) {
+	score := 0.0
+	if p.IsInBlacklist {
+		score = 1
+	}
+
+	if p.MatchScore != nil {
+		score = *p.MatchScore
+	}
+
+	if score < 0 || score > 1 {
+		return nil, fmt.Errorf("%w: PLD answered with score %v", domain.ErrUpstreamUnavailable, score)
+	}
+
+	return &domain.ScreeningResult{
+		InBlacklist:  p.IsInBlacklist,
+		Score:        score,
+		MatchedLists: p.MatchedLists,
+		EntityID:     p.EntityID,
+		ReasonCodes:  p.ReasonCodes,
+	}, nil
 }
 
-func NewPLDRepository(client HTTPClient, url string) domain.PLDRepository {
-	return &pldRepository{client, url}
+func NewPLDRepository(client HTTPClient, url string, policy PLDPolicy) domain.PLDRepository {
+	return &pldRepository{client, url, policy, newCircuitBreaker(policy.BreakerThreshold, policy.BreakerCooldown)}
 }
 
-func (ms *pldRepository) IsValidUser(ctx context.Context, user *domain.User) (bool, error) {
+// Checking the blacklist has no side effects, so failed attempts are retried
+func (ms *pldRepository) ScreenUser(ctx context.Context, user *domain.User) (*domain.ScreeningResult, error) {
 	data, err := json.Marshal(&pldRequest{user})
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+
+	if wait, ok := ms.breaker.allow(); !ok {
+		return nil, &CircuitOpenError{wait}
+	}
+
+	var result *domain.ScreeningResult
+	for attempt := 0; ; attempt++ {
+		result, err = ms.checkBlacklist(ctx, data)
+		if !errors.Is(err, domain.ErrUpstreamUnavailable) || attempt == ms.policy.MaxRetries {
+			break
+		}
+
+		if !sleep(ctx, ms.backoff(attempt)) {
+			break
+		}
+	}
+
+	switch {
+	case err == nil:
+		ms.breaker.success()
+	case errors.Is(err, domain.ErrUpstreamUnavailable):
+		ms.breaker.failure()
+	default:
+		ms.breaker.release()
 	}
 
+	return result, err
+}
+
+func (ms *pldRepository) checkBlacklist(ctx context.Context, data []byte) (*domain.ScreeningResult, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ms.url+"/check-blacklist", bytes.NewReader(data))
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
 	var resp *http.Response
 	if resp, err = ms.client.Do(req); err != nil {
-		return false, err
+		// the caller left, the service is not to blame
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		return nil, fmt.Errorf("%w: %w", domain.ErrUpstreamUnavailable, err)
 	}
 
 	defer resp.Body.Close()
 
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("%w: PLD answered %d", domain.ErrUpstreamUnavailable, resp.StatusCode)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("PLD answered %d", resp.StatusCode)
+	}
+
+	// proxies answer with error pages of their own
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "application/json" {
+		return nil, fmt.Errorf("%w: PLD answered with content type %q", domain.ErrUpstreamUnavailable, mediaType)
+	}
+
 	var body []byte
-	if body, err = io.ReadAll(resp.Body); err != nil {
-		return false, err
+	if body, err = io.ReadAll(io.LimitReader(resp.Body, maxPLDResponseSize+1)); err != nil {
+		return nil, fmt.Errorf("%w: %w", domain.ErrUpstreamUnavailable, err)
+	}
+
+	if len(body) > maxPLDResponseSize {
+		return nil, fmt.Errorf("%w: PLD answer is too large", domain.ErrUpstreamUnavailable)
 	}
 
 	var response pldResponse
 	if err = json.Unmarshal(body, &response); err != nil {
-		return false, err
+		return nil, fmt.Errorf("%w: %w", domain.ErrUpstreamUnavailable, err)
 	}
 
-	return !response.IsInBlacklist, nil
+	return response.toDomain()
+}
+
+// The service is only known by its URL
+func (ms *pldRepository) Provider() string {
+	return ms.url
+}
+
+// Helper function to get the delay before a retry, from half to all of the exponential delay
+func (ms *pldRepository) backoff(attempt int) time.Duration {
+	// compared before shifting, large attempts would overflow the delay
+	delay := ms.policy.MaxDelay
+	if ms.policy.BaseDelay <= ms.policy.MaxDelay>>attempt {
+		delay = ms.policy.BaseDelay << attempt
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// Helper function to wait unless the context ends first
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
 }
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/christhianjesus/crabi-challenge/internal/domain"
 	"github.com/christhianjesus/crabi-challenge/internal/mocks"
@@ -18,45 +19,170 @@ type pldRepositoryMock struct {
 	repo   domain.PLDRepository
 }
 
+// Short delays to keep the tests fast, and a circuit that does not open
+var testPLDPolicy = PLDPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond, BreakerThreshold: 100, BreakerCooldown: time.Minute}
+
 func setupPLDRepository(t *testing.T) *pldRepositoryMock {
+	return setupPLDRepositoryWithPolicy(t, testPLDPolicy)
+}
+
+func setupPLDRepositoryWithPolicy(t *testing.T, policy PLDPolicy) *pldRepositoryMock {
 	mockHTTPClient := mocks.NewHTTPClient(t)
 
 	return &pldRepositoryMock{
 		client: mockHTTPClient,
-		repo:   NewPLDRepository(mockHTTPClient, ""),
+		repo:   NewPLDRepository(mockHTTPClient, "", policy),
+	}
+}
+
+// Helper function to answer every request with a new response
+func pldAnswer(status int, contentType, body string) func(*http.Request) (*http.Response, error) {
+	return func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": []string{contentType}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
 	}
 }
 
-func TestIsValidUser_MarshalError(t *testing.T) {
+func TestScreenUser_MarshalError(t *testing.T) {
 	prm := setupPLDRepository(t)
 
-	isValidUser, err := prm.repo.IsValidUser(context.Context(nil), nil)
+	result, err := prm.repo.ScreenUser(context.Context(nil), nil)
 
-	assert.Empty(t, isValidUser)
+	assert.Empty(t, result)
 	assert.Error(t, err)
 	assert.EqualError(t, err, "json: error calling MarshalJSON for type *infrastructure.pldRequest: Nil user")
 }
 
-func TestIsValidUser_NewRequestError(t *testing.T) {
+func TestScreenUser_NewRequestError(t *testing.T) {
 	prm := setupPLDRepository(t)
 
-	isValidUser, err := prm.repo.IsValidUser(context.Context(nil), &domain.User{})
+	result, err := prm.repo.ScreenUser(context.Context(nil), &domain.User{})
 
-	assert.Empty(t, isValidUser)
+	assert.Empty(t, result)
 	assert.Error(t, err)
 	assert.EqualError(t, err, "net/http: nil Context")
 }
 
-func TestIsValidUser_DoError(t *testing.T) {
+func TestScreenUser_DoError(t *testing.T) {
 	prm := setupPLDRepository(t)
 	prm.client.On("Do", mock.Anything).
 		Return(nil, assert.AnError)
 
-	isValidUser, err := prm.repo.IsValidUser(context.TODO(), &domain.User{})
+	result, err := prm.repo.ScreenUser(context.TODO(), &domain.User{})
 
-	assert.Empty(t, isValidUser)
-	assert.Error(t, err)
-	assert.EqualError(t, err, assert.AnError.Error())
+	assert.Empty(t, result)
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	assert.ErrorIs(t, err, assert.AnError)
+	prm.client.AssertNumberOfCalls(t, "Do", 3)
+}
+
+func TestScreenUser_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	prm := setupPLDRepository(t)
+	prm.client.On("Do", mock.Anything).Return(nil, context.Canceled)
+
+	_, err := prm.repo.ScreenUser(ctx, &domain.User{})
+
+	// not retried, the caller is gone
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	prm.client.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestScreenUser_ServerError(t *testing.T) {
+	prm := setupPLDRepository(t)
+	prm.client.On("Do", mock.Anything).Return(pldAnswer(502, "text/html", `<html>Bad Gateway</html>`))
+
+	result, err := prm.repo.ScreenUser(context.TODO(), &domain.User{})
+
+	assert.Empty(t, result)
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	assert.EqualError(t, err, "Upstream service unavailable: PLD answered 502")
+	prm.client.AssertNumberOfCalls(t, "Do", 3)
+}
+
+func TestScreenUser_RetrySucceeds(t *testing.T) {
+	prm := setupPLDRepository(t)
+	prm.client.On("Do", mock.Anything).Return(pldAnswer(503, "text/plain", `Service Unavailable`)).Once()
+	prm.client.On("Do", mock.Anything).Return(pldAnswer(200, "application/json", `{"is_in_blacklist": false}`)).Once()
+
+	result, err := prm.repo.ScreenUser(context.TODO(), &domain.User{})
+
+	assert.NoError(t, err)
+	assert.False(t, result.InBlacklist)
+}
+
+func TestScreenUser_ClientError(t *testing.T) {
+	prm := setupPLDRepository(t)
+	prm.client.On("Do", mock.Anything).Return(pldAnswer(400, "application/json", `{"error": "bad request"}`))
+
+	_, err := prm.repo.ScreenUser(context.TODO(), &domain.User{})
+
+	// the same request would fail again
+	assert.EqualError(t, err, "PLD answered 400")
+	assert.NotErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	prm.client.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestScreenUser_WrongContentType(t *testing.T) {
+	prm := setupPLDRepository(t)
+	prm.client.On("Do", mock.Anything).Return(pldAnswer(200, "text/html; charset=utf-8", `<html>Login</html>`))
+
+	_, err := prm.repo.ScreenUser(context.TODO(), &domain.User{})
+
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	assert.EqualError(t, err, `Upstream service unavailable: PLD answered with content type "text/html"`)
+}
+
+func TestScreenUser_TooLarge(t *testing.T) {
+	prm := setupPLDRepository(t)
+	prm.client.On("Do", mock.Anything).Return(pldAnswer(200, "application/json", `{"is_in_blacklist": false, "padding": "`+strings.Repeat("a", maxPLDResponseSize)+`"}`))
+
+	_, err := prm.repo.ScreenUser(context.TODO(), &domain.User{})
+
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	assert.EqualError(t, err, "Upstream service unavailable: PLD answer is too large")
+}
+
+func TestScreenUser_CircuitOpen(t *testing.T) {
+	prm := setupPLDRepositoryWithPolicy(t, PLDPolicy{BreakerThreshold: 2, BreakerCooldown: time.Minute})
+	prm.client.On("Do", mock.Anything).Return(pldAnswer(503, "text/plain", `Service Unavailable`))
+
+	prm.repo.ScreenUser(context.TODO(), &domain.User{})
+	prm.repo.ScreenUser(context.TODO(), &domain.User{})
+	_, err := prm.repo.ScreenUser(context.TODO(), &domain.User{})
+
+	// fails fast without calling the service
+	var open *CircuitOpenError
+	assert.ErrorAs(t, err, &open)
+	assert.ErrorIs(t, err, ErrPLDCircuitOpen)
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	assert.InDelta(t, time.Minute, open.RetryAfter, float64(time.Second))
+	prm.client.AssertNumberOfCalls(t, "Do", 2)
+}
+
+func TestScreenUser_HalfOpenClientError(t *testing.T) {
+	prm := setupPLDRepositoryWithPolicy(t, PLDPolicy{BreakerThreshold: 2, BreakerCooldown: 10 * time.Millisecond})
+	prm.client.On("Do", mock.Anything).Return(pldAnswer(503, "text/plain", `Service Unavailable`)).Twice()
+	prm.client.On("Do", mock.Anything).Return(pldAnswer(400, "application/json", `{"error": "bad request"}`)).Once()
+	prm.client.On("Do", mock.Anything).Return(pldAnswer(503, "text/plain", `Service Unavailable`)).Once()
+
+	prm.repo.ScreenUser(context.TODO(), &domain.User{})
+	prm.repo.ScreenUser(context.TODO(), &domain.User{})
+	time.Sleep(20 * time.Millisecond)
+
+	// a trial call that does not reach a verdict leaves the circuit half open
+	prm.repo.ScreenUser(context.TODO(), &domain.User{})
+	prm.repo.ScreenUser(context.TODO(), &domain.User{})
+	_, err := prm.repo.ScreenUser(context.TODO(), &domain.User{})
+
+	assert.ErrorIs(t, err, ErrPLDCircuitOpen)
+	prm.client.AssertNumberOfCalls(t, "Do", 4)
 }
 
 type FailRead struct{}
@@ -65,46 +191,99 @@ func (*FailRead) Read(p []byte) (n int, err error) {
 	return 0, assert.AnError
 }
 
-func TestIsValidUser_ReadAllError(t *testing.T) {
+func TestScreenUser_ReadAllError(t *testing.T) {
 	prm := setupPLDRepository(t)
 	prm.client.On("Do", mock.Anything).
 		Return(&http.Response{
 			StatusCode: 201,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
 			Body:       io.NopCloser(&FailRead{}),
 		}, nil)
 
-	isValidUser, err := prm.repo.IsValidUser(context.TODO(), &domain.User{})
+	result, err := prm.repo.ScreenUser(context.TODO(), &domain.User{})
 
-	assert.Empty(t, isValidUser)
-	assert.Error(t, err)
-	assert.EqualError(t, err, assert.AnError.Error())
+	assert.Empty(t, result)
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	assert.ErrorIs(t, err, assert.AnError)
 }
 
-func TestIsValidUser_UnmarshalError(t *testing.T) {
+func TestScreenUser_UnmarshalError(t *testing.T) {
 	prm := setupPLDRepository(t)
-	prm.client.On("Do", mock.Anything).
-		Return(&http.Response{
-			StatusCode: 201,
-			Body:       io.NopCloser(strings.NewReader(``)),
-		}, nil)
+	prm.client.On("Do", mock.Anything).Return(pldAnswer(201, "application/json", ``))
 
-	isValidUser, err := prm.repo.IsValidUser(context.TODO(), &domain.User{})
+	result, err := prm.repo.ScreenUser(context.TODO(), &domain.User{})
 
-	assert.Empty(t, isValidUser)
-	assert.Error(t, err)
-	assert.EqualError(t, err, "unexpected end of JSON input")
+	assert.Empty(t, result)
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	assert.EqualError(t, err, "Upstream service unavailable: unexpected end of JSON input")
 }
 
-func TestIsValidUser_OK(t *testing.T) {
+func TestScreenUser_OK(t *testing.T) {
 	prm := setupPLDRepository(t)
-	prm.client.On("Do", mock.Anything).
-		Return(&http.Response{
-			StatusCode: 201,
-			Body:       io.NopCloser(strings.NewReader(`{"is_in_blacklist": true}`)),
-		}, nil)
+	prm.client.On("Do", mock.Anything).Return(pldAnswer(201, "application/json", `{"is_in_blacklist": true}`))
 
-	isValidUser, err := prm.repo.IsValidUser(context.TODO(), &domain.User{})
+	result, err := prm.repo.ScreenUser(context.TODO(), &domain.User{})
 
+	// a hit without a score is a full match
 	assert.NoError(t, err)
-	assert.Equal(t, false, isValidUser)
+	assert.Equal(t, &domain.ScreeningResult{InBlacklist: true, Score: 1}, result)
+}
+
+func TestScreenUser_NotInBlacklist(t *testing.T) {
+	prm := setupPLDRepository(t)
+	prm.client.On("Do", mock.Anything).Return(pldAnswer(200, "application/json", `{"is_in_blacklist": false}`))
+
+	result, err := prm.repo.ScreenUser(context.TODO(), &domain.User{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.ScreeningResult{}, result)
+}
+
+func TestScreenUser_Detailed(t *testing.T) {
+	prm := setupPLDRepository(t)
+	prm.client.On("Do", mock.Anything).Return(pldAnswer(200, "application/json",
+		`{"is_in_blacklist": true, "match_score": 0.72, "matched_lists": ["PEP"], "entity_id": "e1", "reason_codes": ["FUZZY_NAME"]}`))
+
+	result, err := prm.repo.ScreenUser(context.TODO(), &domain.User{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.ScreeningResult{
+		InBlacklist:  true,
+		Score:        0.72,
+		MatchedLists: []string{"PEP"},
+		EntityID:     "e1",
+		ReasonCodes:  []string{"FUZZY_NAME"},
+	}, result)
+}
+
+func TestScreenUser_ScoreOutOfRange(t *testing.T) {
+	prm := setupPLDRepository(t)
+	prm.client.On("Do", mock.Anything).Return(pldAnswer(200, "application/json", `{"is_in_blacklist": true, "match_score": 72}`))
+
+	result, err := prm.repo.ScreenUser(context.TODO(), &domain.User{})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	assert.EqualError(t, err, "Upstream service unavailable: PLD answered with score 72")
+}
+
+func TestProvider(t *testing.T) {
+	repo := NewPLDRepository(mocks.NewHTTPClient(t), "http://pld.local", testPLDPolicy)
+
+	assert.Equal(t, "http://pld.local", repo.Provider())
+}
+
+func TestBackoff(t *testing.T) {
+	repo := &pldRepository{policy: PLDPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 2 * time.Second}}
+
+	delay := repo.backoff(1)
+	assert.GreaterOrEqual(t, delay, 100*time.Millisecond)
+	assert.LessOrEqual(t, delay, 200*time.Millisecond)
+
+	// the shifted delay would overflow, or be shifted out, without the cap
+	for _, attempt := range []int{40, 62, 63, 64, 1000} {
+		delay = repo.backoff(attempt)
+		assert.GreaterOrEqual(t, delay, time.Second, "attempt %d", attempt)
+		assert.LessOrEqual(t, delay, 2*time.Second, "attempt %d", attempt)
+	}
 }
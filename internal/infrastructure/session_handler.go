@@ -0,0 +1,52 @@
+package infrastructure
+
+import (
+	"net/http"
+
+	"github.com/christhianjesus/crabi-challenge/internal/application"
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/labstack/echo/v4"
+)
+
+type sessionHandler struct {
+	srv application.SessionService
+}
+
+func NewSessionHandler(srv application.SessionService) *sessionHandler {
+	return &sessionHandler{srv}
+}
+
+type SessionsResponse struct {
+	Sessions []*domain.Session `json:"sessions"`
+}
+
+func (h *sessionHandler) List(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	sessions, err := h.srv.ListSessions(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &SessionsResponse{Sessions: sessions})
+}
+
+func (h *sessionHandler) Revoke(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	if err := h.srv.RevokeSession(ctx, c.Param("id")); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *sessionHandler) RevokeAll(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	if err := h.srv.RevokeAllSessions(ctx); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
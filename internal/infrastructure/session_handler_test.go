@@ -0,0 +1,121 @@
+package infrastructure
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/christhianjesus/crabi-challenge/internal/application"
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/christhianjesus/crabi-challenge/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type sessionHandlerMock struct {
+	service *mocks.SessionService
+	handler *sessionHandler
+}
+
+func setupSessionHandler(t *testing.T) *sessionHandlerMock {
+	mockSessionService := mocks.NewSessionService(t)
+
+	return &sessionHandlerMock{
+		service: mockSessionService,
+		handler: NewSessionHandler(mockSessionService),
+	}
+}
+
+func TestListSessionsHandler_OK(t *testing.T) {
+	ctx, rec := newUserJSONContext(http.MethodGet, "/v1/sessions", "")
+
+	sh := setupSessionHandler(t)
+	sh.service.On("ListSessions", mock.Anything).Return([]*domain.Session{{ID: "family", UserID: "1", IP: "192.0.2.1", Current: true}}, nil)
+
+	err := sh.handler.List(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"id":"family"`)
+	assert.Contains(t, rec.Body.String(), `"current":true`)
+	assert.NotContains(t, rec.Body.String(), `"user_id"`)
+}
+
+func TestListSessionsHandler_Empty(t *testing.T) {
+	ctx, rec := newUserJSONContext(http.MethodGet, "/v1/sessions", "")
+
+	sh := setupSessionHandler(t)
+	sh.service.On("ListSessions", mock.Anything).Return([]*domain.Session{}, nil)
+
+	err := sh.handler.List(ctx)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"sessions":[]}`, rec.Body.String())
+}
+
+func TestListSessionsHandler_Error(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodGet, "/v1/sessions", "")
+
+	sh := setupSessionHandler(t)
+	sh.service.On("ListSessions", mock.Anything).Return(nil, assert.AnError)
+
+	err := sh.handler.List(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, problem.Status)
+}
+
+func TestRevokeSessionHandler_OK(t *testing.T) {
+	ctx, rec := newUserJSONContext(http.MethodDelete, "/v1/sessions/family", "")
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("family")
+
+	sh := setupSessionHandler(t)
+	sh.service.On("RevokeSession", mock.Anything, "family").Return(nil)
+
+	err := sh.handler.Revoke(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestRevokeSessionHandler_NotFound(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodDelete, "/v1/sessions/family", "")
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("family")
+
+	sh := setupSessionHandler(t)
+	sh.service.On("RevokeSession", mock.Anything, "family").Return(application.ErrSessionNotFound)
+
+	err := sh.handler.Revoke(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, problem.Status)
+	assert.Equal(t, "Session not found", problem.Detail)
+}
+
+func TestRevokeAllSessionsHandler_OK(t *testing.T) {
+	ctx, rec := newUserJSONContext(http.MethodDelete, "/v1/sessions", "")
+
+	sh := setupSessionHandler(t)
+	sh.service.On("RevokeAllSessions", mock.Anything).Return(nil)
+
+	err := sh.handler.RevokeAll(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestRevokeAllSessionsHandler_Error(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodDelete, "/v1/sessions", "")
+
+	sh := setupSessionHandler(t)
+	sh.service.On("RevokeAllSessions", mock.Anything).Return(assert.AnError)
+
+	err := sh.handler.RevokeAll(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, problem.Status)
+}
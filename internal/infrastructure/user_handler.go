@@ -2,11 +2,20 @@ package infrastructure
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/christhianjesus/crabi-challenge/internal/application"
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 )
 
+const (
+	headerETag    = "ETag"
+	headerIfMatch = "If-Match"
+)
+
 type userHandler struct {
 	srv application.UserService
 }
@@ -15,14 +24,95 @@ func NewUserHandler(srv application.UserService) *userHandler {
 	return &userHandler{srv}
 }
 
+type UpdateUserRequest struct {
+	FirstName *string `json:"first_name" validate:"omitempty,alpha"`
+	LastName  *string `json:"last_name" validate:"omitempty,alpha"`
+}
+
 func (h *userHandler) Get(c echo.Context) error {
 	ctx := c.Request().Context()
-	userID := c.Get("user_id").(string)
 
-	user, err := h.srv.GetUser(ctx, userID)
+	principal, err := principalFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	user, err := h.srv.GetUser(ctx, principal.UserID)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(headerETag, etag(user.Version))
+
+	return c.JSON(http.StatusOK, user)
+}
+
+func (h *userHandler) Update(c echo.Context) error {
+	ctx := c.Request().Context()
+	request := new(UpdateUserRequest)
+
+	principal, err := principalFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	version, ok := parseETag(c.Request().Header.Get(headerIfMatch))
+	if !ok {
+		return echo.NewHTTPError(http.StatusPreconditionRequired, "If-Match header with the user ETag is required")
+	}
+
+	if err := c.Bind(request); err != nil {
+		return err
+	}
+
+	if validate, ok := c.Get(ValidatorCtxKey).(*validator.Validate); ok {
+		if err := validate.Struct(request); err != nil {
+			return err
+		}
+	}
+
+	update := &domain.UserUpdate{FirstName: request.FirstName, LastName: request.LastName}
+
+	user, err := h.srv.UpdateUser(ctx, principal.UserID, update, version)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return err
 	}
 
+	c.Response().Header().Set(headerETag, etag(user.Version))
+
 	return c.JSON(http.StatusOK, user)
 }
+
+func (h *userHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	principal, err := principalFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	if err = h.srv.DeleteUser(ctx, principal.UserID); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Helper function to format a version as a strong ETag
+func etag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+func parseETag(value string) (int64, bool) {
+	value, err := strconv.Unquote(strings.TrimSpace(value))
+	if err != nil {
+		return 0, false
+	}
+
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return version, true
+}
@@ -5,6 +5,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/christhianjesus/crabi-challenge/internal/application"
 	"github.com/christhianjesus/crabi-challenge/internal/domain"
 	"github.com/christhianjesus/crabi-challenge/internal/mocks"
 	"github.com/labstack/echo/v4"
@@ -22,9 +23,9 @@ type userHandlerMock struct {
 func setupGetUserHandler(t *testing.T) *userHandlerMock {
 	mockUserService := mocks.NewUserService(t)
 	req := httptest.NewRequest(http.MethodGet, "/user", nil)
+	req = req.WithContext(domain.WithPrincipal(req.Context(), &domain.Principal{UserID: "1"}))
 	rec := httptest.NewRecorder()
 	ctx := echo.New().NewContext(req, rec)
-	ctx.Set("user_id", "1")
 
 	return &userHandlerMock{
 		service: mockUserService,
@@ -43,7 +44,19 @@ func TestGetUser_OK(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, guh.rec.Code)
-	assert.JSONEq(t, `{"id":"1", "email":"an@email.com", "first_name":"", "last_name":"", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`, guh.rec.Body.String())
+	assert.JSONEq(t, `{"id":"1", "email":"an@email.com", "first_name":"", "last_name":"", "email_verified":false, "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z"}`, guh.rec.Body.String())
+}
+
+func TestGetUser_HidesScreeningStatus(t *testing.T) {
+	guh := setupGetUserHandler(t)
+	guh.service.On("GetUser", mock.Anything, "1").Return(&domain.User{ID: "1", ScreeningStatus: domain.ScreeningPendingReview}, nil)
+
+	err := guh.handler.Get(guh.ctx)
+
+	// telling the user they are under review would tip them off
+	assert.NoError(t, err)
+	assert.NotContains(t, guh.rec.Body.String(), "screening_status")
+	assert.NotContains(t, guh.rec.Body.String(), "pending_review")
 }
 
 func TestGetUser_GetUserError(t *testing.T) {
@@ -51,9 +64,155 @@ func TestGetUser_GetUserError(t *testing.T) {
 	guh.service.On("GetUser", mock.Anything, mock.AnythingOfType("string")).Return(nil, assert.AnError)
 
 	err := guh.handler.Get(guh.ctx)
-	he := err.(*echo.HTTPError)
+	problem := newProblem(err)
 
 	assert.Error(t, err)
-	assert.Equal(t, http.StatusInternalServerError, he.Code)
-	assert.Equal(t, assert.AnError.Error(), he.Message)
+	assert.Equal(t, http.StatusInternalServerError, problem.Status)
+	assert.Equal(t, "/problems/internal", problem.Type)
+}
+
+func TestGetUser_MissingPrincipal(t *testing.T) {
+	guh := setupGetUserHandler(t)
+	ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/user", nil), guh.rec)
+
+	err := guh.handler.Get(ctx)
+
+	assert.Equal(t, http.StatusUnauthorized, newProblem(err).Status)
+}
+
+func TestGetUser_ETag(t *testing.T) {
+	guh := setupGetUserHandler(t)
+	guh.service.On("GetUser", mock.Anything, "1").Return(&domain.User{ID: "1", Version: 3}, nil)
+
+	err := guh.handler.Get(guh.ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `"3"`, guh.rec.Header().Get("ETag"))
+}
+
+// Helper function to build a PATCH /v1/user request with an If-Match header
+func newUpdateUserContext(ifMatch, body string) (echo.Context, *httptest.ResponseRecorder) {
+	ctx, rec := newUserJSONContext(http.MethodPatch, "/v1/user", body)
+	if ifMatch != "" {
+		ctx.Request().Header.Set("If-Match", ifMatch)
+	}
+
+	return ctx, rec
+}
+
+func TestUpdateUser_OK(t *testing.T) {
+	ctx, rec := newUpdateUserContext(`"3"`, `{"first_name": "Newname"}`)
+
+	isUpdate := func(update *domain.UserUpdate) bool {
+		return *update.FirstName == "Newname" && update.LastName == nil
+	}
+
+	guh := setupGetUserHandler(t)
+	guh.service.On("UpdateUser", mock.Anything, "1", mock.MatchedBy(isUpdate), int64(3)).Return(&domain.User{ID: "1", FirstName: "Newname", Version: 4}, nil)
+
+	err := SetValidator(guh.handler.Update)(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"4"`, rec.Header().Get("ETag"))
+	assert.Contains(t, rec.Body.String(), `"first_name":"Newname"`)
+}
+
+func TestUpdateUser_HidesScreeningStatus(t *testing.T) {
+	ctx, rec := newUpdateUserContext(`"3"`, `{"first_name": "Newname"}`)
+
+	guh := setupGetUserHandler(t)
+	guh.service.On("UpdateUser", mock.Anything, "1", mock.AnythingOfType("*domain.UserUpdate"), int64(3)).Return(&domain.User{ID: "1", ScreeningStatus: domain.ScreeningFlagged, Version: 4}, nil)
+
+	err := SetValidator(guh.handler.Update)(ctx)
+
+	assert.NoError(t, err)
+	assert.NotContains(t, rec.Body.String(), "screening_status")
+	assert.NotContains(t, rec.Body.String(), "flagged")
+}
+
+func TestUpdateUser_MissingIfMatch(t *testing.T) {
+	ctx, _ := newUpdateUserContext("", `{"first_name": "Newname"}`)
+
+	guh := setupGetUserHandler(t)
+
+	err := guh.handler.Update(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusPreconditionRequired, problem.Status)
+}
+
+func TestUpdateUser_InvalidIfMatch(t *testing.T) {
+	ctx, _ := newUpdateUserContext(`W/"abc"`, `{"first_name": "Newname"}`)
+
+	guh := setupGetUserHandler(t)
+
+	err := guh.handler.Update(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusPreconditionRequired, problem.Status)
+}
+
+func TestUpdateUser_BindError(t *testing.T) {
+	ctx, _ := newUpdateUserContext(`"3"`, `{`)
+
+	guh := setupGetUserHandler(t)
+
+	err := guh.handler.Update(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+}
+
+func TestUpdateUser_ValidateError(t *testing.T) {
+	ctx, _ := newUpdateUserContext(`"3"`, `{"last_name": "N3wname"}`)
+
+	guh := setupGetUserHandler(t)
+
+	err := SetValidator(guh.handler.Update)(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "last_name", problem.Errors[0].Field)
+	assert.Equal(t, "alpha", problem.Errors[0].Tag)
+}
+
+func TestUpdateUser_Stale(t *testing.T) {
+	ctx, _ := newUpdateUserContext(`"3"`, `{"first_name": "Newname"}`)
+
+	guh := setupGetUserHandler(t)
+	guh.service.On("UpdateUser", mock.Anything, "1", mock.AnythingOfType("*domain.UserUpdate"), int64(3)).Return(nil, application.ErrStaleUser)
+
+	err := guh.handler.Update(ctx)
+	problem := newProblem(err)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusPreconditionFailed, problem.Status)
+}
+
+func TestDeleteUser_OK(t *testing.T) {
+	ctx, rec := newUserJSONContext(http.MethodDelete, "/v1/user", "")
+
+	guh := setupGetUserHandler(t)
+	guh.service.On("DeleteUser", mock.Anything, "1").Return(nil)
+
+	err := guh.handler.Delete(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestDeleteUser_Error(t *testing.T) {
+	ctx, _ := newUserJSONContext(http.MethodDelete, "/v1/user", "")
+
+	guh := setupGetUserHandler(t)
+	guh.service.On("DeleteUser", mock.Anything, "1").Return(domain.ErrNotFound)
+
+	err := guh.handler.Delete(ctx)
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
 }
@@ -0,0 +1,29 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+)
+
+// Development sink that prints notifications instead of delivering them
+type writerNotifier struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterNotifier(w io.Writer) domain.Notifier {
+	return &writerNotifier{w: w}
+}
+
+func (n *writerNotifier) Notify(ctx context.Context, notification *domain.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	_, err := fmt.Fprintf(n.w, "To: %s\nSubject: %s\n\n%s\n\n", notification.To, notification.Subject, notification.Body)
+
+	return err
+}
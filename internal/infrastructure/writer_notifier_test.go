@@ -0,0 +1,32 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+type FailWrite struct{}
+
+func (*FailWrite) Write(p []byte) (n int, err error) {
+	return 0, assert.AnError
+}
+
+func TestNotify_OK(t *testing.T) {
+	output := new(bytes.Buffer)
+
+	err := NewWriterNotifier(output).Notify(context.TODO(), &domain.Notification{To: "an@email.com", Subject: "Subject", Body: "Body"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "To: an@email.com\nSubject: Subject\n\nBody\n\n", output.String())
+}
+
+func TestNotify_WriteError(t *testing.T) {
+	err := NewWriterNotifier(&FailWrite{}).Notify(context.TODO(), &domain.Notification{})
+
+	assert.Error(t, err)
+	assert.EqualError(t, err, assert.AnError.Error())
+}
@@ -15,22 +15,24 @@ type AuthService struct {
 }
 
 // Login provides a mock function with given fields: ctx, email, password
-func (_m *AuthService) Login(ctx context.Context, email string, password string) (string, error) {
+func (_m *AuthService) Login(ctx context.Context, email string, password string) (*domain.LoginResult, error) {
 	ret := _m.Called(ctx, email, password)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Login")
 	}
 
-	var r0 string
+	var r0 *domain.LoginResult
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.LoginResult, error)); ok {
 		return rf(ctx, email, password)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.LoginResult); ok {
 		r0 = rf(ctx, email, password)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.LoginResult)
+		}
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
@@ -42,6 +44,84 @@ func (_m *AuthService) Login(ctx context.Context, email string, password string)
 	return r0, r1
 }
 
+// LoginMFA provides a mock function with given fields: ctx, challengeToken, code
+func (_m *AuthService) LoginMFA(ctx context.Context, challengeToken string, code string) (*domain.TokenPair, error) {
+	ret := _m.Called(ctx, challengeToken, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LoginMFA")
+	}
+
+	var r0 *domain.TokenPair
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.TokenPair, error)); ok {
+		return rf(ctx, challengeToken, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.TokenPair); ok {
+		r0 = rf(ctx, challengeToken, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TokenPair)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, challengeToken, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Logout provides a mock function with given fields: ctx, token, refreshToken
+func (_m *AuthService) Logout(ctx context.Context, token *domain.AccessToken, refreshToken string) error {
+	ret := _m.Called(ctx, token, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.AccessToken, string) error); ok {
+		r0 = rf(ctx, token, refreshToken)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Refresh provides a mock function with given fields: ctx, refreshToken
+func (_m *AuthService) Refresh(ctx context.Context, refreshToken string) (*domain.TokenPair, error) {
+	ret := _m.Called(ctx, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Refresh")
+	}
+
+	var r0 *domain.TokenPair
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.TokenPair, error)); ok {
+		return rf(ctx, refreshToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.TokenPair); ok {
+		r0 = rf(ctx, refreshToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TokenPair)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, refreshToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Signin provides a mock function with given fields: ctx, user
 func (_m *AuthService) Signin(ctx context.Context, user *domain.User) error {
 	ret := _m.Called(ctx, user)
@@ -0,0 +1,155 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/christhianjesus/crabi-challenge/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ComplianceService is an autogenerated mock type for the ComplianceService type
+type ComplianceService struct {
+	mock.Mock
+}
+
+// ApproveReview provides a mock function with given fields: ctx, userID, comment
+func (_m *ComplianceService) ApproveReview(ctx context.Context, userID string, comment string) error {
+	ret := _m.Called(ctx, userID, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApproveReview")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, comment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetReview provides a mock function with given fields: ctx, userID
+func (_m *ComplianceService) GetReview(ctx context.Context, userID string) (*domain.ReviewCase, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReview")
+	}
+
+	var r0 *domain.ReviewCase
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.ReviewCase, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.ReviewCase); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ReviewCase)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListReviews provides a mock function with given fields: ctx, cursor, limit
+func (_m *ComplianceService) ListReviews(ctx context.Context, cursor string, limit int) (*domain.UserPage, error) {
+	ret := _m.Called(ctx, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListReviews")
+	}
+
+	var r0 *domain.UserPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (*domain.UserPage, error)); ok {
+		return rf(ctx, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) *domain.UserPage); ok {
+		r0 = rf(ctx, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListScreenings provides a mock function with given fields: ctx, query
+func (_m *ComplianceService) ListScreenings(ctx context.Context, query *domain.ScreeningQuery) (*domain.ScreeningPage, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListScreenings")
+	}
+
+	var r0 *domain.ScreeningPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ScreeningQuery) (*domain.ScreeningPage, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ScreeningQuery) *domain.ScreeningPage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ScreeningPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.ScreeningQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RejectReview provides a mock function with given fields: ctx, userID, comment
+func (_m *ComplianceService) RejectReview(ctx context.Context, userID string, comment string) error {
+	ret := _m.Called(ctx, userID, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RejectReview")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, comment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewComplianceService creates a new instance of ComplianceService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewComplianceService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ComplianceService {
+	mock := &ComplianceService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,155 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/christhianjesus/crabi-challenge/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OneTimeTokenRepository is an autogenerated mock type for the OneTimeTokenRepository type
+type OneTimeTokenRepository struct {
+	mock.Mock
+}
+
+// ConsumeOneTimeToken provides a mock function with given fields: ctx, tokenHash, purpose
+func (_m *OneTimeTokenRepository) ConsumeOneTimeToken(ctx context.Context, tokenHash string, purpose string) (*domain.OneTimeToken, error) {
+	ret := _m.Called(ctx, tokenHash, purpose)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeOneTimeToken")
+	}
+
+	var r0 *domain.OneTimeToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.OneTimeToken, error)); ok {
+		return rf(ctx, tokenHash, purpose)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.OneTimeToken); ok {
+		r0 = rf(ctx, tokenHash, purpose)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OneTimeToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, tokenHash, purpose)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateOneTimeToken provides a mock function with given fields: ctx, token
+func (_m *OneTimeTokenRepository) CreateOneTimeToken(ctx context.Context, token *domain.OneTimeToken) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateOneTimeToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.OneTimeToken) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteOneTimeTokens provides a mock function with given fields: ctx, userID, purpose
+func (_m *OneTimeTokenRepository) DeleteOneTimeTokens(ctx context.Context, userID string, purpose string) error {
+	ret := _m.Called(ctx, userID, purpose)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteOneTimeTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, purpose)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetLatestOneTimeToken provides a mock function with given fields: ctx, userID, purpose
+func (_m *OneTimeTokenRepository) GetLatestOneTimeToken(ctx context.Context, userID string, purpose string) (*domain.OneTimeToken, error) {
+	ret := _m.Called(ctx, userID, purpose)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestOneTimeToken")
+	}
+
+	var r0 *domain.OneTimeToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.OneTimeToken, error)); ok {
+		return rf(ctx, userID, purpose)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.OneTimeToken); ok {
+		r0 = rf(ctx, userID, purpose)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OneTimeToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, purpose)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOneTimeToken provides a mock function with given fields: ctx, tokenHash, purpose
+func (_m *OneTimeTokenRepository) GetOneTimeToken(ctx context.Context, tokenHash string, purpose string) (*domain.OneTimeToken, error) {
+	ret := _m.Called(ctx, tokenHash, purpose)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOneTimeToken")
+	}
+
+	var r0 *domain.OneTimeToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.OneTimeToken, error)); ok {
+		return rf(ctx, tokenHash, purpose)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.OneTimeToken); ok {
+		r0 = rf(ctx, tokenHash, purpose)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OneTimeToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, tokenHash, purpose)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewOneTimeTokenRepository creates a new instance of OneTimeTokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOneTimeTokenRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OneTimeTokenRepository {
+	mock := &OneTimeTokenRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,47 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/christhianjesus/crabi-challenge/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PasswordPolicyService is an autogenerated mock type for the PasswordPolicyService type
+type PasswordPolicyService struct {
+	mock.Mock
+}
+
+// CheckPassword provides a mock function with given fields: ctx, password, user
+func (_m *PasswordPolicyService) CheckPassword(ctx context.Context, password string, user *domain.User) error {
+	ret := _m.Called(ctx, password, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.User) error); ok {
+		r0 = rf(ctx, password, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPasswordPolicyService creates a new instance of PasswordPolicyService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPasswordPolicyService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PasswordPolicyService {
+	mock := &PasswordPolicyService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -14,23 +14,43 @@ type PLDRepository struct {
 	mock.Mock
 }
 
-// IsValidUser provides a mock function with given fields: ctx, user
-func (_m *PLDRepository) IsValidUser(ctx context.Context, user *domain.User) (bool, error) {
+// Provider provides a mock function with no fields
+func (_m *PLDRepository) Provider() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Provider")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// ScreenUser provides a mock function with given fields: ctx, user
+func (_m *PLDRepository) ScreenUser(ctx context.Context, user *domain.User) (*domain.ScreeningResult, error) {
 	ret := _m.Called(ctx, user)
 
 	if len(ret) == 0 {
-		panic("no return value specified for IsValidUser")
+		panic("no return value specified for ScreenUser")
 	}
 
-	var r0 bool
+	var r0 *domain.ScreeningResult
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) (bool, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) (*domain.ScreeningResult, error)); ok {
 		return rf(ctx, user)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) bool); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) *domain.ScreeningResult); ok {
 		r0 = rf(ctx, user)
 	} else {
-		r0 = ret.Get(0).(bool)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ScreeningResult)
+		}
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, *domain.User) error); ok {
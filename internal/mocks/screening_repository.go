@@ -0,0 +1,77 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/christhianjesus/crabi-challenge/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ScreeningRepository is an autogenerated mock type for the ScreeningRepository type
+type ScreeningRepository struct {
+	mock.Mock
+}
+
+// ListScreenings provides a mock function with given fields: ctx, query
+func (_m *ScreeningRepository) ListScreenings(ctx context.Context, query *domain.ScreeningQuery) (*domain.ScreeningPage, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListScreenings")
+	}
+
+	var r0 *domain.ScreeningPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ScreeningQuery) (*domain.ScreeningPage, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ScreeningQuery) *domain.ScreeningPage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ScreeningPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.ScreeningQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveScreening provides a mock function with given fields: ctx, record
+func (_m *ScreeningRepository) SaveScreening(ctx context.Context, record *domain.ScreeningRecord) error {
+	ret := _m.Called(ctx, record)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveScreening")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ScreeningRecord) error); ok {
+		r0 = rf(ctx, record)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewScreeningRepository creates a new instance of ScreeningRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewScreeningRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ScreeningRepository {
+	mock := &ScreeningRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,55 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/christhianjesus/crabi-challenge/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TokenSigner is an autogenerated mock type for the TokenSigner type
+type TokenSigner struct {
+	mock.Mock
+}
+
+// Sign provides a mock function with given fields: token
+func (_m *TokenSigner) Sign(token *domain.AccessToken) (string, error) {
+	ret := _m.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Sign")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*domain.AccessToken) (string, error)); ok {
+		return rf(token)
+	}
+	if rf, ok := ret.Get(0).(func(*domain.AccessToken) string); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*domain.AccessToken) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTokenSigner creates a new instance of TokenSigner. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTokenSigner(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TokenSigner {
+	mock := &TokenSigner{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
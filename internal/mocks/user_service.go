@@ -32,6 +32,24 @@ func (_m *UserService) CreateUser(ctx context.Context, user *domain.User) error
 	return r0
 }
 
+// DeleteUser provides a mock function with given fields: ctx, userID
+func (_m *UserService) DeleteUser(ctx context.Context, userID string) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetUser provides a mock function with given fields: ctx, userID
 func (_m *UserService) GetUser(ctx context.Context, userID string) (*domain.User, error) {
 	ret := _m.Called(ctx, userID)
@@ -62,6 +80,158 @@ func (_m *UserService) GetUser(ctx context.Context, userID string) (*domain.User
 	return r0, r1
 }
 
+// GrantRole provides a mock function with given fields: ctx, userID, role
+func (_m *UserService) GrantRole(ctx context.Context, userID string, role domain.Role) error {
+	ret := _m.Called(ctx, userID, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GrantRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Role) error); ok {
+		r0 = rf(ctx, userID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListUsers provides a mock function with given fields: ctx, query, withTotal
+func (_m *UserService) ListUsers(ctx context.Context, query *domain.UserQuery, withTotal bool) (*domain.UserPage, error) {
+	ret := _m.Called(ctx, query, withTotal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUsers")
+	}
+
+	var r0 *domain.UserPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.UserQuery, bool) (*domain.UserPage, error)); ok {
+		return rf(ctx, query, withTotal)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.UserQuery, bool) *domain.UserPage); ok {
+		r0 = rf(ctx, query, withTotal)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.UserQuery, bool) error); ok {
+		r1 = rf(ctx, query, withTotal)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeDeletedUsers provides a mock function with given fields: ctx
+func (_m *UserService) PurgeDeletedUsers(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeDeletedUsers")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RetryScreenings provides a mock function with given fields: ctx
+func (_m *UserService) RetryScreenings(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RetryScreenings")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeRole provides a mock function with given fields: ctx, userID, role
+func (_m *UserService) RevokeRole(ctx context.Context, userID string, role domain.Role) error {
+	ret := _m.Called(ctx, userID, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Role) error); ok {
+		r0 = rf(ctx, userID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUser provides a mock function with given fields: ctx, userID, update, version
+func (_m *UserService) UpdateUser(ctx context.Context, userID string, update *domain.UserUpdate, version int64) (*domain.User, error) {
+	ret := _m.Called(ctx, userID, update, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUser")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.UserUpdate, int64) (*domain.User, error)); ok {
+		return rf(ctx, userID, update, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.UserUpdate, int64) *domain.User); ok {
+		r0 = rf(ctx, userID, update, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.UserUpdate, int64) error); ok {
+		r1 = rf(ctx, userID, update, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewUserService creates a new instance of UserService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserService(t interface {
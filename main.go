@@ -2,11 +2,14 @@ package main
 
 import (
 	"context"
+	"expvar"
 	"log"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/christhianjesus/crabi-challenge/internal/application"
+	"github.com/christhianjesus/crabi-challenge/internal/domain"
 	"github.com/christhianjesus/crabi-challenge/internal/infrastructure"
 	echojwt "github.com/labstack/echo-jwt/v4"
 	"github.com/labstack/echo/v4"
@@ -32,30 +35,98 @@ func main() {
 		}
 	}()
 
+	db := mongoClient.Database("default")
+
+	if len(c.GetJwtKey()) == 0 && c.GetJwtSigningKeyFile() == "" {
+		log.Fatal("JWT_KEY or JWT_SIGNING_KEY_FILE must be set")
+	}
+
+	jwtKeys := infrastructure.NewHMACKeySet(c.GetJwtKey())
+	if c.GetJwtSigningKeyFile() != "" {
+		if jwtKeys, err = infrastructure.LoadJWTKeySet(c.GetJwtSigningKeyFile(), c.GetJwtVerificationKeyFiles()); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	jwtManager := infrastructure.NewJWTManager(jwtKeys, c.GetJwtIssuer(), c.GetJwtAudience())
+
+	notificationOutput := os.Stdout
+	if c.GetNotificationFile() != "" {
+		if notificationOutput, err = os.OpenFile(c.GetNotificationFile(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600); err != nil {
+			log.Fatal(err)
+		}
+
+		defer notificationOutput.Close()
+	}
+
+	notifier := infrastructure.NewWriterNotifier(notificationOutput)
+
+	// hashing is CPU bound, bursts of logins must not starve the other routes
+	passwordHasher := infrastructure.NewHashingPool(infrastructure.NewPasswordHasher(c.GetPasswordHashPolicy()), c.GetHashingConcurrency(), c.GetHashingQueueDepth(), c.GetHashingRetryAfter())
+	expvar.Publish("password_hashing", expvar.Func(func() any { return passwordHasher.Stats() }))
+
+	breachedPasswordRepository := infrastructure.NewBreachedPasswordRepository()
+	if c.GetBreachedPasswordsDir() != "" {
+		if breachedPasswordRepository, err = infrastructure.OpenBreachedPasswordRepository(c.GetBreachedPasswordsDir()); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	// Repositories
-	mongoUserRepository := infrastructure.NewMongoUserRepository(mongoClient.Database("default"))
-	pldRepository := infrastructure.NewPLDRepository(http.DefaultClient, c.GetPLDURL())
+	mongoUserRepository := infrastructure.NewMongoUserRepository(db)
+	refreshTokenRepository := infrastructure.NewMongoRefreshTokenRepository(db)
+	revokedTokenRepository := infrastructure.NewMongoRevokedTokenRepository(db)
+	sessionRepository := infrastructure.NewMongoSessionRepository(db)
+	oneTimeTokenRepository := infrastructure.NewMongoOneTimeTokenRepository(db)
+	mfaRepository := infrastructure.NewMongoMFARepository(db)
+	loginAttemptRepository := infrastructure.NewMongoLoginAttemptRepository(db)
+	auditRepository := infrastructure.NewMongoAuditRepository(db)
+	screeningRepository := infrastructure.NewMongoScreeningRepository(db)
+	pldRepository := infrastructure.NewPLDRepository(&http.Client{Timeout: c.GetPLDTimeout()}, c.GetPLDURL(), c.GetPLDPolicy())
 
 	// Services
-	userService := application.NewUserService(mongoUserRepository, pldRepository)
-	authService := application.NewAuthService(mongoUserRepository, userService)
+	passwordPolicyService := application.NewPasswordPolicyService(breachedPasswordRepository, c.GetPasswordPolicy())
+	tokenService := application.NewTokenService(jwtManager, refreshTokenRepository, revokedTokenRepository, sessionRepository, mongoUserRepository, c.GetUnverifiedEmailPolicy(), c.GetAccessTokenTTL(), c.GetRefreshTokenTTL())
+	userService := application.NewUserService(mongoUserRepository, pldRepository, screeningRepository, auditRepository, mfaRepository, sessionRepository, loginAttemptRepository, tokenService, c.GetDeletionPolicy(), c.GetScreeningPolicy())
+	verificationService := application.NewEmailVerificationService(mongoUserRepository, mongoUserRepository, oneTimeTokenRepository, notifier, c.GetVerificationTokenTTL(), c.GetVerificationResendInterval())
+	mfaService := application.NewMFAService(mfaRepository, mongoUserRepository, oneTimeTokenRepository, c.GetTOTPIssuer(), c.GetMFAChallengeTTL())
+	lockoutService := application.NewLockoutService(loginAttemptRepository, c.GetLockoutPolicy())
+	authService := application.NewAuthService(mongoUserRepository, passwordHasher, userService, tokenService, verificationService, mfaService, lockoutService, notifier, passwordPolicyService)
+	passwordService := application.NewPasswordService(mongoUserRepository, passwordHasher, mongoUserRepository, oneTimeTokenRepository, notifier, tokenService, passwordPolicyService, c.GetResetTokenTTL(), c.GetPasswordHistorySize())
+	sessionService := application.NewSessionService(sessionRepository, tokenService)
+	complianceService := application.NewComplianceService(screeningRepository, mongoUserRepository, auditRepository, tokenService)
 
 	// Handlers
 	userHandler := infrastructure.NewUserHandler(userService)
-	authHandler := infrastructure.NewAuthHandler(authService, c.jwtKey)
+	authHandler := infrastructure.NewAuthHandler(authService)
+	jwksHandler := infrastructure.NewJWKSHandler(jwtKeys)
+	passwordHandler := infrastructure.NewPasswordHandler(passwordService)
+	verificationHandler := infrastructure.NewEmailVerificationHandler(verificationService)
+	mfaHandler := infrastructure.NewMFAHandler(mfaService)
+	adminHandler := infrastructure.NewAdminHandler(lockoutService, userService)
+	sessionHandler := infrastructure.NewSessionHandler(sessionService)
+	complianceHandler := infrastructure.NewComplianceHandler(complianceService)
 
 	// Middlewares
 	jwtMiddleware := echojwt.WithConfig(echojwt.Config{
-		SuccessHandler: infrastructure.SetUserID,
-		SigningKey:     c.GetJwtKey(),
+		ParseTokenFunc: jwtManager.ParseToken,
 	})
 
 	e := echo.New()
+	e.HTTPErrorHandler = infrastructure.HTTPErrorHandler
+
+	// X-Forwarded-For can be forged unless a trusted proxy sets it
+	e.IPExtractor = echo.ExtractIPDirect()
+	if c.GetTrustProxyHeaders() {
+		e.IPExtractor = echo.ExtractIPFromXFFHeader()
+	}
 
 	// Root level middleware
+	e.Use(middleware.RequestID())
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
 	e.Use(infrastructure.SetValidator)
+	e.Use(infrastructure.SetClientInfo)
 
 	// Health
 	e.GET("/health", func(c echo.Context) error {
@@ -63,14 +134,70 @@ func main() {
 	})
 
 	// Auth routes
+	e.GET("/.well-known/jwks.json", jwksHandler.Get)
 	e.POST("/signin", authHandler.Signin)
 	e.POST("/login", authHandler.Login)
+	e.POST("/login/mfa", authHandler.LoginMFA)
+	e.POST("/token/refresh", authHandler.Refresh)
+	e.POST("/password/forgot", passwordHandler.Forgot)
+	e.POST("/password/reset", passwordHandler.Reset)
+	e.POST("/email/verify", verificationHandler.Verify)
+	e.POST("/email/verify/resend", verificationHandler.Resend)
 
-	// versioning endpoints
-	v1 := e.Group("/v1", jwtMiddleware)
+	// versioning endpoints, restricted tokens can only see the account and end sessions until the email is verified
+	restrictedRoutes := []string{"GET /v1/user", "POST /v1/logout", "GET /v1/sessions", "DELETE /v1/sessions", "DELETE /v1/sessions/:id"}
+	v1 := e.Group("/v1", jwtMiddleware, infrastructure.SetPrincipal, infrastructure.RejectRevokedTokens(tokenService), infrastructure.AllowRestrictedTokens(restrictedRoutes...))
 
 	// App routes
+	v1.POST("/logout", authHandler.Logout)
 	v1.GET("/user", userHandler.Get)
+	v1.PATCH("/user", userHandler.Update)
+	v1.DELETE("/user", userHandler.Delete)
+	v1.PUT("/user/password", passwordHandler.Change)
+
+	// Session routes, deleting all of them logs out everywhere
+	v1.GET("/sessions", sessionHandler.List)
+	v1.DELETE("/sessions", sessionHandler.RevokeAll)
+	v1.DELETE("/sessions/:id", sessionHandler.Revoke)
+
+	// MFA routes
+	mfa := v1.Group("/mfa")
+	mfa.POST("/totp", mfaHandler.Enroll)
+	mfa.POST("/totp/confirm", mfaHandler.Confirm)
+	mfa.DELETE("/totp", mfaHandler.Disable)
+
+	// Admin routes, each one guarded by the permission it needs
+	admin := v1.Group("/admin")
+	admin.GET("/users", adminHandler.ListUsers, infrastructure.RequirePermission(domain.PermissionReadUsers))
+	admin.POST("/users/unlock", adminHandler.Unlock, infrastructure.RequirePermission(domain.PermissionUnlockUsers))
+	admin.PUT("/users/:id/roles/:role", adminHandler.GrantRole, infrastructure.RequirePermission(domain.PermissionManageRoles))
+	admin.DELETE("/users/:id/roles/:role", adminHandler.RevokeRole, infrastructure.RequirePermission(domain.PermissionManageRoles))
+	// expvar also publishes the command line and memory stats
+	admin.GET("/debug/vars", echo.WrapHandler(expvar.Handler()), infrastructure.RequirePermission(domain.PermissionReadMetrics))
+
+	// Compliance routes
+	compliance := v1.Group("/compliance", infrastructure.RequirePermission(domain.PermissionCompliance))
+	compliance.GET("/screenings", complianceHandler.ListScreenings)
+	compliance.GET("/reviews", complianceHandler.ListReviews)
+	compliance.GET("/reviews/:id", complianceHandler.GetReview)
+	compliance.POST("/reviews/:id/approve", complianceHandler.ApproveReview)
+	compliance.POST("/reviews/:id/reject", complianceHandler.RejectReview)
+
+	// Users in ADMIN_USER_IDS are made admins, so there is someone to grant roles
+	for _, adminID := range c.GetAdminUserIDs() {
+		grantCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := mongoUserRepository.AddRole(grantCtx, adminID, domain.RoleAdmin); err != nil {
+			e.Logger.Warnf("could not grant the admin role to %s: %v", adminID, err)
+		}
+		cancel()
+	}
+
+	// Background jobs
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	go infrastructure.NewPeriodicJob(userService.PurgeDeletedUsers, c.GetPurgeInterval(), "purged %d deleted users", e.Logger).Run(jobCtx)
+	go infrastructure.NewPeriodicJob(userService.RetryScreenings, c.GetScreeningRetryInterval(), "screened %d users created during a PLD outage", e.Logger).Run(jobCtx)
 
 	e.Logger.Fatal(e.Start(":" + c.GetHttpPort()))
 }